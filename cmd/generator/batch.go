@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+
+	"aaa-generator/internal/console"
+	"aaa-generator/internal/template"
+	"github.com/spf13/cobra"
+)
+
+func newBatchCommand() *cobra.Command {
+	var (
+		force     bool
+		quietPost bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "batch <file>",
+		Short: "Generate every project listed in a batch YAML file without prompting",
+		Long: `Generate every project listed in a batch YAML file, for example:
+
+  projects:
+    - name: svc-users
+      template: basic
+      vars:
+        Port: "8081"
+    - name: svc-orders
+
+Projects are generated in order with --no-input semantics; a failure is
+reported and the remaining projects are still generated.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			batch, err := template.LoadBatchFile(workPath(args[0]))
+			if err != nil {
+				return err
+			}
+
+			manager, err := template.NewManager()
+			if err != nil {
+				return fmt.Errorf("error initializing template manager: %w", err)
+			}
+
+			status := console.NewPrinter(cmd.OutOrStdout())
+			manager.Status = status
+			if err := checkEnvironment(status); err != nil {
+				return err
+			}
+
+			failures := make(map[string]error)
+			for _, project := range batch.Projects {
+				status.Println()
+				status.Printf("🚀 Creating project '%s' using template '%s'\n", project.Name, project.Template)
+				status.Println("───────────────────────────────────────────────────────")
+
+				generator := template.NewGenerator(manager)
+				generator.Version = version
+				generator.WorkDir = workingDir
+				generator.Status = status
+				generator.NoInput = true
+				generator.Force = force
+				generator.QuietPost = quietPost
+				generator.Values = project.Vars
+
+				if _, err := generator.Generate(project.Name, project.Template); err != nil {
+					status.Printf(console.Failure("❌ %s: %v\n"), project.Name, err)
+					failures[project.Name] = err
+				}
+			}
+
+			status.Println()
+			status.Println("📋 Batch summary:")
+			for _, project := range batch.Projects {
+				if err, failed := failures[project.Name]; failed {
+					status.Printf(console.Failure("   ❌ %s (%s): %v\n"), project.Name, project.Template, err)
+				} else {
+					status.Printf(console.Success("   ✅ %s (%s)\n"), project.Name, project.Template)
+				}
+			}
+
+			if len(failures) > 0 {
+				return fmt.Errorf("failed to generate %d of %d project(s)", len(failures), len(batch.Projects))
+			}
+			status.Printf(console.Success("✅ Generated %d project(s)\n"), len(batch.Projects))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Regenerate into existing project directories")
+	cmd.Flags().BoolVar(&quietPost, "quiet-post", false, "Only show post-generate command output when a command fails")
+	return cmd
+}
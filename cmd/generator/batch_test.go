@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBatchCommand(t *testing.T) {
+	templates := map[string]map[string]string{
+		"svc": {
+			"template.yaml": "name: svc\nvariables:\n  - name: Port\n    required: true\n",
+			"port.txt.tmpl": "{{ .Port }}\n",
+		},
+		"db": {
+			"template.yaml": "name: db\nvariables:\n  - name: Driver\n    type: select\n    optionsFrom: \"printf 'postgres\\\\nmysql\\\\n'\"\n",
+			"port.txt.tmpl": "{{ .Driver }}\n",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		batch   string
+		wantErr string
+		// wantFailed 為批次摘要中應列為失敗的項目
+		wantFailed string
+		wantPorts  map[string]string
+	}{
+		{
+			name:      "all projects generated",
+			batch:     "projects:\n  - name: users\n    template: svc\n    vars: {Port: \"8081\"}\n  - name: orders\n    template: svc\n    vars: {Port: \"8082\"}\n",
+			wantPorts: map[string]string{"users": "8081", "orders": "8082"},
+		},
+		{
+			name:       "a failure does not stop the batch",
+			batch:      "projects:\n  - name: users\n    template: svc\n  - name: orders\n    template: svc\n    vars: {Port: \"8082\"}\n",
+			wantErr:    "failed to generate 1 of 2 project(s)",
+			wantFailed: "❌ users (svc)",
+			wantPorts:  map[string]string{"orders": "8082"},
+		},
+		{
+			// optionsFrom 的命令照常執行，以驗證批次檔提供的值
+			name:       "optionsFrom values are validated",
+			batch:      "projects:\n  - name: good\n    template: db\n    vars: {Driver: mysql}\n  - name: bad\n    template: db\n    vars: {Driver: sqlite}\n",
+			wantErr:    "failed to generate 1 of 2 project(s)",
+			wantFailed: "❌ bad (db)",
+			wantPorts:  map[string]string{"good": "mysql"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			writeTestFile(t, filepath.Join(dir, "batch.yaml"), tt.batch, 0o644)
+
+			stdout, _, err := runCLI(t, dir, "batch", "batch.yaml")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if !strings.Contains(stdout, tt.wantFailed) {
+					t.Errorf("summary does not report the failed project:\n%s", stdout)
+				}
+			} else if err != nil {
+				t.Fatalf("error = %v\n%s", err, stdout)
+			}
+
+			for project, want := range tt.wantPorts {
+				data, err := os.ReadFile(filepath.Join(dir, project, "port.txt"))
+				if err != nil {
+					t.Fatalf("project %s was not generated: %v", project, err)
+				}
+				if got := strings.TrimSpace(string(data)); got != want {
+					t.Errorf("%s Port = %q, want %q", project, got, want)
+				}
+			}
+		})
+	}
+}
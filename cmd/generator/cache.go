@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"aaa-generator/internal/console"
+	"aaa-generator/internal/template"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "List or clear cached remote template downloads",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Show cached sources and their sizes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := template.ListCache()
+			if err != nil {
+				return err
+			}
+			printCacheEntries(console.NewPrinter(cmd.OutOrStdout()), entries)
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear [source]",
+		Short: "Remove every cached download, or only the one for source",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := ""
+			if len(args) == 1 {
+				source = args[0]
+			}
+			removed, err := template.ClearCache(source)
+			if err != nil {
+				return err
+			}
+			console.NewPrinter(cmd.OutOrStdout()).Printf(console.Success("✅ Removed %d cached download(s)\n"), removed)
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+// printCacheEntries 列出每筆快取的來源、大小與下載時間
+func printCacheEntries(out io.Writer, entries []template.CacheEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "📭 The template cache is empty.")
+		return
+	}
+
+	fmt.Fprintln(out, "🗄️  Cached templates:")
+	fmt.Fprintln(out, "───────────────────────────────────────────────────────")
+	var total int64
+	for _, entry := range entries {
+		fmt.Fprintf(out, "📦 %s\n", entry.Source)
+		fmt.Fprintf(out, "   %s | cached %s\n", formatBytes(entry.Size), entry.CachedAt.Format("2006-01-02 15:04"))
+		total += entry.Size
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "📊 %d download(s), %s total\n", len(entries), formatBytes(total))
+}
+
+// formatBytes 以 B、KB、MB 等單位顯示大小
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"aaa-generator/internal/template"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0 B"},
+		{size: 1023, want: "1023 B"},
+		{size: 1024, want: "1.0 KB"},
+		{size: 1536, want: "1.5 KB"},
+		{size: 5 * 1024 * 1024, want: "5.0 MB"},
+		{size: 3 * 1024 * 1024 * 1024, want: "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatBytes(tt.size); got != tt.want {
+				t.Errorf("formatBytes(%d) = %q, want %q", tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintCacheEntries(t *testing.T) {
+	cachedAt := time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		entries []template.CacheEntry
+		want    []string
+	}{
+		{name: "empty", want: []string{"The template cache is empty."}},
+		{
+			name: "entries and total",
+			entries: []template.CacheEntry{
+				{Source: "oci://ghcr.io/me/a@sha256:aaa", Size: 1024, CachedAt: cachedAt},
+				{Source: "oci://ghcr.io/me/b@sha256:bbb", Size: 2048, CachedAt: cachedAt},
+			},
+			want: []string{"📦 oci://ghcr.io/me/a@sha256:aaa", "1.0 KB | cached 2026-01-02 15:04", "2 download(s), 3.0 KB total"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printCacheEntries(&out, tt.entries)
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
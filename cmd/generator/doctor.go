@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"aaa-generator/internal/console"
+	"aaa-generator/internal/template"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Report tool versions, template directories and template load problems",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := template.NewManager()
+			if err != nil {
+				return fmt.Errorf("error initializing template manager: %w", err)
+			}
+
+			printDoctorReport(console.NewPrinter(cmd.OutOrStdout()), manager)
+			return nil
+		},
+	}
+}
+
+// printDoctorReport 彙整環境與模板管理器的狀態，方便回報問題時附上
+func printDoctorReport(out io.Writer, manager *template.Manager) {
+	fmt.Fprintf(out, "🩺 AAA-Generator v%s\n", version)
+	fmt.Fprintln(out, "───────────────────────────────────────────────────────")
+
+	fmt.Fprintln(out, "🔧 Tools:")
+	printToolVersion(out, "go", "version")
+	printToolVersion(out, "node", "--version")
+	printToolVersion(out, "git", "--version")
+	if _, err := exec.LookPath("git"); err != nil {
+		fmt.Fprintln(out, console.Warning("   ⚠️  git is required for remote template installs"))
+	}
+
+	fmt.Fprintln(out, "📁 Directories:")
+	printDoctorPath(out, "templates", template.UserTemplatesDir)
+	printDoctorPath(out, "config", template.ConfigDir)
+	printDoctorPath(out, "cache", template.CacheDir)
+
+	builtin, user := 0, 0
+	for _, tmpl := range manager.ListTemplates() {
+		if tmpl.Source == "user" {
+			user++
+		} else {
+			builtin++
+		}
+	}
+	fmt.Fprintln(out, "📦 Templates:")
+	fmt.Fprintf(out, "   • built-in: %d\n", builtin)
+	fmt.Fprintf(out, "   • user: %d\n", user)
+
+	for _, conflict := range manager.Conflicts() {
+		fmt.Fprintf(out, console.Warning("⚠️  %s\n"), conflict)
+	}
+
+	warnings := manager.LoadWarnings()
+	if len(warnings) == 0 {
+		fmt.Fprintln(out, console.Success("✅ All templates loaded"))
+		return
+	}
+	fmt.Fprintf(out, console.Warning("⚠️  %d problem(s) while loading templates:\n"), len(warnings))
+	for _, warning := range warnings {
+		fmt.Fprintf(out, "   • %s\n", warning)
+	}
+}
+
+func printToolVersion(out io.Writer, name string, versionArg string) {
+	fmt.Fprintf(out, "   • %s: ", name)
+	if _, err := exec.LookPath(name); err != nil {
+		fmt.Fprintln(out, console.Failure("❌ not found in PATH"))
+		return
+	}
+
+	output, err := exec.Command(name, versionArg).Output()
+	if err != nil {
+		fmt.Fprintf(out, console.Warning("⚠️  failed to get version: %v\n"), err)
+		return
+	}
+	fmt.Fprintf(out, console.Success("✅ %s\n"), strings.TrimSpace(string(output)))
+}
+
+func printDoctorPath(out io.Writer, label string, resolve func() (string, error)) {
+	dir, err := resolve()
+	if err != nil {
+		fmt.Fprintf(out, console.Failure("   • %s: ❌ %v\n"), label, err)
+		return
+	}
+	fmt.Fprintf(out, "   • %s: %s\n", label, dir)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates map[string]map[string]string
+		tools     map[string]string
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:      "healthy environment",
+			templates: map[string]map[string]string{"mine": {"template.yaml": "name: mine\n"}},
+			tools:     map[string]string{"go": "echo go version go1.24.4", "node": "echo v22.1.0", "git": "echo git version 2.45.0"},
+			want:      []string{"go: ✅ go version go1.24.4", "node: ✅ v22.1.0", "git: ✅ git version 2.45.0", "• built-in: 2", "• user: 1", "✅ All templates loaded"},
+			notWant:   []string{"git is required"},
+		},
+		{
+			name:  "missing tools",
+			tools: map[string]string{"go": "exit 1"},
+			want:  []string{"go: ⚠️  failed to get version", "node: ❌ not found in PATH", "git: ❌ not found in PATH", "git is required for remote template installs", "• user: 0", "✅ All templates loaded"},
+		},
+		{
+			name: "load problems and conflicts",
+			templates: map[string]map[string]string{
+				"broken": {"template.yaml": "name: [\n"},
+				"basic":  {"template.yaml": "name: basic\n"},
+			},
+			want:    []string{"1 problem(s) while loading templates", "Failed to parse config for user template broken", "template 'basic' defined in both user and built-in sources; user wins"},
+			notWant: []string{"All templates loaded"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, tt.templates)
+			// PATH 只保留這裡列出的假工具
+			bin := filepath.Join(t.TempDir(), "bin")
+			for name, script := range tt.tools {
+				writeTestFile(t, filepath.Join(bin, name), "#!/bin/sh\n"+script+"\n", 0o755)
+			}
+			if err := os.MkdirAll(bin, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("PATH", bin)
+			home := os.Getenv("HOME")
+
+			stdout, _, err := runCLI(t, dir, "doctor")
+			if err != nil {
+				t.Fatalf("doctor error = %v", err)
+			}
+			want := append([]string{"templates: " + filepath.Join(home, "data", "aaa-generator", "templates")}, tt.want...)
+			for _, text := range want {
+				if !strings.Contains(stdout, text) {
+					t.Errorf("doctor output should contain %q, got:\n%s", text, stdout)
+				}
+			}
+			for _, text := range tt.notWant {
+				if strings.Contains(stdout, text) {
+					t.Errorf("doctor output should not contain %q, got:\n%s", text, stdout)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"aaa-generator/internal/console"
+	"aaa-generator/internal/template"
+)
+
+// printDryRun 在 out 輸出 dry-run 的檔案清單與 post-generate 命令檢查結果；有問題時回傳錯誤
+func printDryRun(out io.Writer, projectName string, report *template.DryRunReport) error {
+	fmt.Fprintf(out, "📋 Dry run for '%s' using template '%s' (nothing is written or executed)\n", projectName, report.Manifest.Template)
+	fmt.Fprintln(out, "───────────────────────────────────────────────────────")
+	fmt.Fprintf(out, "📁 %d file(s) would be generated:\n", len(report.Manifest.Files))
+	for _, file := range report.Manifest.Files {
+		fmt.Fprintf(out, "   • %s\n", file)
+	}
+
+	if len(report.Commands) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "🔄 Post-generate commands:")
+	}
+	for i, check := range report.Commands {
+		label := fmt.Sprintf("[%d/%d] %s (in %s)", i+1, len(report.Commands), check.Command, check.WorkDir)
+		switch {
+		case check.Skipped:
+			fmt.Fprintf(out, "   • %s — skipped on this OS\n", label)
+		case len(check.Problems) == 0:
+			fmt.Fprintf(out, console.Success("   ✅ %s\n"), label)
+		default:
+			fmt.Fprintf(out, console.Failure("   ❌ %s\n"), label)
+			for _, problem := range check.Problems {
+				fmt.Fprintf(out, "      %s\n", problem)
+			}
+		}
+	}
+	fmt.Fprintln(out, "───────────────────────────────────────────────────────")
+
+	if problems := report.Problems(); problems > 0 {
+		return fmt.Errorf("dry run found %d problem(s) in post-generate commands", problems)
+	}
+	fmt.Fprintln(out, console.Success("✅ Dry run passed"))
+	return nil
+}
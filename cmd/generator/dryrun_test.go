@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aaa-generator/internal/template"
+)
+
+// snapshotTree 記錄 roots 之下每個路徑的類型、權限與內容，用來確認命令沒有寫入任何東西
+func snapshotTree(t *testing.T, roots ...string) map[string]string {
+	t.Helper()
+	snapshot := make(map[string]string)
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			entry := info.Mode().String()
+			if info.Mode().IsRegular() {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				entry += " " + string(data)
+			}
+			snapshot[path] = entry
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return snapshot
+}
+
+func TestDryRunCommand(t *testing.T) {
+	dry := func(command string) map[string]map[string]string {
+		return map[string]map[string]string{
+			"dry": {
+				"template.yaml": "name: dry\npostGenerate:\n  - command: " + command + "\n",
+				"main.go":       "package main\n",
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		templates map[string]map[string]string
+		args      []string
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "passes",
+			templates: dry("go mod tidy"),
+			args:      []string{"-t", "dry"},
+			want:      []string{"1 file(s) would be generated", "• main.go", "✅ [1/1] go mod tidy", "Dry run passed"},
+		},
+		{
+			name:      "reports problems",
+			templates: dry("cargo build"),
+			args:      []string{"-t", "dry"},
+			want:      []string{"❌ [1/1] cargo build", "cargo not found in PATH"},
+			wantErr:   "dry run found 1 problem(s) in post-generate commands",
+		},
+		{
+			// HOME 為空時也不建立用戶模板目錄
+			name: "built-in template in an empty home",
+			args: []string{"-t", "basic"},
+			want: []string{"would be generated"},
+		},
+		{
+			name:      "trace is rejected",
+			templates: dry("go mod tidy"),
+			args:      []string{"-t", "dry", "--trace", "t.log"},
+			wantErr:   "--dry-run cannot be combined with --manifest-only, --summary-json or --trace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, tt.templates)
+			home := os.Getenv("HOME")
+			before := snapshotTree(t, home, dir)
+
+			stdout, _, err := runCLI(t, dir, append([]string{"-n", "app", "--no-input", "--dry-run"}, tt.args...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("error = %v\n%s", err, stdout)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("output missing %q:\n%s", want, stdout)
+				}
+			}
+
+			after := snapshotTree(t, home, dir)
+			for path, entry := range after {
+				if before[path] != entry {
+					t.Errorf("--dry-run wrote %s", path)
+				}
+			}
+			for path := range before {
+				if _, ok := after[path]; !ok {
+					t.Errorf("--dry-run removed %s", path)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "manifest on stdout", args: []string{"--dry-run", "--print-manifest"}},
+		{name: "requires --dry-run", args: []string{"--print-manifest"}, wantErr: "--print-manifest requires --dry-run"},
+		{name: "dry run refuses --summary-json", args: []string{"--dry-run", "--summary-json", "summary.json"}, wantErr: "--dry-run cannot be combined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, map[string]map[string]string{
+				"dry": {"template.yaml": "name: dry\nversion: 1.0.0\n", "main.go": "package main\n"},
+			})
+
+			stdout, stderr, err := runCLI(t, dir, append([]string{"-n", "app", "-t", "dry", "--no-input"}, tt.args...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			var manifest template.Manifest
+			if err := json.Unmarshal([]byte(stdout), &manifest); err != nil {
+				t.Fatalf("stdout is not a manifest: %v\n%s", err, stdout)
+			}
+			if manifest.Template != "dry" || len(manifest.Files) != 1 || manifest.Files[0] != "main.go" {
+				t.Errorf("manifest = %+v", manifest)
+			}
+			if !strings.Contains(stderr, "Dry run passed") {
+				t.Errorf("report not on stderr:\n%s", stderr)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil || len(entries) != 0 {
+				t.Errorf("dry run wrote files: %v, %v", entries, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+
+	"aaa-generator/internal/console"
+	"aaa-generator/internal/template"
+	"github.com/spf13/cobra"
+)
+
+func newExportAllCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export-all <dir>",
+		Short: "Copy every installed user template into dir for backup",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := template.NewManager()
+			if err != nil {
+				return fmt.Errorf("error initializing template manager: %w", err)
+			}
+
+			status := console.NewPrinter(cmd.OutOrStdout())
+			names, err := manager.ExportTemplates(workPath(args[0]))
+			if err != nil {
+				return fmt.Errorf("error exporting templates: %w", err)
+			}
+			if len(names) == 0 {
+				status.Println("ℹ️  No user templates installed, nothing to export")
+				return nil
+			}
+
+			for _, name := range names {
+				status.Printf("   • %s\n", name)
+			}
+			status.Printf(console.Success("✅ Exported %d template(s) to %s\n"), len(names), args[0])
+			return nil
+		},
+	}
+}
+
+func newImportAllCommand() *cobra.Command {
+	var (
+		ifNotPresent bool
+		reinstall    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import-all <dir>",
+		Short: "Install every template directory found in dir (e.g. the output of export-all)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ifNotPresent && reinstall {
+				return fmt.Errorf("--if-not-present and --reinstall cannot be used together")
+			}
+
+			sources, err := template.TemplateDirs(workPath(args[0]))
+			if err != nil {
+				return fmt.Errorf("error reading %s: %w", args[0], err)
+			}
+			if len(sources) == 0 {
+				return fmt.Errorf("no template directories found in %s", args[0])
+			}
+
+			manager, err := template.NewManager()
+			if err != nil {
+				return fmt.Errorf("error initializing template manager: %w", err)
+			}
+			manager.IfNotPresent = ifNotPresent
+			manager.Reinstall = reinstall
+			status := console.NewPrinter(cmd.OutOrStdout())
+			manager.Status = status
+
+			return installTemplates(status, manager, sources)
+		},
+	}
+
+	cmd.Flags().BoolVar(&ifNotPresent, "if-not-present", false, "Skip templates that are already installed")
+	cmd.Flags().BoolVar(&reinstall, "reinstall", false, "Remove an installed template of the same name before installing")
+	return cmd
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	templates := map[string]map[string]string{
+		"api": {"template.yaml": "name: api\n", "main.go": "package main\n"},
+		"web": {"template.yaml": "name: web\n", "index.html": "<html></html>\n"},
+	}
+
+	old := map[string]map[string]string{"api": {"template.yaml": "name: api\n", "main.go": "package old\n"}}
+
+	tests := []struct {
+		name       string
+		importArgs []string
+		preinstall map[string]map[string]string
+		wantAPI    string
+	}{
+		{name: "into an empty machine", wantAPI: "package main\n"},
+		{name: "overwrites an installed template", preinstall: old, wantAPI: "package main\n"},
+		{name: "--if-not-present keeps an installed template", importArgs: []string{"--if-not-present"}, preinstall: old, wantAPI: "package old\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := cliEnv(t, templates)
+			backup := filepath.Join(t.TempDir(), "backup")
+			if stdout, _, err := runCLI(t, source, "export-all", backup); err != nil || !strings.Contains(stdout, "Exported 2 template(s)") {
+				t.Fatalf("export-all error = %v\n%s", err, stdout)
+			}
+
+			target := cliEnv(t, tt.preinstall)
+			if _, _, err := runCLI(t, target, append([]string{"import-all", backup}, tt.importArgs...)...); err != nil {
+				t.Fatalf("import-all error = %v", err)
+			}
+
+			stdout, _, err := runCLI(t, target, "--list-installed", "--json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var entries []listEntry
+			if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+				t.Fatalf("invalid list output: %v\n%s", err, stdout)
+			}
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name)
+			}
+			sort.Strings(names)
+			if strings.Join(names, ",") != "api,web" {
+				t.Errorf("installed %v, want [api web]", names)
+			}
+
+			data, err := os.ReadFile(filepath.Join(os.Getenv("HOME"), "data", "aaa-generator", "templates", "api", "main.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.wantAPI {
+				t.Errorf("installed api/main.go = %q, want %q", data, tt.wantAPI)
+			}
+		})
+	}
+}
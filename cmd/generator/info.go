@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"aaa-generator/internal/console"
+	"aaa-generator/internal/template"
+	"github.com/spf13/cobra"
+)
+
+func newInfoCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "info <name>",
+		Short: "Show a template's variables, file rules and post-generate commands",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := template.NewManager()
+			if err != nil {
+				return fmt.Errorf("error initializing template manager: %w", err)
+			}
+
+			info, err := manager.GetTemplateInfo(args[0])
+			if err != nil {
+				return err
+			}
+			tmpl, err := manager.GetTemplate(args[0])
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printTemplateConfigJSON(cmd.OutOrStdout(), tmpl.Config)
+			}
+			printTemplateInfo(console.NewPrinter(cmd.OutOrStdout()), info, tmpl.Config)
+			return nil
+		},
+	}
+}
+
+// printTemplateConfigJSON 以 --json 輸出完整的 TemplateConfig（含合併後的 imports），鍵名與 template.yaml 相同
+func printTemplateConfigJSON(out io.Writer, config *template.TemplateConfig) error {
+	if config == nil {
+		config = &template.TemplateConfig{}
+	}
+	keyed, err := yamlKeyed(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode template config: %w", err)
+	}
+	return writeJSON(out, keyed)
+}
+
+// printTemplateInfo 輸出單一模板的詳細資訊，協助使用者在產生前判斷模板是否合用
+func printTemplateInfo(out io.Writer, info template.TemplateInfo, config *template.TemplateConfig) {
+	fmt.Fprintf(out, "📦 %s (%s)\n", info.DisplayName, info.Name)
+	fmt.Fprintln(out, "───────────────────────────────────────────────────────")
+	if info.Description != "" {
+		fmt.Fprintf(out, "   %s\n", info.Description)
+	}
+	fmt.Fprintf(out, "   Version: %s | Source: %s\n", info.Version, info.Source)
+	if info.Author != "" {
+		fmt.Fprintf(out, "   Author: %s\n", info.Author)
+	}
+	if info.URL != "" {
+		fmt.Fprintf(out, "   URL: %s\n", info.URL)
+	}
+	if len(info.Tags) > 0 {
+		fmt.Fprintf(out, "   🏷️  %s\n", strings.Join(info.Tags, ", "))
+	}
+	if info.Deprecated {
+		fmt.Fprintln(out, console.Warning("   ⚠️  Deprecated"))
+		if info.DeprecationMessage != "" {
+			fmt.Fprintf(out, "      %s\n", info.DeprecationMessage)
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "📝 Variables:")
+	if len(config.Variables) == 0 {
+		fmt.Fprintln(out, "   (none)")
+	}
+	for _, variable := range config.Variables {
+		varType := variable.Type
+		if varType == "" {
+			varType = "string"
+		}
+		details := []string{varType}
+		if variable.Required {
+			details = append(details, "required")
+		}
+		if variable.RequiredIf != "" {
+			details = append(details, "required if: "+variable.RequiredIf)
+		}
+		if variable.Default != "" {
+			details = append(details, fmt.Sprintf("default: %q", variable.Default))
+		}
+		if len(variable.Options) > 0 {
+			details = append(details, "options: "+strings.Join(variable.Options, ", "))
+		}
+		if variable.OptionsFrom != "" {
+			details = append(details, "options from: "+variable.OptionsFrom)
+		}
+		if variable.OptionsFile != "" {
+			details = append(details, "options file: "+variable.OptionsFile)
+		}
+		fmt.Fprintf(out, "   • %s (%s)\n", variable.Name, strings.Join(details, ", "))
+		if variable.Description != "" {
+			fmt.Fprintf(out, "     %s\n", variable.Description)
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "📁 File rules:")
+	if len(config.Files) == 0 {
+		fmt.Fprintln(out, "   (none, every file is copied)")
+	}
+	for _, rule := range config.Files {
+		ruleType := rule.Type
+		if ruleType == "" {
+			ruleType = "directory"
+		}
+		target := rule.Target
+		if target == "" {
+			target = "."
+		}
+		fmt.Fprintf(out, "   • %s → %s (%s)", rule.Source, target, ruleType)
+		if len(rule.OS) > 0 {
+			fmt.Fprintf(out, " [os: %s]", strings.Join(rule.OS, ", "))
+		}
+		fmt.Fprintln(out)
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "⚙️  Post-generate commands:")
+	if len(config.PostGenerate) == 0 {
+		fmt.Fprintln(out, "   (none)")
+	}
+	for _, command := range config.PostGenerate {
+		fmt.Fprintf(out, "   • %s", command.Command)
+		if command.WorkDir != "" {
+			fmt.Fprintf(out, " (in %s)", command.WorkDir)
+		}
+		if len(command.OS) > 0 {
+			fmt.Fprintf(out, " [os: %s]", strings.Join(command.OS, ", "))
+		}
+		fmt.Fprintln(out)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"aaa-generator/internal/template"
+)
+
+func TestPrintTemplateInfo(t *testing.T) {
+	config := &template.TemplateConfig{
+		Name:    "demo",
+		Version: "1.2.0",
+		Variables: []template.TemplateVar{
+			{Name: "DB", Type: "select", Options: []string{"sqlite", "postgres"}, Default: "sqlite"},
+			{Name: "Owner", Required: true},
+			{Name: "DBHost", RequiredIf: `{{ ne .DB "sqlite" }}`},
+			{Name: "Region", Type: "select", OptionsFile: "~/regions.txt"},
+			{Name: "Driver", Type: "select", OptionsFrom: "ls drivers"},
+		},
+		Files:        []template.FileRule{{Source: "src", Target: "app", Type: "directory"}},
+		PostGenerate: []template.PostCommand{{Command: "go mod tidy", WorkDir: "app"}},
+	}
+	info := template.TemplateInfo{Name: "demo", DisplayName: "Demo", Version: "1.2.0", Source: "user"}
+
+	tests := []struct {
+		name string
+		json bool
+		want []string
+	}{
+		{
+			name: "formatted",
+			want: []string{
+				"DB (select, default: \"sqlite\", options: sqlite, postgres)",
+				"Owner (string, required)",
+				`required if: {{ ne .DB "sqlite" }}`,
+				"options file: ~/regions.txt",
+				"options from: ls drivers",
+				"src → app (directory)",
+				"go mod tidy (in app)",
+			},
+		},
+		{
+			name: "json",
+			json: true,
+			want: []string{`"name": "demo"`, `"requiredIf": "{{ ne .DB \"sqlite\" }}"`, `"optionsFrom": "ls drivers"`, `"command": "go mod tidy"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if tt.json {
+				if err := printTemplateConfigJSON(&out, config); err != nil {
+					t.Fatal(err)
+				}
+				var decoded map[string]interface{}
+				if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+					t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+				}
+			} else {
+				printTemplateInfo(&out, info, config)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jsonOutput 為 --json：支援的命令改為在 stdout 輸出單一 JSON 文件
+var jsonOutput bool
+
+// writeJSON 將 v 以縮排的 JSON 寫到 w
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// yamlKeyed 經由 YAML 轉換 v（例如 TemplateConfig），讓 JSON 輸出使用與 template.yaml 相同的鍵名
+func yamlKeyed(v interface{}) (interface{}, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var keyed interface{}
+	if err := yaml.Unmarshal(data, &keyed); err != nil {
+		return nil, err
+	}
+	return keyed, nil
+}
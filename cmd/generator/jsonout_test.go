@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aaa-generator/internal/template"
+)
+
+var jsonTemplates = map[string]map[string]string{
+	"plain": {
+		"template.yaml":  "name: plain\nversion: 1.0.0\npostGenerate:\n  - command: \"true\"\n",
+		"README.md.tmpl": "# {{ .ProjectName }}\n",
+		"main.go":        "package main\n",
+	},
+}
+
+func TestJSONOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+		check   func(t *testing.T, stdout, stderr string)
+	}{
+		{
+			name: "generation prints the run summary with counts",
+			args: []string{"-n", "app", "-t", "plain", "--no-input", "--json"},
+			check: func(t *testing.T, stdout, stderr string) {
+				if !strings.Contains(stderr, "Creating project") {
+					t.Errorf("progress output should go to stderr, got:\n%s", stderr)
+				}
+				var summary template.RunSummary
+				if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+					t.Fatalf("stdout is not a run summary: %v\n%s", err, stdout)
+				}
+				want := template.SummaryCounts{Created: 2, CommandsRun: 1}
+				if summary.Template != "plain" || summary.Counts != want {
+					t.Errorf("summary = %+v, want template plain and counts %+v", summary, want)
+				}
+			},
+		},
+		{
+			name: "print-vars adds the variable map",
+			args: []string{"-n", "app", "-t", "plain", "--no-input", "--print-vars", "--json"},
+			check: func(t *testing.T, stdout, stderr string) {
+				var summary template.RunSummary
+				if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+					t.Fatalf("stdout is not a run summary: %v\n%s", err, stdout)
+				}
+				want := template.ResolvedVariable{Value: "app", Source: "builtin"}
+				if got := summary.Variables["ProjectName"]; got != want {
+					t.Errorf("ProjectName = %+v, want %+v", got, want)
+				}
+				if !strings.Contains(stderr, "Resolved variables") {
+					t.Errorf("the variable dump should go to stderr, got:\n%s", stderr)
+				}
+			},
+		},
+		{
+			name: "list-installed prints only user templates",
+			args: []string{"--list-installed", "--json"},
+			check: func(t *testing.T, stdout, stderr string) {
+				var entries []listEntry
+				if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+					t.Fatalf("stdout is not a template list: %v\n%s", err, stdout)
+				}
+				if len(entries) != 1 || entries[0].Name != "plain" || entries[0].Source != "user" {
+					t.Errorf("entries = %+v, want only the user template plain", entries)
+				}
+			},
+		},
+		{
+			name: "list includes built-in templates",
+			args: []string{"--list", "--json"},
+			check: func(t *testing.T, stdout, stderr string) {
+				var entries []listEntry
+				if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+					t.Fatalf("stdout is not a template list: %v\n%s", err, stdout)
+				}
+				sources := map[string]bool{}
+				for _, entry := range entries {
+					sources[entry.Source] = true
+				}
+				if !sources["user"] || !sources["built-in"] {
+					t.Errorf("sources = %v, want user and built-in templates", sources)
+				}
+			},
+		},
+		{
+			name:    "conflicting list format",
+			args:    []string{"--list", "--list-format", "table", "--json"},
+			wantErr: "--json cannot be combined with --list-format table",
+		},
+		{
+			name:    "unsupported mode",
+			args:    []string{"--interactive", "--json"},
+			wantErr: "--json is only supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, jsonTemplates)
+			stdout, stderr, err := runCLI(t, dir, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			tt.check(t, stdout, stderr)
+		})
+	}
+}
+
+func TestSummaryJSON(t *testing.T) {
+	dir := cliEnv(t, jsonTemplates)
+	// 相對路徑以 -C 指定的目錄為準
+	_, stderr, err := runCLI(t, dir, "-n", "app", "-t", "plain", "--no-input", "--summary-json", "summary.json")
+	if err != nil {
+		t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "summary.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary template.RunSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("summary.json is not a run summary: %v\n%s", err, data)
+	}
+	if summary.ProjectName != "app" || summary.ProjectPath != filepath.Join(dir, "app") || len(summary.Commands) != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
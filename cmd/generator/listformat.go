@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"aaa-generator/internal/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// listFormats 為 --list-format 可用的輸出格式；detailed 為原本的多行格式
+var listFormats = []string{"detailed", "table", "yaml", "json"}
+
+// listEntry 為 yaml/json 輸出中每個模板的欄位
+type listEntry struct {
+	Name               string   `json:"name" yaml:"name"`
+	DisplayName        string   `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Description        string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Version            string   `json:"version,omitempty" yaml:"version,omitempty"`
+	Author             string   `json:"author,omitempty" yaml:"author,omitempty"`
+	URL                string   `json:"url,omitempty" yaml:"url,omitempty"`
+	Source             string   `json:"source" yaml:"source"`
+	Tags               []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Path               string   `json:"path,omitempty" yaml:"path,omitempty"`
+	Deprecated         bool     `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	DeprecationMessage string   `json:"deprecationMessage,omitempty" yaml:"deprecationMessage,omitempty"`
+}
+
+func checkListFormat(format string) error {
+	for _, name := range listFormats {
+		if format == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --list-format %q (expected %s)", format, strings.Join(listFormats, ", "))
+}
+
+// renderTemplateList 以 table、yaml 或 json 格式輸出模板清單；yaml/json 一律包含磁碟路徑
+func renderTemplateList(out io.Writer, templates []template.TemplateInfo, format string, showPaths bool) error {
+	switch format {
+	case "table":
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		header := "NAME\tVERSION\tSOURCE\tDESCRIPTION"
+		if showPaths {
+			header += "\tPATH"
+		}
+		fmt.Fprintln(w, header)
+		for _, tmpl := range templates {
+			description := tmpl.Description
+			if tmpl.Deprecated {
+				description = "(deprecated) " + description
+			}
+			row := fmt.Sprintf("%s\t%s\t%s\t%s", tmpl.Name, tmpl.Version, tmpl.Source, description)
+			if showPaths {
+				row += "\t" + templateLocation(tmpl)
+			}
+			fmt.Fprintln(w, row)
+		}
+		return w.Flush()
+	case "yaml", "json":
+		entries := make([]listEntry, 0, len(templates))
+		for _, tmpl := range templates {
+			entries = append(entries, listEntry{
+				Name:               tmpl.Name,
+				DisplayName:        tmpl.DisplayName,
+				Description:        tmpl.Description,
+				Version:            tmpl.Version,
+				Author:             tmpl.Author,
+				URL:                tmpl.URL,
+				Source:             tmpl.Source,
+				Tags:               tmpl.Tags,
+				Path:               tmpl.LocalPath,
+				Deprecated:         tmpl.Deprecated,
+				DeprecationMessage: tmpl.DeprecationMessage,
+			})
+		}
+		if format == "json" {
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(entries)
+		}
+		encoder := yaml.NewEncoder(out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(entries); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+	return checkListFormat(format)
+}
+
+// templateLocation 回傳模板在磁碟上的目錄，內建模板顯示為 (embedded)
+func templateLocation(tmpl template.TemplateInfo) string {
+	if tmpl.LocalPath == "" {
+		return "(embedded)"
+	}
+	return tmpl.LocalPath
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aaa-generator/internal/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderTemplateList(t *testing.T) {
+	templates := []template.TemplateInfo{
+		{Name: "basic", DisplayName: "Basic", Description: "Starter", Version: "1.0.0", Source: "built-in"},
+		{Name: "old", Description: "Legacy API", Version: "0.9.0", Source: "user", LocalPath: "/tpl/old", URL: "https://example.com/old", Deprecated: true, DeprecationMessage: "use basic"},
+	}
+	wantEntries := []listEntry{
+		{Name: "basic", DisplayName: "Basic", Description: "Starter", Version: "1.0.0", Source: "built-in"},
+		{Name: "old", Description: "Legacy API", Version: "0.9.0", Source: "user", Path: "/tpl/old", URL: "https://example.com/old", Deprecated: true, DeprecationMessage: "use basic"},
+	}
+
+	tests := []struct {
+		name      string
+		format    string
+		showPaths bool
+		check     func(t *testing.T, output string)
+		wantErr   string
+	}{
+		{
+			name:   "table",
+			format: "table",
+			check: func(t *testing.T, output string) {
+				lines := strings.Split(strings.TrimSpace(output), "\n")
+				if len(lines) != 3 || strings.Join(strings.Fields(lines[0]), " ") != "NAME VERSION SOURCE DESCRIPTION" {
+					t.Fatalf("table = %q", output)
+				}
+				if !strings.Contains(lines[2], "(deprecated) Legacy API") || strings.Contains(output, "/tpl/old") {
+					t.Errorf("table rows = %q", lines[1:])
+				}
+			},
+		},
+		{
+			name:      "table with paths",
+			format:    "table",
+			showPaths: true,
+			check: func(t *testing.T, output string) {
+				if !strings.Contains(output, "PATH") || !strings.Contains(output, "(embedded)") || !strings.Contains(output, "/tpl/old") {
+					t.Errorf("table = %q", output)
+				}
+			},
+		},
+		{
+			name:   "json",
+			format: "json",
+			check: func(t *testing.T, output string) {
+				var entries []listEntry
+				if err := json.Unmarshal([]byte(output), &entries); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(entries, wantEntries) {
+					t.Errorf("entries = %+v, want %+v", entries, wantEntries)
+				}
+			},
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			check: func(t *testing.T, output string) {
+				var entries []listEntry
+				if err := yaml.Unmarshal([]byte(output), &entries); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(entries, wantEntries) {
+					t.Errorf("entries = %+v, want %+v", entries, wantEntries)
+				}
+			},
+		},
+		{name: "unknown format", format: "csv", wantErr: `invalid --list-format "csv"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := renderTemplateList(&out, templates, tt.format, tt.showPaths)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("renderTemplateList() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderTemplateList() error = %v", err)
+			}
+			tt.check(t, out.String())
+		})
+	}
+}
+
+func TestListFormatFlag(t *testing.T) {
+	dir := cliEnv(t, map[string]map[string]string{"mine": {"template.yaml": "name: mine\nversion: 2.0.0\n"}})
+
+	stdout, _, err := runCLI(t, dir, "--list", "--source", "user", "--list-format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []listEntry
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if len(entries) != 1 || entries[0].Name != "mine" || entries[0].Version != "2.0.0" || entries[0].Path == "" {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	if _, _, err := runCLI(t, dir, "--list", "--list-format", "csv"); err == nil || !strings.Contains(err.Error(), "invalid --list-format") {
+		t.Errorf("--list-format csv error = %v", err)
+	}
+}
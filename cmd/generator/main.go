@@ -6,9 +6,10 @@ import (
 	"io"
 	"os"
 	"os/exec"
-	"strconv"
+	"path/filepath"
 	"strings"
 
+	"aaa-generator/internal/console"
 	"aaa-generator/internal/template"
 	"github.com/spf13/cobra"
 )
@@ -17,17 +18,17 @@ var version = "dev" // Set via ldflags during build
 
 func main() {
 	if err := newRootCommand().Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		console.NewPrinter(os.Stderr).Printf(console.Failure("Error: %s\n"), err)
 		os.Exit(1)
 	}
 }
 
-func printWelcomeBanner() {
-	fmt.Println("╭─────────────────────────────────────────────────────────╮")
-	fmt.Printf("│              AAA-Generator v%-8s                    │\n", version)
-	fmt.Println("│            Application Template Generator               │")
-	fmt.Println("╰─────────────────────────────────────────────────────────╯")
-	fmt.Println()
+func printWelcomeBanner(out io.Writer) {
+	fmt.Fprintln(out, "╭─────────────────────────────────────────────────────────╮")
+	fmt.Fprintf(out, "│              AAA-Generator v%-8s                    │\n", version)
+	fmt.Fprintln(out, "│            Application Template Generator               │")
+	fmt.Fprintln(out, "╰─────────────────────────────────────────────────────────╯")
+	fmt.Fprintln(out)
 }
 
 func newRootCommand() *cobra.Command {
@@ -35,9 +36,55 @@ func newRootCommand() *cobra.Command {
 		projectName   string
 		templateName  string
 		listFlag      bool
-		installTarget string
+		listInstalled bool
+		sourceFilter  string
+		installFrom   []string
 		interactive   bool
 		versionFlag   bool
+		noSymlinks    bool
+		countFlag     bool
+		setValues     []string
+		replaceValues []string
+		excludes      []string
+		includeOnly   []string
+		verifyKey     string
+		force         bool
+		prune         bool
+		quietPost     bool
+		nameTemplate  string
+		moduleTmpl    string
+		traceFile     string
+		seed          int64
+		allowDepr     bool
+		strict        bool
+		printVars     bool
+		noInput       bool
+		assumeYes     bool
+		checkModule   bool
+		varHelp       string
+		checkUpdates  bool
+		formatFlag    bool
+		stripGitkeep  bool
+		genReadme     bool
+		summaryJSON   string
+		validateFlag  bool
+		fileMode      string
+		dirMode       string
+		fromStdin     bool
+		ifNotPresent  bool
+		reinstall     bool
+		merge         bool
+		showPaths     bool
+		sortBy        string
+		listFormat    string
+		uninstall     string
+		noEmoji       bool
+		colorMode     string
+		tempDir       bool
+		manifestOnly  string
+		dryRun        bool
+		printManifest bool
+		pathOnly      bool
 	)
 
 	cmd := &cobra.Command{
@@ -45,63 +92,323 @@ func newRootCommand() *cobra.Command {
 		Short:        "Create Go + React applications from templates",
 		Long:         "Go React Generator scaffolds Go backends and React frontends using reusable templates.",
 		SilenceUsage: true,
+		// 錯誤由 main 輸出（經過 --no-emoji 的轉換），cobra 不再另外印一次
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// --json 的輸出供程式解析，auto 模式下與非終端機一樣不加色碼
+			color, err := console.ResolveColor(colorMode, isTerminal(os.Stdout) && !jsonOutput, os.Getenv("NO_COLOR") != "")
+			if err != nil {
+				return err
+			}
+			console.SetColor(color)
+			// 每次執行都重新設定，避免沿用上一次的 --no-emoji
+			console.SetPlain(noEmoji || noEmojiFromEnv())
+			return checkWorkingDir()
+		},
+		Args: func(cmd *cobra.Command, args []string) error {
+			// --install 的額外位置參數為更多安裝來源，其餘情況由模板的 argsOrder 對應到變數（見 applyPositionalArgs）
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if versionFlag {
-				printWelcomeBanner()
+				printWelcomeBanner(console.NewPrinter(os.Stdout))
 				return nil
 			}
 
-			manager, err := template.NewManager()
+			// 進度與狀態訊息都經過 status 輸出；stdout 要留給路徑、JSON 或 manifest 時改寫到 stderr
+			statusFile := os.Stdout
+
+			// --path-only：所有進度輸出改寫到 stderr，stdout 只留下專案的絕對路徑
+			if pathOnly {
+				if interactive || dryRun || manifestOnly != "" {
+					return fmt.Errorf("--path-only cannot be combined with --interactive, --dry-run or --manifest-only")
+				}
+				statusFile = os.Stderr
+			}
+			// --json：進度輸出改寫到 stderr，產生完成後在 stdout 輸出 RunSummary；
+			// 搭配 --list 時等同 --list-format json
+			if jsonOutput {
+				if interactive || pathOnly || dryRun || manifestOnly != "" ||
+					len(installFrom) > 0 || uninstall != "" || checkUpdates || varHelp != "" {
+					return fmt.Errorf("--json is only supported when generating a project or listing templates (use --print-manifest with --dry-run)")
+				}
+				if listFlag || listInstalled {
+					if cmd.Flags().Changed("list-format") && listFormat != "json" {
+						return fmt.Errorf("--json cannot be combined with --list-format %s", listFormat)
+					}
+					listFormat = "json"
+				} else {
+					statusFile = os.Stderr
+				}
+			}
+			// dry-run 不寫入任何檔案，因此不能與寫出 manifest、summary 或 trace 的旗標並用；
+			// --print-manifest 將預計的 manifest 輸出到 stdout，報告改寫到 stderr
+			if dryRun && (manifestOnly != "" || summaryJSON != "" || traceFile != "") {
+				return fmt.Errorf("--dry-run cannot be combined with --manifest-only, --summary-json or --trace (use --print-manifest to see the manifest)")
+			}
+			if printManifest {
+				if !dryRun {
+					return fmt.Errorf("--print-manifest requires --dry-run")
+				}
+				statusFile = os.Stderr
+			}
+			status := console.NewPrinter(statusFile)
+
+			// dry-run 時載入模板也不建立用戶模板目錄或搬移舊目錄
+			newManager := template.NewManager
+			if dryRun {
+				newManager = template.NewReadOnlyManager
+			}
+			manager, err := newManager()
 			if err != nil {
 				return fmt.Errorf("error initializing template manager: %w", err)
 			}
+			manager.NoSymlinks = noSymlinks
+			manager.WorkDir = workingDir
+			manager.VerifyKey = verifyKey
+			manager.IfNotPresent = ifNotPresent
+			manager.Reinstall = reinstall
+			manager.Merge = merge
+			manager.Status = status
+			if ifNotPresent && reinstall {
+				return fmt.Errorf("--if-not-present and --reinstall cannot be used together")
+			}
+			if merge && (ifNotPresent || reinstall) {
+				return fmt.Errorf("--merge cannot be combined with --if-not-present or --reinstall")
+			}
+
+			// 所有提示共用同一個 stdin reader，避免各自緩衝而吃掉彼此的輸入
+			stdin := bufio.NewReader(os.Stdin)
 
+			generator := template.NewGenerator(manager)
+			generator.Input = stdin
+			generator.Status = status
+			generator.Version = version
+			generator.WorkDir = workingDir
+			generator.NoSymlinks = noSymlinks
+			generator.Count = countFlag
+			generator.Force = force
+			generator.Prune = prune
+			generator.QuietPost = quietPost
+			generator.Progress = isTerminal(statusFile)
+			generator.NameTemplate = nameTemplate
+			generator.ModuleTemplate = moduleTmpl
+			generator.Seed = seed
+			generator.AllowDeprecated = allowDepr
+			generator.Strict = strict
+			generator.CheckModule = checkModule
+			// --assume-yes 與 --no-input 一樣不詢問變數，差別在於確認提示一律回答是，而非拒絕
+			generator.NoInput = noInput || assumeYes
+			generator.RepromptInvalid = !generator.NoInput && isTerminal(os.Stdin)
+			generator.PrintVars = printVars
+			generator.Format = formatFlag
+			generator.StripGitkeep = stripGitkeep
+			generator.GenReadme = genReadme
+			generator.Validate = validateFlag
+			generator.Exclude = excludes
+			generator.IncludeOnly = includeOnly
+			if fileMode != "" {
+				if generator.FileMode, err = template.ParseFileMode(fileMode); err != nil {
+					return fmt.Errorf("--file-mode: %w", err)
+				}
+			}
+			if dirMode != "" {
+				if generator.DirMode, err = template.ParseFileMode(dirMode); err != nil {
+					return fmt.Errorf("--dir-mode: %w", err)
+				}
+			}
+			switch {
+			case assumeYes:
+				generator.Confirm = newAssumeYesPrompt(status)
+			case !noInput:
+				generator.Confirm = newConfirmPrompt(status, stdin)
+			}
+			if noInput && assumeYes {
+				return fmt.Errorf("--no-input and --assume-yes cannot be used together")
+			}
+			if (noInput || assumeYes) && interactive {
+				return fmt.Errorf("--no-input and --assume-yes cannot be combined with --interactive")
+			}
+			if prune && !force {
+				return fmt.Errorf("--prune requires --force")
+			}
+			if fromStdin {
+				if interactive {
+					return fmt.Errorf("--from-stdin cannot be combined with --interactive")
+				}
+				for _, value := range setValues {
+					if strings.HasSuffix(value, "=@-") {
+						return fmt.Errorf("--from-stdin cannot be combined with --set Key=@-")
+					}
+				}
+			}
+			if generator.Values, err = parseSetValues(setValues, stdin); err != nil {
+				return err
+			}
+			if generator.Replace, err = parseReplaceValues(replaceValues); err != nil {
+				return err
+			}
+			if traceFile != "" {
+				file, err := os.Create(workPath(traceFile))
+				if err != nil {
+					return fmt.Errorf("failed to create trace file: %w", err)
+				}
+				defer file.Close()
+				generator.Trace = file
+			}
+
+			if listInstalled {
+				listFlag = true
+				sourceFilter = "user"
+			}
 			if listFlag {
-				listAvailableTemplates(manager)
-				return nil
+				return listAvailableTemplates(status, manager, sourceFilter, sortBy, listFormat, showPaths)
 			}
 
-			if installTarget != "" {
-				fmt.Println()
-				fmt.Printf("📦 Installing template from: %s\n", installTarget)
-				fmt.Println("───────────────────────────────────────────────────────")
-				if err := manager.InstallTemplate(installTarget); err != nil {
-					return fmt.Errorf("error installing template: %w", err)
+			if uninstall != "" {
+				if err := manager.UninstallTemplate(uninstall); err != nil {
+					return fmt.Errorf("error uninstalling template: %w", err)
 				}
-				fmt.Println("✅ Template installed successfully!")
-				fmt.Println()
 				return nil
 			}
 
+			if checkUpdates {
+				printUpdateChecks(status, manager.CheckUpdates())
+				return nil
+			}
+
+			if varHelp != "" {
+				return manager.VariableHelp(status, templateName, varHelp)
+			}
+
+			if len(installFrom) > 0 {
+				// --install a b：其餘的位置參數也視為安裝來源
+				return installTemplates(status, manager, append(installFrom, args...))
+			}
+
 			if interactive {
-				if err := checkEnvironment(cmd.OutOrStdout()); err != nil {
+				if len(args) > 0 {
+					return fmt.Errorf("positional arguments cannot be combined with --interactive")
+				}
+				if err := checkEnvironment(status); err != nil {
 					return err
 				}
-				if err := runInteractiveMode(manager); err != nil {
+				preselected := ""
+				if cmd.Flags().Changed("template") {
+					preselected = templateName
+				}
+				if err := runInteractiveMode(status, manager, generator, stdin, preselected); err != nil {
 					return err
 				}
 				return nil
 			}
 
+			if len(args) > 0 {
+				if fromStdin {
+					return fmt.Errorf("positional arguments cannot be combined with --from-stdin")
+				}
+				if projectName, err = applyPositionalArgs(manager, generator, templateName, projectName, args); err != nil {
+					return err
+				}
+			}
+
 			if projectName == "" {
 				return fmt.Errorf("project name is required (use --name or run with --interactive)")
 			}
 
-			if err := checkEnvironment(cmd.OutOrStdout()); err != nil {
+			if fromStdin {
+				// 模板封存只用於這次產生，結束後即刪除
+				name, cleanup, err := manager.LoadTemplateArchive(stdin)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+				templateName = name
+			}
+
+			if projectName, err = generator.ResolveProjectName(projectName); err != nil {
 				return err
 			}
 
-			fmt.Println()
-			fmt.Printf("🚀 Creating project '%s' using template '%s'\n", projectName, templateName)
-			fmt.Println("───────────────────────────────────────────────────────")
+			if dryRun {
+				report, err := generator.DryRun(projectName, templateName)
+				if err != nil {
+					return err
+				}
+				if err := printDryRun(status, projectName, report); err != nil {
+					return err
+				}
+				if printManifest {
+					if _, err := report.Manifest.WriteTo(os.Stdout); err != nil {
+						return fmt.Errorf("failed to print manifest: %w", err)
+					}
+				}
+				return nil
+			}
 
-			generator := template.NewGenerator(manager)
-			if err := generator.Generate(projectName, templateName); err != nil {
+			if manifestOnly != "" {
+				manifest, err := generator.Plan(projectName, templateName)
+				if err != nil {
+					return err
+				}
+				manifestPath := workPath(manifestOnly)
+				if err := manifest.WriteFile(manifestPath); err != nil {
+					return fmt.Errorf("failed to write manifest: %w", err)
+				}
+				status.Printf(console.Success("✅ Manifest for '%s' written to %s (%d files, nothing generated)\n"), projectName, manifestPath, len(manifest.Files))
+				return nil
+			}
+
+			if err := checkEnvironment(status); err != nil {
 				return err
 			}
 
-			showNextSteps(projectName)
-			return nil
+			status.Println()
+			status.Printf("🚀 Creating project '%s' using template '%s'\n", projectName, templateName)
+			status.Println("───────────────────────────────────────────────────────")
+
+			if tempDir {
+				// 暫存目錄每次都是新的，不會有目錄已存在的問題
+				if generator.OutputDir, err = os.MkdirTemp("", filepath.Base(projectName)+"-"); err != nil {
+					return fmt.Errorf("failed to create temp directory: %w", err)
+				}
+			}
+
+			// post-generate 命令失敗時專案仍已產生：照常輸出結果，最後再回傳錯誤
+			result, genErr := generator.Generate(projectName, templateName)
+			if result == nil {
+				return genErr
+			}
+			if summaryJSON != "" {
+				if err := result.WriteSummary(workPath(summaryJSON)); err != nil {
+					return fmt.Errorf("failed to write summary: %w", err)
+				}
+			}
+			if jsonOutput {
+				summary, err := result.Summary()
+				if err != nil {
+					return err
+				}
+				if err := writeJSON(os.Stdout, summary); err != nil {
+					return fmt.Errorf("failed to write summary: %w", err)
+				}
+				return genErr
+			}
+
+			if pathOnly {
+				path, err := filepath.Abs(result.ProjectDir)
+				if err != nil {
+					return fmt.Errorf("failed to resolve project path: %w", err)
+				}
+				fmt.Fprintln(os.Stdout, path)
+				return genErr
+			}
+
+			showNextSteps(status, result)
+			if tempDir {
+				status.Printf("📁 Generated in: %s\n", result.ProjectDir)
+			}
+			return genErr
 		},
 	}
 
@@ -109,84 +416,401 @@ func newRootCommand() *cobra.Command {
 
 	cmd.Flags().StringVarP(&projectName, "name", "n", "", "Project name for the generated application")
 	cmd.Flags().StringVarP(&templateName, "template", "t", templateName, "Template to use when generating the project")
+	cmd.Flags().BoolVar(&fromStdin, "from-stdin", false, "Generate from a template tar(.gz) archive read from stdin without installing it")
+	cmd.Flags().StringVar(&nameTemplate, "name-template", "", "Derive the project name from a pattern, e.g. 'svc-{{ .Name }}'")
+	cmd.Flags().StringVar(&moduleTmpl, "module-template", "", "Derive the Go module name from a pattern, e.g. 'github.com/acme/{{ .Name }}'")
 	cmd.Flags().BoolVarP(&listFlag, "list", "l", false, "List available templates")
-	cmd.Flags().StringVar(&installTarget, "install", "", "Install template from URL or local path")
+	cmd.Flags().StringVar(&sourceFilter, "source", "all", "With --list, only show templates from this source (user, builtin, all)")
+	cmd.Flags().BoolVar(&showPaths, "show-paths", false, "With --list, show where each template is stored on disk")
+	cmd.Flags().StringVar(&sortBy, "sort", "source", "With --list, order templates by "+strings.Join(template.TemplateSortKeys, ", "))
+	cmd.Flags().StringVar(&listFormat, "list-format", "detailed", "With --list, output as "+strings.Join(listFormats, ", "))
+	cmd.Flags().BoolVar(&listInstalled, "list-installed", false, "List only user-installed templates (same as --list --source user)")
+	cmd.Flags().StringArrayVar(&installFrom, "install", nil, "Install template from URL or local path (repeatable; extra arguments are also installed)")
+	cmd.Flags().BoolVar(&checkUpdates, "check-updates", false, "Check the recorded install sources of user templates for newer versions without installing them")
+	cmd.Flags().StringVar(&uninstall, "uninstall", "", "Remove an installed user template by name")
+	cmd.Flags().BoolVar(&ifNotPresent, "if-not-present", false, "With --install, skip templates that are already installed")
+	cmd.Flags().BoolVar(&reinstall, "reinstall", false, "With --install, remove an installed template of the same name before installing")
+	cmd.Flags().BoolVar(&merge, "merge", false, "With --install, overlay the source onto an installed template of the same name, keeping its other files")
+	cmd.Flags().StringVar(&verifyKey, "verify-key", "", "Verify template.yaml.sig with this ed25519 public key before installing")
+	cmd.Flags().StringArrayVar(&setValues, "set", nil, "Set a template variable (Key=Value, Key=@file to read a file, Key=@- to read stdin)")
+	cmd.Flags().StringArrayVar(&replaceValues, "replace", nil, "Replace a literal string in copied non-.tmpl text files (old=new, repeatable)")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Skip generated files whose source or target path matches this glob (repeatable, ** allowed)")
+	cmd.Flags().StringArrayVar(&includeOnly, "include-only", nil, "Only generate files whose source or target path matches this glob (repeatable; applied before --exclude)")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "Seed for random template functions such as randAlphaNum (default: time-based)")
 	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Run in interactive mode")
 	cmd.Flags().BoolVar(&versionFlag, "version", false, "Show the generator version")
+	cmd.Flags().BoolVar(&tempDir, "temp", false, "Generate into a new temporary directory and print its path")
+	cmd.Flags().BoolVar(&pathOnly, "path-only", false, "Print only the absolute project path to stdout on success (progress goes to stderr), e.g. cd \"$(generator -n app --path-only)\"")
+	cmd.Flags().StringVar(&manifestOnly, "manifest-only", "", "Write the manifest of what would be generated to this path without generating anything")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the files that would be generated and check post-generate commands without writing or running anything")
+	cmd.Flags().BoolVar(&printManifest, "print-manifest", false, "With --dry-run, print the planned manifest as JSON to stdout (the report goes to stderr)")
+	cmd.Flags().BoolVar(&allowDepr, "allow-deprecated", false, "Allow generating from a template marked as deprecated")
+	cmd.Flags().BoolVar(&validateFlag, "validate", false, "Run the template's validate commands (e.g. go build ./...) against the generated project; a failure fails generation")
+	cmd.Flags().BoolVar(&formatFlag, "format", false, "Run gofmt / prettier over the generated files when they are installed")
+	cmd.Flags().StringVar(&varHelp, "var-help", "", "Explain a single variable of the selected template (type, required, default, options) and exit")
+	cmd.Flags().BoolVar(&printVars, "print-vars", false, "Print the resolved template variables and their sources before generating")
+	cmd.Flags().BoolVar(&noInput, "no-input", false, "Never prompt: fail when a required variable is missing or a value is invalid, and decline confirmations")
+	cmd.Flags().BoolVarP(&assumeYes, "assume-yes", "y", false, "Take every default and answer yes to confirmations; fail only if a required variable has no default or --set value")
+	cmd.Flags().BoolVar(&checkModule, "check-module", false, "For Go templates, also warn when the module name already exists in the local module cache")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail instead of warning when Go module or package names are invalid or post-generate tools are missing")
+	cmd.Flags().BoolVar(&force, "force", false, "Regenerate into an existing project directory")
+	cmd.Flags().BoolVar(&prune, "prune", false, "With --force, remove previously generated files the template no longer produces")
+	cmd.Flags().BoolVar(&quietPost, "quiet-post", false, "Only show post-generate command output when a command fails")
+	cmd.Flags().StringVar(&summaryJSON, "summary-json", "", "Write a JSON summary of the run (template, project path, files with kinds, commands with exit codes and durations) to this file")
+	cmd.Flags().BoolVar(&countFlag, "count", false, "Print a summary of created/skipped files and commands run")
+	cmd.Flags().StringVar(&fileMode, "file-mode", "", "Permissions for generated files as octal, e.g. 0600 (default: template's fileMode or 0644)")
+	cmd.Flags().StringVar(&dirMode, "dir-mode", "", "Permissions for generated directories as octal (default: template's dirMode or 0755)")
+	cmd.Flags().BoolVar(&stripGitkeep, "strip-gitkeep", false, "Create directories that only exist for a .gitkeep without copying the .gitkeep")
+	cmd.Flags().BoolVar(&genReadme, "gen-readme", false, "Write a README.md from the template metadata, variables and next steps when the template doesn't provide one")
+	cmd.Flags().BoolVar(&noSymlinks, "no-symlinks", false, "Copy symlink targets instead of recreating symlinks")
+	cmd.Flags().StringVar(&traceFile, "trace", "", "Write a JSON-lines debug log of variable, rule and command decisions to this file")
+	cmd.PersistentFlags().StringVarP(&workingDir, "working-dir", "C", "", "Resolve relative project paths, output files and install sources against this directory (like make -C)")
+	cmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Print machine-readable JSON on stdout instead of the formatted output (info, --list, and the run summary with file counts when generating)")
+	cmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Colorize status output: auto, always or never")
+	cmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", false, "Use plain ASCII status markers instead of emoji (also NO_COLOR or GENERATOR_NO_EMOJI)")
 	cmd.Flags().SortFlags = false
 
+	cmd.AddCommand(newTemplateCommand())
+	cmd.AddCommand(newSchemaCommand())
+	cmd.AddCommand(newValidateCommand())
+	cmd.AddCommand(newDoctorCommand())
+	cmd.AddCommand(newInfoCommand())
+	cmd.AddCommand(newRegenerateCommand())
+	cmd.AddCommand(newCacheCommand())
+	cmd.AddCommand(newExportAllCommand())
+	cmd.AddCommand(newImportAllCommand())
+	cmd.AddCommand(newBatchCommand())
+
 	return cmd
 }
 
-func listAvailableTemplates(manager *template.Manager) {
-	templates := manager.ListTemplates()
+func newSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema for template.yaml",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := cmd.OutOrStdout().Write(template.ConfigSchema())
+			return err
+		},
+	}
+}
 
-	if len(templates) == 0 {
-		fmt.Println("❌ No templates available.")
-		return
+func newValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [template-dir]",
+		Short: "Validate a template.yaml against the schema",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			location := "."
+			if len(args) > 0 {
+				location = args[0]
+			}
+
+			status := console.NewPrinter(cmd.OutOrStdout())
+			problems, err := template.ValidateTemplate(workPath(location))
+			if err != nil {
+				return fmt.Errorf("error validating template: %w", err)
+			}
+
+			if len(problems) > 0 {
+				status.Println(console.Failure("❌ Template is invalid:"))
+				for _, problem := range problems {
+					status.Printf("   • %s\n", problem)
+				}
+				return fmt.Errorf("%d problem(s) found in %s", len(problems), location)
+			}
+
+			status.Println(console.Success("✅ Template is valid"))
+			return nil
+		},
 	}
+}
 
-	printWelcomeBanner()
-	fmt.Println("📋 Available templates:")
-	fmt.Println("───────────────────────────────────────────────────────")
+func newRegenerateCommand() *cobra.Command {
+	var (
+		files      []string
+		projectDir string
+		setValues  []string
+	)
 
-	for _, tmpl := range templates {
-		fmt.Printf("📦 %s (%s)\n", tmpl.DisplayName, tmpl.Name)
-		fmt.Printf("   %s\n", tmpl.Description)
-		fmt.Printf("   Version: %s | Source: %s\n", tmpl.Version, tmpl.Source)
-		if len(tmpl.Tags) > 0 {
-			fmt.Printf("   🏷️  %s\n", strings.Join(tmpl.Tags, ", "))
+	cmd := &cobra.Command{
+		Use:   "regenerate --file <path>",
+		Short: "Re-render generated files from the template using the project's saved variables",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(files) == 0 {
+				return fmt.Errorf("at least one --file is required")
+			}
+
+			manager, err := template.NewManager()
+			if err != nil {
+				return fmt.Errorf("error initializing template manager: %w", err)
+			}
+			status := console.NewPrinter(cmd.OutOrStdout())
+			generator := template.NewGenerator(manager)
+			generator.Version = version
+			generator.WorkDir = workingDir
+			generator.Status = status
+			// secret 變數不保存在 manifest 中，須以 --set 或同名的環境變數再次提供
+			if generator.Values, err = parseSetValues(setValues, os.Stdin); err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				if err := generator.RegenerateFile(projectDir, file); err != nil {
+					return fmt.Errorf("error regenerating %s: %w", file, err)
+				}
+				status.Printf(console.Success("✅ Regenerated %s\n"), file)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&files, "file", nil, "Generated file to re-render, relative to the project directory (repeatable)")
+	cmd.Flags().StringVar(&projectDir, "dir", ".", "Project directory containing "+template.ManifestFileName)
+	cmd.Flags().StringArrayVar(&setValues, "set", nil, "Provide a secret variable, which the manifest does not save (Key=Value, Key=@file, Key=@-); otherwise it is read from the environment variable of the same name")
+	return cmd
+}
+
+func newTemplateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "new-template <name>",
+		Short: "Create a starter template in the user templates directory",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manager, err := template.NewManager()
+			if err != nil {
+				return fmt.Errorf("error initializing template manager: %w", err)
+			}
+
+			path, err := manager.CreateTemplateSkeleton(args[0])
+			if err != nil {
+				return fmt.Errorf("error creating template: %w", err)
+			}
+
+			status := console.NewPrinter(cmd.OutOrStdout())
+			status.Println()
+			status.Printf(console.Success("✅ Template '%s' created at: %s\n"), args[0], path)
+			status.Println("   Edit template.yaml and the files under project/ to get started.")
+			status.Println()
+			return nil
+		},
+	}
+}
+
+// applyPositionalArgs 依模板的 argsOrder 將位置參數設為變數值，回傳（可能由參數指定的）專案名稱。
+// 同一個變數不可同時以位置參數與 --set（或 --name）指定
+func applyPositionalArgs(manager *template.Manager, generator *template.Generator, templateName, projectName string, args []string) (string, error) {
+	values, err := manager.PositionalValues(templateName, args)
+	if err != nil {
+		return "", err
+	}
+	if generator.Values == nil {
+		generator.Values = make(map[string]string)
+	}
+	for name, value := range values {
+		if name == "ProjectName" {
+			if projectName != "" {
+				return "", fmt.Errorf("project name given both with --name and as a positional argument")
+			}
+			projectName = value
+			continue
+		}
+		if _, exists := generator.Values[name]; exists {
+			return "", fmt.Errorf("variable '%s' given both with --set and as a positional argument", name)
 		}
-		fmt.Println()
+		generator.Values[name] = value
 	}
-	fmt.Println("───────────────────────────────────────────────────────")
-	fmt.Println()
+	return projectName, nil
 }
 
-func runInteractiveMode(manager *template.Manager) error {
-	printWelcomeBanner()
+// printUpdateChecks 列出 --check-updates 的結果；查詢失敗的模板只顯示警告
+func printUpdateChecks(out io.Writer, checks []template.UpdateCheck) {
+	fmt.Fprintln(out)
+	if len(checks) == 0 {
+		fmt.Fprintln(out, "ℹ️  No installed templates with a recorded install source")
+		fmt.Fprintln(out)
+		return
+	}
 
-	reader := bufio.NewReader(os.Stdin)
+	fmt.Fprintln(out, "🔍 Checking installed templates for updates:")
+	available := 0
+	for _, check := range checks {
+		switch {
+		case check.Err != nil:
+			fmt.Fprintf(out, console.Warning("   ⚠️  %s: %v\n"), check.Name, check.Err)
+		case check.Available:
+			available++
+			fmt.Fprintf(out, console.Success("   ⬆️  %s: %s → %s (%s)\n"), check.Name, displayVersion(check.Installed), check.Latest, check.Source)
+		default:
+			fmt.Fprintf(out, "   ✅ %s: %s is up to date\n", check.Name, displayVersion(check.Installed))
+		}
+	}
+	fmt.Fprintln(out)
 
-	templates := manager.ListTemplates()
-	if len(templates) == 0 {
-		return fmt.Errorf("no templates available")
+	if available > 0 {
+		fmt.Fprintf(out, "%d update(s) available; install with: generator --install <source> --reinstall\n", available)
+		fmt.Fprintln(out)
 	}
+}
 
-	fmt.Println("Available templates:")
-	for i, tmpl := range templates {
-		fmt.Printf("%d) %s - %s\n", i+1, tmpl.DisplayName, tmpl.Description)
+// displayVersion 為未標示 version 的模板顯示替代文字
+func displayVersion(version string) string {
+	if version == "" {
+		return "(no version)"
 	}
+	return version
+}
 
-	var choice int
-	for {
-		fmt.Printf("\nSelect template (1-%d): ", len(templates))
-		input, err := reader.ReadString('\n')
-		if err != nil {
-			return fmt.Errorf("failed to read input: %w", err)
+// installTemplates 依序安裝每個來源；單一來源失敗不會中止其餘安裝，最後回報摘要
+func installTemplates(out io.Writer, manager *template.Manager, sources []string) error {
+	failed := make([]bool, len(sources))
+	failures := 0
+	for i, source := range sources {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "📦 Installing template from: %s\n", source)
+		fmt.Fprintln(out, "───────────────────────────────────────────────────────")
+		result, err := manager.InstallTemplate(source)
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, console.Failure("❌ Failed: %v\n"), err)
+			failed[i] = true
+			failures++
+		case result.Skipped:
+			fmt.Fprintf(out, "ℹ️  Template '%s' is already installed, skipping\n", result.Name)
+		case result.Merge != nil:
+			fmt.Fprintf(out, console.Success("✅ Template '%s' merged: %d added, %d updated, %d unchanged\n"),
+				result.Name, result.Merge.Added, result.Merge.Updated, result.Merge.Unchanged)
+		default:
+			fmt.Fprintf(out, console.Success("✅ Template '%s' installed successfully!\n"), result.Name)
 		}
+	}
+	fmt.Fprintln(out)
+
+	if len(sources) > 1 {
+		fmt.Fprintln(out, "📋 Install summary:")
+		for i, source := range sources {
+			status := console.Success("✅")
+			if failed[i] {
+				status = console.Failure("❌")
+			}
+			fmt.Fprintf(out, "   %s %s\n", status, source)
+		}
+		fmt.Fprintln(out)
+	}
 
-		input = strings.TrimSpace(input)
-		if input == "" {
-			fmt.Println("Please enter a number.")
-			continue
+	if failures > 0 {
+		return fmt.Errorf("failed to install %d of %d template(s)", failures, len(sources))
+	}
+	return nil
+}
+
+func listAvailableTemplates(out io.Writer, manager *template.Manager, source, sortBy, format string, showPaths bool) error {
+	if err := checkListFormat(format); err != nil {
+		return err
+	}
+	templates, err := filterTemplatesBySource(manager.ListTemplates(), source)
+	if err != nil {
+		return err
+	}
+	if err := template.SortTemplates(templates, sortBy); err != nil {
+		return err
+	}
+
+	if format != "detailed" {
+		if err := renderTemplateList(os.Stdout, templates, format, showPaths); err != nil {
+			return err
+		}
+		// 衝突警告寫到 stderr，不影響 yaml/json 輸出的解析
+		for _, conflict := range manager.Conflicts() {
+			console.NewPrinter(os.Stderr).Printf(console.Warning("⚠️  %s\n"), conflict)
 		}
+		return nil
+	}
 
-		value, err := strconv.Atoi(input)
-		if err != nil || value < 1 || value > len(templates) {
-			fmt.Println("Invalid selection. Try again.")
-			continue
+	if len(templates) == 0 {
+		fmt.Fprintln(out, console.Failure("❌ No templates available."))
+		return nil
+	}
+
+	printWelcomeBanner(out)
+	fmt.Fprintln(out, "📋 Available templates:")
+	fmt.Fprintln(out, "───────────────────────────────────────────────────────")
+
+	for _, tmpl := range templates {
+		fmt.Fprintf(out, "📦 %s (%s)\n", tmpl.DisplayName, tmpl.Name)
+		fmt.Fprintf(out, "   %s\n", tmpl.Description)
+		fmt.Fprintf(out, "   Version: %s | Source: %s\n", tmpl.Version, tmpl.Source)
+		if showPaths {
+			fmt.Fprintf(out, "   📁 %s\n", templateLocation(tmpl))
+		}
+		if len(tmpl.Tags) > 0 {
+			fmt.Fprintf(out, "   🏷️  %s\n", strings.Join(tmpl.Tags, ", "))
+		}
+		if tmpl.Deprecated {
+			fmt.Fprintln(out, console.Warning("   ⚠️  Deprecated"))
+			if tmpl.DeprecationMessage != "" {
+				fmt.Fprintf(out, "      %s\n", tmpl.DeprecationMessage)
+			}
 		}
+		fmt.Fprintln(out)
+	}
+	for _, conflict := range manager.Conflicts() {
+		fmt.Fprintf(out, console.Warning("⚠️  %s\n"), conflict)
+	}
+	fmt.Fprintln(out, "───────────────────────────────────────────────────────")
+	fmt.Fprintln(out)
+	return nil
+}
 
-		choice = value
-		break
+// filterTemplatesBySource 依來源篩選模板：user、builtin（built-in）或 all
+func filterTemplatesBySource(templates []template.TemplateInfo, source string) ([]template.TemplateInfo, error) {
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case "", "all":
+		return templates, nil
+	case "builtin", "built-in":
+		source = "built-in"
+	case "user":
+		source = "user"
+	default:
+		return nil, fmt.Errorf("invalid --source %q (expected user, builtin or all)", source)
 	}
 
-	selectedTemplate := templates[choice-1]
+	var filtered []template.TemplateInfo
+	for _, tmpl := range templates {
+		if tmpl.Source == source {
+			filtered = append(filtered, tmpl)
+		}
+	}
+	return filtered, nil
+}
+
+// runInteractiveMode 以互動方式建立專案；templateName 不為空時略過模板選單。
+// reader 須與 generator.Input 相同，之後的變數提示才讀得到剩餘的輸入
+func runInteractiveMode(out io.Writer, manager *template.Manager, generator *template.Generator, reader *bufio.Reader, templateName string) error {
+	printWelcomeBanner(out)
+
+	if templateName != "" {
+		if _, err := manager.GetTemplate(templateName); err != nil {
+			return err
+		}
+	} else {
+		templates := manager.ListTemplates()
+		if len(templates) == 0 {
+			return fmt.Errorf("no templates available")
+		}
+
+		selectedTemplate, err := selectTemplate(out, reader, templates)
+		if err != nil {
+			return err
+		}
+		templateName = selectedTemplate.Name
+	}
 
 	var projectName string
 	for {
-		fmt.Print("Enter project name: ")
+		fmt.Fprint(out, "Enter project name: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return fmt.Errorf("failed to read project name: %w", err)
@@ -194,38 +818,74 @@ func runInteractiveMode(manager *template.Manager) error {
 
 		projectName = strings.TrimSpace(input)
 		if projectName == "" {
-			fmt.Println("Project name cannot be empty. Please try again.")
+			fmt.Fprintln(out, "Project name cannot be empty. Please try again.")
 			continue
 		}
 
+		projectName, err = generator.ResolveProjectName(projectName)
+		if err != nil {
+			return err
+		}
+
 		break
 	}
 
-	if err := checkEnvironment(os.Stdout); err != nil {
+	if err := checkEnvironment(out); err != nil {
 		return err
 	}
 
-	generator := template.NewGenerator(manager)
-	if err := generator.Generate(projectName, selectedTemplate.Name); err != nil {
+	result, err := generator.Generate(projectName, templateName)
+	if result == nil {
 		return err
 	}
 
-	showNextSteps(projectName)
-	return nil
+	showNextSteps(out, result)
+	return err
+}
+
+// newConfirmPrompt 回傳在 out 顯示問題、從 reader 讀取回答的確認提示，只有輸入 y/yes 時回傳 true
+func newConfirmPrompt(out io.Writer, reader *bufio.Reader) func(prompt string) bool {
+	return func(prompt string) bool {
+		fmt.Fprintf(out, console.Warning("⚠️  %s [y/N]: "), prompt)
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return false
+		}
+		answer := strings.ToLower(strings.TrimSpace(input))
+		return answer == "y" || answer == "yes"
+	}
 }
 
-func showNextSteps(projectName string) {
-	fmt.Println()
-	fmt.Println("✨ Project created successfully!")
-	fmt.Println()
-	fmt.Println("📝 Next steps:")
-	fmt.Printf("   cd %s\n", projectName)
-	fmt.Println("   make install    # Install dependencies")
-	fmt.Println("   make dev        # Start development servers")
-	fmt.Println("   make build      # Build for production")
-	fmt.Println("───────────────────────────────────────────────────────")
-	fmt.Println("✨ Ready! Happy coding!")
-	fmt.Println()
+// newAssumeYesPrompt 回傳 --assume-yes 時的確認提示：在 out 顯示問題並直接回答是
+func newAssumeYesPrompt(out io.Writer) func(prompt string) bool {
+	return func(prompt string) bool {
+		fmt.Fprintf(out, console.Warning("⚠️  %s [y/N]: y (--assume-yes)\n"), prompt)
+		return true
+	}
+}
+
+func showNextSteps(out io.Writer, result *template.GenerateResult) {
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "✨ Project created successfully!")
+	fmt.Fprintln(out)
+	if result.Message != "" {
+		fmt.Fprintln(out, result.Message)
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out, "📝 Next steps:")
+	fmt.Fprintf(out, "   cd %s\n", result.ProjectDir)
+	if len(result.NextSteps) > 0 {
+		for _, step := range result.NextSteps {
+			fmt.Fprintf(out, "   %s\n", step)
+		}
+	} else {
+		fmt.Fprintln(out, "   make install    # Install dependencies")
+		fmt.Fprintln(out, "   make dev        # Start development servers")
+		fmt.Fprintln(out, "   make build      # Build for production")
+	}
+	fmt.Fprintln(out, "───────────────────────────────────────────────────────")
+	fmt.Fprintln(out, "✨ Ready! Happy coding!")
+	fmt.Fprintln(out)
 }
 
 func checkEnvironment(out io.Writer) error {
@@ -236,7 +896,7 @@ func checkEnvironment(out io.Writer) error {
 	if err := ensureTool("node", "Install Node.js from https://nodejs.org/", out); err != nil {
 		return err
 	}
-	fmt.Fprintln(out, "✅ Environment ready")
+	fmt.Fprintln(out, console.Success("✅ Environment ready"))
 	fmt.Fprintln(out, "───────────────────────────────────────────────────────")
 	return nil
 }
@@ -244,9 +904,9 @@ func checkEnvironment(out io.Writer) error {
 func ensureTool(name, hint string, out io.Writer) error {
 	fmt.Fprintf(out, "   • %s: ", name)
 	if _, err := exec.LookPath(name); err != nil {
-		fmt.Fprintln(out, "❌ missing")
+		fmt.Fprintln(out, console.Failure("❌ missing"))
 		return fmt.Errorf("%s executable not found in PATH. %s", name, hint)
 	}
-	fmt.Fprintln(out, "✅")
+	fmt.Fprintln(out, console.Success("✅"))
 	return nil
 }
@@ -0,0 +1,781 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aaa-generator/internal/template"
+)
+
+// cliEnv 以暫存目錄隔離 HOME、XDG 目錄與 PATH（只提供假的 go 與 node），回傳工作目錄
+func cliEnv(t *testing.T, templates map[string]map[string]string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+	t.Setenv("NO_COLOR", "")
+
+	bin := filepath.Join(home, "bin")
+	for _, tool := range []string{"go", "node"} {
+		writeTestFile(t, filepath.Join(bin, tool), "#!/bin/sh\nexit 0\n", 0o755)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+"/usr/bin:/bin")
+
+	for name, files := range templates {
+		for file, content := range files {
+			writeTestFile(t, filepath.Join(home, "data", "aaa-generator", "templates", name, filepath.FromSlash(file)), content, 0o644)
+		}
+	}
+	return t.TempDir()
+}
+
+func writeTestFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runCLI 以 args 執行根命令，回傳 stdout 與 stderr 的內容；args 會自動加上 -C dir
+func runCLI(t *testing.T, dir string, args ...string) (string, string, error) {
+	t.Helper()
+	stdoutFile, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderrFile, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutFile, stderrFile
+	cmd := newRootCommand()
+	cmd.SetArgs(append([]string{"-C", dir}, args...))
+	runErr := cmd.Execute()
+	os.Stdout, os.Stderr = stdout, stderr
+	workingDir = ""
+
+	outData, err := os.ReadFile(stdoutFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	errData, err := os.ReadFile(stderrFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(outData), string(errData), runErr
+}
+
+// withStdin 讓 os.Stdin 在測試期間讀取 input
+func withStdin(t *testing.T, input string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stdin")
+	writeTestFile(t, path, input, 0o644)
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = file
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		file.Close()
+	})
+}
+
+func TestPromptsShareStdin(t *testing.T) {
+	templates := map[string]map[string]string{
+		"greet": {
+			"template.yaml":  "name: greet\nvariables:\n  - name: Greeting\n    required: true\n  - name: Target\n    required: true\n",
+			"hello.txt.tmpl": "{{ .Greeting }}, {{ .Target }}\n",
+		},
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		input string
+	}{
+		{
+			name:  "variable prompts after the project name prompt",
+			args:  []string{"--interactive", "-t", "greet"},
+			input: "app\nhello\nworld\n",
+		},
+		{
+			name:  "variable prompts only",
+			args:  []string{"-n", "app", "-t", "greet"},
+			input: "hello\nworld\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			withStdin(t, tt.input)
+			if _, stderr, err := runCLI(t, dir, tt.args...); err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "app", "hello.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := string(data), "hello, world\n"; got != want {
+				t.Errorf("hello.txt = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPositionalArgs(t *testing.T) {
+	templates := map[string]map[string]string{
+		"component": {
+			"template.yaml":    "name: component\nargsOrder: [ProjectName, Variant]\nvariables:\n  - name: Variant\n    default: primary\n",
+			"variant.txt.tmpl": "{{ .Variant }}\n",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		project string
+		want    string
+		wantErr string
+	}{
+		{name: "project name and variable", args: []string{"-t", "component", "--no-input", "Button", "secondary"}, project: "Button", want: "secondary"},
+		{name: "default for a missing argument", args: []string{"-t", "component", "--no-input", "Button"}, project: "Button", want: "primary"},
+		{name: "--name and a positional project name", args: []string{"-n", "app", "-t", "component", "Button"}, wantErr: "both with --name and as a positional argument"},
+		{name: "--set and a positional variable", args: []string{"-t", "component", "--set", "Variant=x", "Button", "secondary"}, wantErr: "variable 'Variant' given both with --set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			_, stderr, err := runCLI(t, dir, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, tt.project, "variant.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(data)); got != tt.want {
+				t.Errorf("Variant = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestOnly(t *testing.T) {
+	templates := map[string]map[string]string{
+		"basic": {
+			"template.yaml": "name: basic\npostGenerate:\n  - command: touch ran.txt\n",
+			"main.go":       "package main\n",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "manifest written", args: []string{"-n", "app", "-t", "basic", "--manifest-only", "plan.json"}},
+		{name: "not with --dry-run", args: []string{"-n", "app", "-t", "basic", "--manifest-only", "plan.json", "--dry-run"}, wantErr: "--dry-run"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			stdout, stderr, err := runCLI(t, dir, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "plan.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), `"template": "basic"`) || !strings.Contains(string(data), `"main.go"`) {
+				t.Errorf("plan.json = %s", data)
+			}
+			if !strings.Contains(stdout, "nothing generated") {
+				t.Errorf("stdout = %q", stdout)
+			}
+			for _, name := range []string{"app", "ran.txt"} {
+				if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+					t.Errorf("%s exists after --manifest-only: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{name: "valid template in the working dir", config: "name: ok\n", want: []string{"Template is valid"}},
+		{name: "template path argument", config: "name: ok\n", args: []string{"tpl"}, want: []string{"Template is valid"}},
+		{
+			name:    "problems listed",
+			config:  "name: t\nvariables:\n  - name: DB\n    type: select\n",
+			want:    []string{"Template is invalid", "• variables[0].options: select variables need at least one option"},
+			wantErr: "1 problem(s) found in .",
+		},
+		{name: "missing template", args: []string{"missing"}, wantErr: "error validating template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, nil)
+			if tt.config != "" {
+				writeTestFile(t, filepath.Join(dir, "template.yaml"), tt.config, 0o644)
+				writeTestFile(t, filepath.Join(dir, "tpl", "template.yaml"), tt.config, 0o644)
+			}
+
+			stdout, _, err := runCLI(t, dir, append([]string{"validate"}, tt.args...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("error = %v\n%s", err, stdout)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("output missing %q:\n%s", want, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaCommand(t *testing.T) {
+	dir := cliEnv(t, nil)
+	stdout, _, err := runCLI(t, dir, "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout != string(template.ConfigSchema()) {
+		t.Errorf("schema output differs from the embedded schema:\n%s", stdout)
+	}
+}
+
+func TestModeFlags(t *testing.T) {
+	templates := map[string]map[string]string{
+		"basic": {"template.yaml": "name: basic\nfileMode: \"0640\"\n", "main.go": "package main\n"},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    os.FileMode
+		wantErr string
+	}{
+		{name: "template mode", want: 0o640},
+		{name: "flag overrides template", args: []string{"--file-mode", "0600"}, want: 0o600},
+		{name: "invalid file mode", args: []string{"--file-mode", "rw"}, wantErr: "--file-mode"},
+		{name: "invalid dir mode", args: []string{"--dir-mode", "0999"}, wantErr: "--dir-mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			_, stderr, err := runCLI(t, dir, append([]string{"-n", "app", "-t", "basic"}, tt.args...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			info, err := os.Stat(filepath.Join(dir, "app", "main.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != tt.want {
+				t.Errorf("main.go mode = %o, want %o", info.Mode().Perm(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceFlag(t *testing.T) {
+	dir := cliEnv(t, map[string]map[string]string{
+		"basic": {"template.yaml": "name: basic\n", "main.go": "package main\n"},
+	})
+	if _, stderr, err := runCLI(t, dir, "-n", "app", "-t", "basic", "--trace", "trace.jsonl"); err != nil {
+		t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "trace.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"event":"write"`) || !strings.Contains(string(data), `"target":"main.go"`) {
+		t.Errorf("trace.jsonl = %s", data)
+	}
+}
+
+func TestCheckUpdates(t *testing.T) {
+	dir := cliEnv(t, nil)
+	source := filepath.Join(dir, "src", "tpl")
+	writeTestFile(t, filepath.Join(source, "template.yaml"), "name: tpl\nversion: 1.0.0\n", 0o644)
+
+	stdout, _, err := runCLI(t, dir, "--check-updates")
+	if err != nil || !strings.Contains(stdout, "No installed templates with a recorded install source") {
+		t.Fatalf("--check-updates before install = %q, %v", stdout, err)
+	}
+
+	if _, stderr, err := runCLI(t, dir, "--install", source); err != nil {
+		t.Fatalf("--install error = %v\nstderr:\n%s", err, stderr)
+	}
+	writeTestFile(t, filepath.Join(source, "template.yaml"), "name: tpl\nversion: 1.1.0\n", 0o644)
+
+	stdout, _, err = runCLI(t, dir, "--check-updates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"tpl: 1.0.0 → 1.1.0", "1 update(s) available"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("stdout should contain %q, got:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestListSort(t *testing.T) {
+	dir := cliEnv(t, map[string]map[string]string{
+		"alpha": {"template.yaml": "name: alpha\nversion: 1.10.0\n"},
+		"beta":  {"template.yaml": "name: beta\nversion: 1.9.0\n"},
+	})
+
+	tests := []struct {
+		sort    string
+		want    string
+		wantErr string
+	}{
+		{sort: "name", want: "alpha,beta"},
+		{sort: "version", want: "beta,alpha"},
+		{sort: "size", wantErr: "invalid sort"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			stdout, _, err := runCLI(t, dir, "--list", "--source", "user", "--sort", tt.sort, "--list-format", "table")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, line := range strings.Split(strings.TrimSpace(stdout), "\n")[1:] {
+				names = append(names, strings.Fields(line)[0])
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("--sort %s listed %s, want %s\n%s", tt.sort, got, tt.want, stdout)
+			}
+		})
+	}
+}
+
+func TestInteractiveTemplate(t *testing.T) {
+	templates := map[string]map[string]string{
+		"mine": {"template.yaml": "name: mine\n", "main.go": "package main\n"},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		wantMenu bool
+		wantErr  string
+	}{
+		{name: "--template skips the menu", args: []string{"--interactive", "-t", "mine"}, input: "app\n"},
+		// 內建模板排在前面，用戶模板為第 3 個
+		{name: "menu without --template", args: []string{"--interactive"}, input: "3\napp\n", wantMenu: true},
+		{name: "unknown --template", args: []string{"--interactive", "-t", "nope"}, input: "app\n", wantErr: "template 'nope' not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			withStdin(t, tt.input)
+			stdout, stderr, err := runCLI(t, dir, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if strings.Contains(stdout, "Enter project name") {
+					t.Error("prompted for a project name before rejecting the template")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			if shown := strings.Contains(stdout, "Available templates"); shown != tt.wantMenu {
+				t.Errorf("template menu shown = %v, want %v\n%s", shown, tt.wantMenu, stdout)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "app", "main.go")); err != nil {
+				t.Errorf("project was not generated from mine: %v", err)
+			}
+		})
+	}
+}
+
+func TestShowNextSteps(t *testing.T) {
+	templates := map[string]map[string]string{
+		"plain":  {"template.yaml": "name: plain\n", "main.go": "package main\n"},
+		"guided": {"template.yaml": "name: guided\nnextSteps:\n  - go run ./cmd/{{ .ProjectName }}\n", "main.go": "package main\n"},
+	}
+
+	tests := []struct {
+		template string
+		want     string
+		notWant  string
+	}{
+		{template: "plain", want: "make install    # Install dependencies"},
+		{template: "guided", want: "   go run ./cmd/app\n", notWant: "make install"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			stdout, stderr, err := runCLI(t, dir, "-n", "app", "-t", tt.template)
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			if !strings.Contains(stdout, "cd "+filepath.Join(dir, "app")) || !strings.Contains(stdout, tt.want) {
+				t.Errorf("next steps should contain %q, got:\n%s", tt.want, stdout)
+			}
+			if tt.notWant != "" && strings.Contains(stdout, tt.notWant) {
+				t.Errorf("next steps should not contain %q, got:\n%s", tt.notWant, stdout)
+			}
+		})
+	}
+}
+
+func TestInstallMultiple(t *testing.T) {
+	dir := cliEnv(t, nil)
+	for _, name := range []string{"one", "two", "three"} {
+		writeTestFile(t, filepath.Join(dir, "src", name, "template.yaml"), "name: "+name+"\n", 0o644)
+	}
+	src := func(name string) string { return filepath.Join(dir, "src", name) }
+
+	stdout, _, err := runCLI(t, dir, "--install", src("one"), "--install", src("missing"), src("two"), src("three"))
+	if err == nil || !strings.Contains(err.Error(), "failed to install 1 of 4 template(s)") {
+		t.Fatalf("error = %v, want one failure out of four", err)
+	}
+	for _, want := range []string{"📋 Install summary:", "❌ " + src("missing"), "✅ " + src("three")} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("stdout should contain %q, got:\n%s", want, stdout)
+		}
+	}
+
+	// 失敗的來源不影響其餘安裝
+	templatesDir := filepath.Join(os.Getenv("HOME"), "data", "aaa-generator", "templates")
+	for _, name := range []string{"one", "two", "three"} {
+		if _, err := os.Stat(filepath.Join(templatesDir, name, "template.yaml")); err != nil {
+			t.Errorf("%s was not installed: %v", name, err)
+		}
+	}
+
+	writeTestFile(t, filepath.Join(dir, "src", "four", "template.yaml"), "name: four\n", 0o644)
+	stdout, _, err = runCLI(t, dir, "--install", src("four"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stdout, "Install summary") {
+		t.Errorf("a single install should not print a summary:\n%s", stdout)
+	}
+}
+
+func TestInstallModeFlags(t *testing.T) {
+	dir := cliEnv(t, nil)
+	source := filepath.Join(dir, "src", "tpl")
+	writeTestFile(t, filepath.Join(source, "template.yaml"), "name: tpl\n", 0o644)
+	if _, _, err := runCLI(t, dir, "--install", source); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := runCLI(t, dir, "--install", source, "--if-not-present")
+	if err != nil || !strings.Contains(stdout, "Template 'tpl' is already installed, skipping") {
+		t.Errorf("--if-not-present = %q, %v", stdout, err)
+	}
+	if _, _, err := runCLI(t, dir, "--install", source, "--if-not-present", "--reinstall"); err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("--if-not-present with --reinstall error = %v", err)
+	}
+}
+
+func TestListShowPaths(t *testing.T) {
+	dir := cliEnv(t, map[string]map[string]string{"mine": {"template.yaml": "name: mine\n"}})
+	userPath := filepath.Join(os.Getenv("XDG_DATA_HOME"), "aaa-generator", "templates", "mine")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{name: "hidden by default", args: []string{"--list"}, notWant: []string{"📁", "(embedded)"}},
+		{name: "text", args: []string{"--list", "--show-paths"}, want: []string{"📁 (embedded)", "📁 " + userPath}},
+		{name: "table", args: []string{"--list", "--show-paths", "--list-format", "table"}, want: []string{"PATH", "(embedded)", userPath}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, _, err := runCLI(t, dir, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("output should contain %q, got:\n%s", want, stdout)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(stdout, notWant) {
+					t.Errorf("output should not contain %q, got:\n%s", notWant, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestTempFlag(t *testing.T) {
+	dir := cliEnv(t, map[string]map[string]string{"plain": {"template.yaml": "name: plain\n", "main.go": "package main\n"}})
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	// 工作目錄已有同名目錄也不影響，專案寫到新的暫存目錄
+	if err := os.Mkdir(filepath.Join(dir, "app"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI(t, dir, "-n", "app", "-t", "plain", "--temp")
+	if err != nil {
+		t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+	}
+	_, after, ok := strings.Cut(stdout, "📁 Generated in: ")
+	if !ok {
+		t.Fatalf("output does not report the temp directory:\n%s", stdout)
+	}
+	projectDir := strings.TrimSpace(after)
+	if !strings.HasPrefix(projectDir, filepath.Join(tmp, "app-")) || filepath.Base(projectDir) != "app" {
+		t.Errorf("generated in %s, want app under a new directory in %s", projectDir, tmp)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, "main.go")); err != nil {
+		t.Errorf("main.go missing: %v", err)
+	}
+	if !strings.Contains(stdout, "cd "+projectDir) {
+		t.Errorf("next steps should cd into %s, got:\n%s", projectDir, stdout)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(dir, "app")); len(entries) != 0 {
+		t.Errorf("--temp wrote %d entries into the working directory", len(entries))
+	}
+}
+
+func TestListConflicts(t *testing.T) {
+	dir := cliEnv(t, map[string]map[string]string{"basic": {"template.yaml": "name: basic\n"}})
+	const warning = "template 'basic' defined in both user and built-in sources; user wins"
+
+	tests := []struct {
+		format     string
+		wantStdout bool
+	}{
+		{format: "detailed", wantStdout: true},
+		// 機器可讀的格式只把警告寫到 stderr
+		{format: "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			stdout, stderr, err := runCLI(t, dir, "--list", "--list-format", tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(stdout, warning); got != tt.wantStdout {
+				t.Errorf("warning on stdout = %v, want %v:\n%s", got, tt.wantStdout, stdout)
+			}
+			if !tt.wantStdout && !strings.Contains(stderr, warning) {
+				t.Errorf("stderr should contain the conflict, got:\n%s", stderr)
+			}
+		})
+	}
+}
+
+func TestNoInputFlag(t *testing.T) {
+	dir := cliEnv(t, map[string]map[string]string{
+		"owned": {"template.yaml": "name: owned\nvariables:\n  - name: Owner\n    required: true\n", "main.go": "package main\n"},
+	})
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "missing required variable", args: []string{"-n", "app", "-t", "owned", "--no-input"}, wantErr: "variable 'Owner' is required"},
+		{name: "with interactive", args: []string{"-i", "--no-input"}, wantErr: "cannot be combined with --interactive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 即使有可讀的輸入也不應被使用
+			withStdin(t, "me\n")
+			_, _, err := runCLI(t, dir, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPathOnly(t *testing.T) {
+	templates := map[string]map[string]string{"plain": {"template.yaml": "name: plain\n", "main.go": "package main\n"}}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "project path on stdout", args: []string{"-n", "app", "-t", "plain", "--path-only"}},
+		{name: "nested name", args: []string{"-n", "apps/api", "-t", "plain", "--path-only"}},
+		{name: "with dry-run", args: []string{"-n", "app", "-t", "plain", "--path-only", "--dry-run"}, wantErr: "--path-only cannot be combined"},
+		{name: "with interactive", args: []string{"-i", "--path-only"}, wantErr: "--path-only cannot be combined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			stdout, stderr, err := runCLI(t, dir, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			// stdout 只有路徑，可直接用於 cd "$(generator ... --path-only)"
+			want := filepath.Join(dir, filepath.FromSlash(tt.args[1])) + "\n"
+			if stdout != want {
+				t.Errorf("stdout = %q, want %q", stdout, want)
+			}
+			if !strings.Contains(stderr, "Creating project") {
+				t.Errorf("progress should go to stderr, got:\n%s", stderr)
+			}
+		})
+	}
+}
+
+func TestAssumeYes(t *testing.T) {
+	templates := map[string]map[string]string{
+		"defaults": {"template.yaml": "name: defaults\nvariables:\n  - name: Owner\n    required: true\n    default: me\n", "owner.txt.tmpl": "{{ .Owner }}"},
+		"owned":    {"template.yaml": "name: owned\nvariables:\n  - name: Owner\n    required: true\n", "owner.txt.tmpl": "{{ .Owner }}"},
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		// want 為產生的 owner.txt；wantErr 非空時預期失敗
+		want    string
+		wantErr string
+	}{
+		{name: "default taken without prompting", args: []string{"-n", "app", "-t", "defaults", "-y"}, stdin: "you\n", want: "me"},
+		{name: "--set still wins", args: []string{"-n", "app", "-t", "defaults", "--assume-yes", "--set", "Owner=you"}, want: "you"},
+		{name: "required without default", args: []string{"-n", "app", "-t", "owned", "-y"}, stdin: "you\n", wantErr: "variable 'Owner' is required"},
+		{name: "with --no-input", args: []string{"-n", "app", "-t", "defaults", "-y", "--no-input"}, wantErr: "--no-input and --assume-yes cannot be used together"},
+		{name: "with --interactive", args: []string{"-i", "-y"}, wantErr: "cannot be combined with --interactive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			withStdin(t, tt.stdin)
+			_, stderr, err := runCLI(t, dir, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			if data, err := os.ReadFile(filepath.Join(dir, "app", "owner.txt")); err != nil || string(data) != tt.want {
+				t.Errorf("owner.txt = %q, %v; want %q", data, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmations(t *testing.T) {
+	templates := map[string]map[string]string{"plain": {"template.yaml": "name: plain\n", "main.go": "package main\n"}}
+
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		want    string
+		wantErr bool
+	}{
+		{name: "answered yes", stdin: "y\n", want: "contains the current directory? [y/N]: "},
+		{name: "answered no", stdin: "n\n", wantErr: true},
+		{name: "no answer", wantErr: true},
+		{name: "assume yes", args: []string{"-y"}, want: "[y/N]: y (--assume-yes)"},
+		// --no-input 不讀取輸入，確認一律視為拒絕
+		{name: "no input declines", args: []string{"--no-input"}, stdin: "y\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			t.Chdir(dir)
+			withStdin(t, tt.stdin)
+			stdout, stderr, err := runCLI(t, dir, append([]string{"-n", ".", "-t", "plain", "--force"}, tt.args...)...)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "refusing to generate into") {
+					t.Fatalf("error = %v, want the current directory to be refused", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			if !strings.Contains(stdout, tt.want) {
+				t.Errorf("output should contain %q, got:\n%s", tt.want, stdout)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "main.go")); err != nil {
+				t.Errorf("main.go was not generated: %v", err)
+			}
+		})
+	}
+}
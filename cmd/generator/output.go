@@ -0,0 +1,8 @@
+package main
+
+import "os"
+
+// noEmojiFromEnv 回傳環境變數是否要求純 ASCII 輸出（NO_COLOR 或 GENERATOR_NO_EMOJI）
+func noEmojiFromEnv() bool {
+	return os.Getenv("NO_COLOR") != "" || os.Getenv("GENERATOR_NO_EMOJI") != ""
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aaa-generator/internal/console"
+)
+
+func TestNoEmojiFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		noColor string
+		noEmoji string
+		want    bool
+	}{
+		{name: "unset"},
+		{name: "NO_COLOR", noColor: "1", want: true},
+		{name: "GENERATOR_NO_EMOJI", noEmoji: "1", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("GENERATOR_NO_EMOJI", tt.noEmoji)
+			if got := noEmojiFromEnv(); got != tt.want {
+				t.Errorf("noEmojiFromEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// isASCII 回傳 s 是否只包含 ASCII 字元
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNoEmojiOnStderr(t *testing.T) {
+	// post-generate 命令的輸出與警告也要經過轉換
+	templates := map[string]map[string]string{
+		"plain": {
+			"template.yaml": "name: plain\npostGenerate:\n  - command: \"echo '✅ from the command'\"\n  - command: \"echo '❌ failed' >&2; exit 1\"\n",
+			"main.go":       "package main\n",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdout func(t *testing.T, dir, stdout string)
+	}{
+		{
+			name: "path-only",
+			args: []string{"-n", "app", "-t", "plain", "--no-input", "--path-only", "--no-emoji"},
+			stdout: func(t *testing.T, dir, stdout string) {
+				if want := filepath.Join(dir, "app") + "\n"; stdout != want {
+					t.Errorf("stdout = %q, want %q", stdout, want)
+				}
+			},
+		},
+		{
+			name: "json",
+			args: []string{"-n", "app", "-t", "plain", "--no-input", "--json", "--no-emoji"},
+			stdout: func(t *testing.T, dir, stdout string) {
+				var summary map[string]interface{}
+				if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+					t.Errorf("stdout is not JSON: %v\n%s", err, stdout)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			t.Cleanup(func() { console.SetPlain(false) })
+			stdout, stderr, err := runCLI(t, dir, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), "exit status 1") {
+				t.Fatalf("error = %v, want the failing post-generate command\nstderr:\n%s", err, stderr)
+			}
+			tt.stdout(t, dir, stdout)
+			if !strings.Contains(stderr, "[ok] from the command") || !strings.Contains(stderr, "[x] failed") {
+				t.Errorf("post-generate output missing from stderr:\n%s", stderr)
+			}
+			if !isASCII(stderr) {
+				t.Errorf("stderr is not plain ASCII:\n%s", stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"aaa-generator/internal/template"
+)
+
+// templatePageSize 為互動模式每頁列出的模板數量
+const templatePageSize = 10
+
+// selectTemplate 讓使用者選擇模板。終端機模式下可輸入文字模糊篩選清單，
+// 非終端機（例如管線輸入）時只接受編號。模板超過一頁時按 Enter 顯示下一頁，
+// 編號在各頁之間連續，因此任何已顯示的編號都可直接選擇。
+func selectTemplate(out io.Writer, reader *bufio.Reader, templates []template.TemplateInfo) (template.TemplateInfo, error) {
+	filtering := isTerminal(os.Stdin)
+	current := templates
+	page := 0
+
+	printTemplateChoices(out, current, page)
+
+	for {
+		prompt := fmt.Sprintf("\nSelect template (1-%d)", len(current))
+		if filtering {
+			prompt += " or type to filter"
+		}
+		if page+1 < pageCount(len(current), templatePageSize) {
+			prompt += ", Enter for more"
+		}
+		fmt.Fprint(out, prompt+": ")
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return template.TemplateInfo{}, fmt.Errorf("failed to read input: %w", err)
+		}
+
+		input = strings.TrimSpace(input)
+		if input == "" {
+			pages := pageCount(len(current), templatePageSize)
+			switch {
+			case page+1 < pages:
+				page++
+			case filtering && len(current) != len(templates):
+				current, page = templates, 0
+			case pages > 1:
+				page = 0
+			default:
+				fmt.Fprintln(out, "Please enter a number.")
+				continue
+			}
+			printTemplateChoices(out, current, page)
+			continue
+		}
+
+		value, err := strconv.Atoi(input)
+		if err == nil {
+			if value < 1 || value > len(current) {
+				fmt.Fprintln(out, "Invalid selection. Try again.")
+				continue
+			}
+			return current[value-1], nil
+		}
+
+		if !filtering {
+			fmt.Fprintln(out, "Invalid selection. Try again.")
+			continue
+		}
+
+		matches := filterTemplates(templates, input)
+		if len(matches) == 0 {
+			fmt.Fprintf(out, "No templates match '%s'. Try again (empty input shows all).\n", input)
+			continue
+		}
+		current, page = matches, 0
+		printTemplateChoices(out, current, page)
+	}
+}
+
+// printTemplateChoices 列出第 page 頁（從 0 開始）的模板，編號為在整份清單中的位置
+func printTemplateChoices(out io.Writer, templates []template.TemplateInfo, page int) {
+	pages := pageCount(len(templates), templatePageSize)
+	if pages > 1 {
+		fmt.Fprintf(out, "Available templates (page %d/%d):\n", page+1, pages)
+	} else {
+		fmt.Fprintln(out, "Available templates:")
+	}
+
+	start, end := pageBounds(len(templates), page, templatePageSize)
+	for i := start; i < end; i++ {
+		tmpl := templates[i]
+		marker := ""
+		if tmpl.Deprecated {
+			marker = " (deprecated)"
+		}
+		fmt.Fprintf(out, "%d) %s%s - %s\n", i+1, tmpl.DisplayName, marker, tmpl.Description)
+	}
+}
+
+// pageCount 回傳 total 個項目以每頁 size 個分頁的頁數；沒有項目時仍為一頁
+func pageCount(total, size int) int {
+	if total <= 0 || size <= 0 {
+		return 1
+	}
+	return (total + size - 1) / size
+}
+
+// pageBounds 回傳第 page 頁的項目範圍 [start, end)；超出範圍的頁數會限制在最後一頁
+func pageBounds(total, page, size int) (start, end int) {
+	if size <= 0 {
+		return 0, total
+	}
+	if last := pageCount(total, size) - 1; page > last {
+		page = last
+	}
+	if page < 0 {
+		page = 0
+	}
+	start = page * size
+	end = start + size
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// filterTemplates 回傳名稱、顯示名稱、描述或標籤與 query 模糊相符的模板。
+// query 以空白分隔的每個詞都必須相符。
+func filterTemplates(templates []template.TemplateInfo, query string) []template.TemplateInfo {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return templates
+	}
+
+	var matches []template.TemplateInfo
+	for _, tmpl := range templates {
+		fields := append([]string{tmpl.Name, tmpl.DisplayName, tmpl.Description}, tmpl.Tags...)
+		haystack := strings.ToLower(strings.Join(fields, " "))
+
+		matched := true
+		for _, term := range terms {
+			if !fuzzyContains(haystack, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, tmpl)
+		}
+	}
+	return matches
+}
+
+// fuzzyContains 判斷 term 的字元是否依序出現在 s 中（不必相鄰）
+func fuzzyContains(s, term string) bool {
+	runes := []rune(term)
+	i := 0
+	for _, r := range s {
+		if i < len(runes) && r == runes[i] {
+			i++
+		}
+	}
+	return i == len(runes)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
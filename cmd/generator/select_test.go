@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aaa-generator/internal/template"
+)
+
+func TestFuzzyContains(t *testing.T) {
+	tests := []struct {
+		s, term string
+		want    bool
+	}{
+		{s: "go react fullstack", term: "react", want: true},
+		{s: "go react fullstack", term: "grf", want: true},
+		{s: "go react fullstack", term: "fsk", want: true},
+		{s: "go react fullstack", term: "kcats", want: false},
+		{s: "api", term: "", want: true},
+		{s: "日本語テンプレート", term: "日語", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s+"/"+tt.term, func(t *testing.T) {
+			if got := fuzzyContains(tt.s, tt.term); got != tt.want {
+				t.Errorf("fuzzyContains(%q, %q) = %v, want %v", tt.s, tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterTemplates(t *testing.T) {
+	templates := []template.TemplateInfo{
+		{Name: "basic", DisplayName: "Basic Go", Description: "Minimal backend"},
+		{Name: "advance", DisplayName: "Advanced", Description: "Go + React", Tags: []string{"frontend", "postgres"}},
+		{Name: "cli", DisplayName: "CLI Tool", Description: "Cobra command line"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{"basic", "advance", "cli"}},
+		{query: "REACT", want: []string{"advance"}},
+		{query: "postgres", want: []string{"advance"}},
+		{query: "go", want: []string{"basic", "advance"}},
+		{query: "go min", want: []string{"basic"}},
+		{query: "cbr", want: []string{"cli"}},
+		{query: "rust", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []string
+			for _, tmpl := range filterTemplates(templates, tt.query) {
+				got = append(got, tmpl.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterTemplates(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		total, page, size int
+		wantPages         int
+		wantStart         int
+		wantEnd           int
+	}{
+		{total: 0, page: 0, size: 10, wantPages: 1, wantStart: 0, wantEnd: 0},
+		{total: 7, page: 0, size: 10, wantPages: 1, wantStart: 0, wantEnd: 7},
+		{total: 10, page: 0, size: 10, wantPages: 1, wantStart: 0, wantEnd: 10},
+		{total: 25, page: 1, size: 10, wantPages: 3, wantStart: 10, wantEnd: 20},
+		{total: 25, page: 2, size: 10, wantPages: 3, wantStart: 20, wantEnd: 25},
+		{total: 25, page: 9, size: 10, wantPages: 3, wantStart: 20, wantEnd: 25},
+		{total: 25, page: -1, size: 10, wantPages: 3, wantStart: 0, wantEnd: 10},
+		{total: 25, page: 1, size: 0, wantPages: 1, wantStart: 0, wantEnd: 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%d/%d", tt.total, tt.page, tt.size), func(t *testing.T) {
+			if got := pageCount(tt.total, tt.size); got != tt.wantPages {
+				t.Errorf("pageCount() = %d, want %d", got, tt.wantPages)
+			}
+			start, end := pageBounds(tt.total, tt.page, tt.size)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("pageBounds() = [%d, %d), want [%d, %d)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestSelectTemplatePaging(t *testing.T) {
+	var templates []template.TemplateInfo
+	for i := 1; i <= 23; i++ {
+		name := fmt.Sprintf("tpl%02d", i)
+		templates = append(templates, template.TemplateInfo{Name: name, DisplayName: name})
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		// wantOutput 為輸出中應依序出現的文字
+		wantOutput []string
+		wantErr    bool
+	}{
+		{name: "first page", input: "3\n", want: "tpl03", wantOutput: []string{"(page 1/3)", "10) tpl10", "Enter for more"}},
+		{name: "number from a later page", input: "\n\n22\n", want: "tpl22", wantOutput: []string{"(page 2/3)", "11) tpl11", "(page 3/3)", "23) tpl23"}},
+		{name: "wraps to the first page", input: "\n\n\n1\n", want: "tpl01", wantOutput: []string{"(page 3/3)", "(page 1/3)"}},
+		{name: "invalid then valid", input: "99\nabc\n5\n", want: "tpl05", wantOutput: []string{"Invalid selection", "Invalid selection"}},
+		{name: "input ends", input: "99\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 以檔案作為 stdin，使選單進入只接受編號的非終端機模式
+			withStdin(t, "")
+			var out bytes.Buffer
+			got, err := selectTemplate(&out, bufio.NewReader(strings.NewReader(tt.input)), templates)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("selectTemplate() = %s, want an error", got.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectTemplate() error = %v", err)
+			}
+			if got.Name != tt.want {
+				t.Errorf("selectTemplate() = %s, want %s", got.Name, tt.want)
+			}
+
+			output := out.String()
+			if strings.Contains(output, "type to filter") {
+				t.Errorf("non-terminal prompt offers filtering:\n%s", output)
+			}
+			for _, want := range tt.wantOutput {
+				i := strings.Index(output, want)
+				if i < 0 {
+					t.Fatalf("output is missing %q after the previous match:\n%s", want, out.String())
+				}
+				output = output[i+len(want):]
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// parseSetValues 解析 --set Key=Value 參數。
+// 值以 @ 開頭時表示從檔案讀取內容，@- 則從標準輸入讀取。
+func parseSetValues(values []string, stdin io.Reader) (map[string]string, error) {
+	result := make(map[string]string, len(values))
+	stdinUsed := false
+
+	for _, entry := range values {
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set value %q (expected Key=Value)", entry)
+		}
+
+		if strings.HasPrefix(value, "@") {
+			source := strings.TrimPrefix(value, "@")
+			var data []byte
+			var err error
+
+			switch source {
+			case "":
+				return nil, fmt.Errorf("invalid --set value %q: missing file path after @", entry)
+			case "-":
+				if stdinUsed {
+					return nil, fmt.Errorf("invalid --set value %q: stdin can only be read once", entry)
+				}
+				stdinUsed = true
+				data, err = io.ReadAll(stdin)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read value for %s from stdin: %w", key, err)
+				}
+			default:
+				data, err = os.ReadFile(workPath(source))
+				if err != nil {
+					if os.IsNotExist(err) {
+						return nil, fmt.Errorf("value file for %s not found: %s", key, source)
+					}
+					return nil, fmt.Errorf("failed to read value file for %s: %w", key, err)
+				}
+			}
+			value = string(data)
+		}
+
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// parseReplaceValues 解析 --replace old=new 參數；old 為要取代的字面字串，不可為空
+func parseReplaceValues(values []string) (map[string]string, error) {
+	result := make(map[string]string, len(values))
+	for _, entry := range values {
+		old, value, ok := strings.Cut(entry, "=")
+		if !ok || old == "" {
+			return nil, fmt.Errorf("invalid --replace value %q (expected old=new)", entry)
+		}
+		result[old] = value
+	}
+	return result, nil
+}
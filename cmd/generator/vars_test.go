@@ -0,0 +1,121 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSetValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		stdin   string
+		files   map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:   "plain values",
+			values: []string{"Port=8080", " Name =demo", "Empty=", "Expr=a=b"},
+			want:   map[string]string{"Port": "8080", "Name": "demo", "Empty": "", "Expr": "a=b"},
+		},
+		{
+			name:   "later value wins",
+			values: []string{"Port=8080", "Port=9090"},
+			want:   map[string]string{"Port": "9090"},
+		},
+		{
+			name:   "value from a file relative to the working directory",
+			values: []string{"License=@LICENSE.txt"},
+			files:  map[string]string{"LICENSE.txt": "MIT\n"},
+			want:   map[string]string{"License": "MIT\n"},
+		},
+		{
+			name:   "value from stdin",
+			values: []string{"Key=@-"},
+			stdin:  "secret\n",
+			want:   map[string]string{"Key": "secret\n"},
+		},
+		{
+			name:    "missing key",
+			values:  []string{"=value"},
+			wantErr: "expected Key=Value",
+		},
+		{
+			name:    "missing separator",
+			values:  []string{"Port"},
+			wantErr: "expected Key=Value",
+		},
+		{
+			name:    "missing file path",
+			values:  []string{"License=@"},
+			wantErr: "missing file path after @",
+		},
+		{
+			name:    "missing file",
+			values:  []string{"License=@nope.txt"},
+			wantErr: "value file for License not found: nope.txt",
+		},
+		{
+			name:    "stdin read twice",
+			values:  []string{"A=@-", "B=@-"},
+			stdin:   "x",
+			wantErr: "stdin can only be read once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workingDir = t.TempDir()
+			t.Cleanup(func() { workingDir = "" })
+			for name, content := range tt.files {
+				writeTestFile(t, filepath.Join(workingDir, name), content, 0o644)
+			}
+
+			got, err := parseSetValues(tt.values, strings.NewReader(tt.stdin))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseSetValues() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSetValues() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSetValues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReplaceValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "literal pairs",
+			values: []string{"__AUTHOR__=Jane Doe", "a=b=c", "gone="},
+			want:   map[string]string{"__AUTHOR__": "Jane Doe", "a": "b=c", "gone": ""},
+		},
+		{name: "empty old", values: []string{"=new"}, wantErr: true},
+		{name: "missing separator", values: []string{"old"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReplaceValues(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReplaceValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseReplaceValues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
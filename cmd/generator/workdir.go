@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// workingDir 為 -C/--working-dir 指定的基準目錄；相對的輸出路徑與安裝來源都以此解析，而非目前工作目錄
+var workingDir string
+
+// checkWorkingDir 確認 --working-dir 指向既有的目錄
+func checkWorkingDir() error {
+	if workingDir == "" {
+		return nil
+	}
+	info, err := os.Stat(workingDir)
+	if err != nil {
+		return fmt.Errorf("--working-dir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("--working-dir: %s is not a directory", workingDir)
+	}
+	return nil
+}
+
+// workPath 將命令列上的相對路徑解析到 --working-dir 之下；未指定時原樣回傳
+func workPath(path string) string {
+	if workingDir == "" || path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(workingDir, path)
+}
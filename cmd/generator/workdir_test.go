@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkingDir(t *testing.T) {
+	templates := map[string]map[string]string{
+		"basic": {"template.yaml": "name: basic\n", "main.go": "package main\n"},
+	}
+
+	tests := []struct {
+		name string
+		// args 之前會加上 -C <dir>；{dir} 代換為該目錄
+		args          []string
+		setup         func(t *testing.T, dir string)
+		wantFile      string
+		wantInstalled string
+		wantErr       string
+	}{
+		{name: "project created under -C", args: []string{"-n", "app", "-t", "basic"}, wantFile: "app/main.go"},
+		{name: "nested project path", args: []string{"-n", "services/api", "-t", "basic"}, wantFile: "services/api/main.go"},
+		{
+			name: "relative install source",
+			args: []string{"--install", "local-tpl"},
+			setup: func(t *testing.T, dir string) {
+				writeTestFile(t, filepath.Join(dir, "local-tpl", "template.yaml"), "name: local\n", 0o644)
+			},
+			wantInstalled: "local",
+		},
+		{name: "missing directory", args: []string{"-C", "{dir}/missing", "-n", "app", "-t", "basic"}, wantErr: "--working-dir:"},
+		{
+			name:    "not a directory",
+			args:    []string{"-C", "{dir}/file", "-n", "app", "-t", "basic"},
+			setup:   func(t *testing.T, dir string) { writeTestFile(t, filepath.Join(dir, "file"), "", 0o644) },
+			wantErr: "is not a directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := cliEnv(t, templates)
+			if tt.setup != nil {
+				tt.setup(t, dir)
+			}
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				args[i] = strings.ReplaceAll(arg, "{dir}", dir)
+			}
+
+			_, stderr, err := runCLI(t, dir, args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v\nstderr:\n%s", err, stderr)
+			}
+			if tt.wantFile != "" {
+				if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(tt.wantFile))); err != nil {
+					t.Errorf("%s not created under -C: %v", tt.wantFile, err)
+				}
+			}
+			if tt.wantInstalled != "" {
+				installed := filepath.Join(os.Getenv("HOME"), "data", "aaa-generator", "templates", tt.wantInstalled, "template.yaml")
+				if _, err := os.Stat(installed); err != nil {
+					t.Errorf("template %s not installed: %v", tt.wantInstalled, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		workingDir string
+		path       string
+		want       string
+	}{
+		{name: "no working dir", path: "out.json", want: "out.json"},
+		{name: "relative path", workingDir: "/work", path: "out.json", want: filepath.Join("/work", "out.json")},
+		{name: "absolute path", workingDir: "/work", path: "/tmp/out.json", want: "/tmp/out.json"},
+		{name: "empty path", workingDir: "/work", path: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workingDir = tt.workingDir
+			t.Cleanup(func() { workingDir = "" })
+			if got := workPath(tt.path); got != tt.want {
+				t.Errorf("workPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
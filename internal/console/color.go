@@ -0,0 +1,53 @@
+// Package console 提供狀態訊息的色彩輸出：成功為綠色、警告為黃色、錯誤為紅色；並以 Printer 提供純 ASCII 輸出（見 SetPlain）
+package console
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	green  = "32"
+	yellow = "33"
+	red    = "31"
+)
+
+var enabled bool
+
+// SetColor 開啟或關閉色彩輸出
+func SetColor(on bool) {
+	enabled = on
+}
+
+// ResolveColor 依 --color 的值（auto、always、never）決定是否使用色彩；
+// auto 只在輸出為終端機且未設定 NO_COLOR 時啟用
+func ResolveColor(mode string, isTTY bool, noColorEnv bool) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", "auto":
+		return isTTY && !noColorEnv, nil
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid --color %q (expected auto, always or never)", mode)
+	}
+}
+
+// Success 以綠色標示成功訊息
+func Success(s string) string { return wrap(green, s) }
+
+// Warning 以黃色標示警告訊息
+func Warning(s string) string { return wrap(yellow, s) }
+
+// Failure 以紅色標示錯誤訊息
+func Failure(s string) string { return wrap(red, s) }
+
+// wrap 為 s 加上色碼；結尾的換行保留在色碼之外，可直接用於 Printf 的格式字串
+func wrap(code, s string) string {
+	if !enabled || s == "" {
+		return s
+	}
+	text := strings.TrimRight(s, "\n")
+	return "\033[" + code + "m" + text + "\033[0m" + s[len(text):]
+}
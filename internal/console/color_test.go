@@ -0,0 +1,68 @@
+package console
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveColor(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		isTTY      bool
+		noColorEnv bool
+		want       bool
+		wantErr    bool
+	}{
+		{name: "auto on a terminal", mode: "auto", isTTY: true, want: true},
+		{name: "auto without a terminal", mode: "auto", isTTY: false, want: false},
+		{name: "auto with NO_COLOR", mode: "auto", isTTY: true, noColorEnv: true, want: false},
+		{name: "empty mode is auto", mode: "", isTTY: true, want: true},
+		{name: "always without a terminal", mode: "always", isTTY: false, want: true},
+		{name: "never on a terminal", mode: "Never", isTTY: true, want: false},
+		{name: "invalid mode", mode: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveColor(tt.mode, tt.isTTY, tt.noColorEnv)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveColor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusColors(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		fn      func(string) string
+		in      string
+		want    string
+	}{
+		{name: "success", enabled: true, fn: Success, in: "✅ done\n", want: "\033[32m✅ done\033[0m\n"},
+		{name: "warning", enabled: true, fn: Warning, in: "⚠️  careful", want: "\033[33m⚠️  careful\033[0m"},
+		{name: "failure", enabled: true, fn: Failure, in: "Error: %s\n", want: "\033[31mError: %s\033[0m\n"},
+		{name: "empty string", enabled: true, fn: Success, in: "", want: ""},
+		{name: "disabled", enabled: false, fn: Failure, in: "❌ failed\n", want: "❌ failed\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetColor(tt.enabled)
+			t.Cleanup(func() { SetColor(false) })
+
+			got := tt.fn(tt.in)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if !tt.enabled && strings.Contains(got, "\033[") {
+				t.Errorf("color codes present with color disabled: %q", got)
+			}
+		})
+	}
+}
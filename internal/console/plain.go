@@ -0,0 +1,121 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// plainMarkers 為純 ASCII 輸出時取代狀態符號的標記
+var plainMarkers = map[rune]string{
+	'✅':      "[ok]",
+	'❌':      "[x]",
+	'⚠':      "[!]",
+	'ℹ':      "[i]",
+	'🔄':      "...",
+	'•':      "-",
+	'→':      "->",
+	'▸':      ">",
+	'─':      "-",
+	'│':      "|",
+	'╭':      "+",
+	'╮':      "+",
+	'╰':      "+",
+	'╯':      "+",
+	'\uFE0F': "", // emoji 變體選擇符
+}
+
+var plain bool
+
+// SetPlain 開啟或關閉純 ASCII 輸出；開啟時 Printer 會把 emoji 與框線換成 ASCII 標記
+func SetPlain(on bool) {
+	plain = on
+}
+
+// plainRune 將單一字元轉為 ASCII：已知的狀態符號換成標記，其餘 emoji 換成 *，其他非 ASCII 字元換成 ?
+func plainRune(r rune) string {
+	if r < utf8.RuneSelf {
+		return string(r)
+	}
+	if marker, ok := plainMarkers[r]; ok {
+		return marker
+	}
+	if r >= 0x1F000 || (r >= 0x2600 && r <= 0x27BF) {
+		return "*"
+	}
+	return "?"
+}
+
+// PlainText 將整段文字轉為純 ASCII
+func PlainText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		b.WriteString(plainRune(r))
+	}
+	return b.String()
+}
+
+// Printer 寫出狀態訊息。stdout 與 stderr 各用一個 Printer，
+// SetPlain 開啟時兩者寫出的內容（包含 post-generate 命令的輸出）都經過 PlainText 轉換
+type Printer struct {
+	w  io.Writer
+	mu sync.Mutex
+	// pending 為上次寫入結尾不完整的 UTF-8 字元，與下次寫入的內容一起轉換
+	pending []byte
+}
+
+// NewPrinter 回傳寫到 w 的 Printer
+func NewPrinter(w io.Writer) *Printer {
+	return &Printer{w: w}
+}
+
+// Write 實作 io.Writer，讓 Printer 可用於 fmt.Fprintf 與子行程的輸出
+func (p *Printer) Write(data []byte) (int, error) {
+	if !plain {
+		return p.w.Write(data)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	buf := append(p.pending, data...)
+	p.pending = nil
+	end := len(buf)
+	// 寫入可能切在多位元組字元中間，不完整的部分留到下次
+	for i := 1; i < utf8.UTFMax && i <= len(buf); i++ {
+		if utf8.RuneStart(buf[len(buf)-i]) {
+			if !utf8.FullRune(buf[len(buf)-i:]) {
+				end = len(buf) - i
+			}
+			break
+		}
+	}
+	p.pending = append([]byte(nil), buf[end:]...)
+
+	if _, err := io.WriteString(p.w, PlainText(string(buf[:end]))); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Printf、Println 與 Print 與 fmt 的同名函式相同，但寫到 Printer
+func (p *Printer) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(p, format, args...)
+}
+
+func (p *Printer) Println(args ...interface{}) {
+	fmt.Fprintln(p, args...)
+}
+
+func (p *Printer) Print(args ...interface{}) {
+	fmt.Fprint(p, args...)
+}
+
+// Writer 回傳給子行程使用的輸出：未開啟純 ASCII 時直接回傳底層的 writer，讓命令仍連接到終端機
+func (p *Printer) Writer() io.Writer {
+	if !plain {
+		return p.w
+	}
+	return p
+}
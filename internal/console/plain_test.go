@@ -0,0 +1,81 @@
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "plain ascii", want: "plain ascii"},
+		{in: "✅ Project created", want: "[ok] Project created"},
+		{in: "⚠️  careful", want: "[!]  careful"},
+		{in: "ℹ️  note", want: "[i]  note"},
+		{in: "   • a → b", want: "   - a -> b"},
+		{in: "   ▸ Backend setup", want: "   > Backend setup"},
+		{in: "╭──╮", want: "+--+"},
+		{in: "📦 basic", want: "* basic"},
+		{in: "☕ break", want: "* break"},
+		{in: "café", want: "caf?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := PlainText(tt.in); got != tt.want {
+				t.Errorf("PlainText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	tests := []struct {
+		name   string
+		plain  bool
+		writes []string
+		want   string
+	}{
+		{name: "plain off", writes: []string{"✅ done\n"}, want: "✅ done\n"},
+		{name: "plain on", plain: true, writes: []string{"🔄 Generating...\n", "✅ done\n"}, want: "... Generating...\n[ok] done\n"},
+		// 子行程的輸出可能在多位元組字元中間被切開
+		{name: "rune split across writes", plain: true, writes: []string{"a \xe2\x9c", "\x85 b\n"}, want: "a [ok] b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPlain(tt.plain)
+			t.Cleanup(func() { SetPlain(false) })
+
+			var out bytes.Buffer
+			printer := NewPrinter(&out)
+			for _, data := range tt.writes {
+				n, err := printer.Write([]byte(data))
+				if err != nil || n != len(data) {
+					t.Fatalf("Write(%q) = %d, %v", data, n, err)
+				}
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrinterWriter(t *testing.T) {
+	var out bytes.Buffer
+	printer := NewPrinter(&out)
+	if printer.Writer() != &out {
+		t.Error("Writer() should return the underlying writer when plain output is off")
+	}
+
+	SetPlain(true)
+	t.Cleanup(func() { SetPlain(false) })
+	fmt.Fprint(printer.Writer(), "❌ failed\n")
+	if want := "[x] failed\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,62 @@
+package template
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadTemplateArchive 將 tar（可為 gzip 壓縮）格式的模板解開到暫存目錄並驗證其 template.yaml，
+// 供單次產生使用而不安裝。回傳模板名稱與清理函式；呼叫端應在產生完成後呼叫 cleanup。
+func (m *Manager) LoadTemplateArchive(r io.Reader) (name string, cleanup func(), err error) {
+	tempDir, err := os.MkdirTemp("", "aaa-generator-archive-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(tempDir)
+		}
+	}()
+
+	if err := extractTar(r, tempDir); err != nil {
+		return "", nil, fmt.Errorf("failed to extract template archive: %w", err)
+	}
+
+	root, err := findTemplateRoot(tempDir)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid template archive: %w", err)
+	}
+
+	problems, err := ValidateTemplate(root)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(problems) > 0 {
+		return "", nil, fmt.Errorf("invalid template.yaml in archive: %s", strings.Join(problems, "; "))
+	}
+
+	templateFS := os.DirFS(root)
+	_, configData, err := findConfigFile(templateFS)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read template config: %w", err)
+	}
+
+	config, err := parseTemplateConfig(configData, templateFS)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse template config: %w", err)
+	}
+
+	name = config.Name
+	m.archiveTemplates[name] = &Template{
+		Config:    config,
+		Files:     templateFS,
+		LocalPath: root,
+	}
+	cleanup = func() {
+		delete(m.archiveTemplates, name)
+		os.RemoveAll(tempDir)
+	}
+	return name, cleanup, nil
+}
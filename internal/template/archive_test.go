@@ -0,0 +1,106 @@
+package template
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, r io.Reader) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := io.Copy(gz, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestLoadTemplateArchive(t *testing.T) {
+	valid := []tarEntry{
+		{name: "template.yaml", body: "name: piped\nversion: 1.0.0\n"},
+		{name: "main.go", body: "package main\n"},
+	}
+
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		gzip    bool
+		wantErr string
+	}{
+		{name: "tar", entries: valid},
+		{name: "tar.gz", entries: valid, gzip: true},
+		{
+			name: "single top-level directory",
+			entries: []tarEntry{
+				{name: "piped", dir: true},
+				{name: "piped/template.yaml", body: "name: piped\n"},
+				{name: "piped/main.go", body: "package main\n"},
+			},
+		},
+		{
+			name:    "no template.yaml",
+			entries: []tarEntry{{name: "main.go", body: "package main\n"}},
+			wantErr: "template.yaml not found",
+		},
+		{
+			name:    "invalid template.yaml",
+			entries: []tarEntry{{name: "template.yaml", body: "name: piped\nvariables:\n  - name: Mode\n    type: select\n"}},
+			wantErr: "invalid template.yaml in archive",
+		},
+		{
+			name:    "escaping entry",
+			entries: []tarEntry{{name: "../template.yaml", body: "name: piped\n"}},
+			wantErr: "failed to extract template archive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			var archive io.Reader = buildTar(t, tt.entries)
+			if tt.gzip {
+				archive = gzipped(t, archive)
+			}
+
+			name, cleanup, err := manager.LoadTemplateArchive(archive)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadTemplateArchive() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTemplateArchive() error = %v", err)
+			}
+
+			tmpl, err := manager.GetTemplate(name)
+			if err != nil {
+				t.Fatalf("GetTemplate(%q) error = %v", name, err)
+			}
+			generator := newTestGenerator(t, manager)
+			if _, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"}); err != nil {
+				t.Fatalf("GenerateFS() error = %v", err)
+			}
+
+			cleanup()
+			if _, err := manager.GetTemplate(name); err == nil {
+				t.Error("archive template is still available after cleanup")
+			}
+			if _, err := os.Stat(tmpl.LocalPath); !os.IsNotExist(err) {
+				t.Errorf("extracted archive %s was not removed", tmpl.LocalPath)
+			}
+			for _, installed := range manager.ListTemplates() {
+				if installed.Name == name {
+					t.Error("archive template was installed")
+				}
+			}
+		})
+	}
+}
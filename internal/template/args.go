@@ -0,0 +1,77 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// projectNameArg 可出現在 argsOrder 中，讓第一個位置參數作為專案名稱，例如 `generator -t component Button primary`
+const projectNameArg = "ProjectName"
+
+// PositionalValues 依模板的 argsOrder 將位置參數對應到變數名稱；參數可少於 argsOrder（其餘變數照常取預設值或詢問），
+// 但不可多於 argsOrder。int、bool 與 select 變數的值會先檢查型別。
+// 回傳的 map 可能包含 ProjectName，由呼叫端作為專案名稱使用
+func (m *Manager) PositionalValues(templateName string, args []string) (map[string]string, error) {
+	tmpl, err := m.GetTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	if tmpl.Config != nil {
+		order = tmpl.Config.ArgsOrder
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("template '%s' does not accept positional arguments (declare argsOrder in template.yaml, or use --set)", templateName)
+	}
+	if len(args) > len(order) {
+		return nil, fmt.Errorf("too many arguments for template '%s': expected at most %d (%s), got %d",
+			templateName, len(order), strings.Join(order, ", "), len(args))
+	}
+
+	values := make(map[string]string, len(args))
+	for i, arg := range args {
+		name := order[i]
+		if name != projectNameArg {
+			variable, ok := findVariable(tmpl.Config, name)
+			if !ok {
+				return nil, newDetailError(ErrUnknownVariable, "argsOrder of template '%s' names undeclared variable '%s'", templateName, name)
+			}
+			if err := checkVariableType(variable, arg); err != nil {
+				return nil, err
+			}
+		}
+		values[name] = arg
+	}
+	return values, nil
+}
+
+// findVariable 回傳 config 中名為 name 的變數宣告
+func findVariable(config *TemplateConfig, name string) (TemplateVar, bool) {
+	for _, variable := range config.Variables {
+		if variable.Name == name {
+			return variable, true
+		}
+	}
+	return TemplateVar{}, false
+}
+
+// checkVariableType 確認 value 符合變數的型別：int 為整數、bool 為 true/false 等、select 為選項之一
+func checkVariableType(variable TemplateVar, value string) error {
+	switch variable.Type {
+	case "int":
+		if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil {
+			return newDetailError(ErrInvalidVariable, "invalid value '%s' for variable '%s': expected an integer", value, variable.Name)
+		}
+	case "bool":
+		if _, err := strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+			return newDetailError(ErrInvalidVariable, "invalid value '%s' for variable '%s': expected true or false", value, variable.Name)
+		}
+	case "select":
+		if len(variable.Options) > 0 && !contains(variable.Options, value) {
+			return &VariableError{Name: variable.Name, Value: value, Options: variable.Options}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package template
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPositionalValues(t *testing.T) {
+	config := `name: component
+argsOrder: [ProjectName, Variant, Size, Rounded]
+variables:
+  - name: Variant
+    type: select
+    options: [primary, secondary]
+  - name: Size
+    type: int
+    default: "12"
+  - name: Rounded
+    type: bool
+    default: "false"
+`
+
+	tests := []struct {
+		name    string
+		config  string
+		args    []string
+		want    map[string]string
+		wantErr string
+		wantIs  error
+	}{
+		{
+			name: "all arguments",
+			args: []string{"Button", "primary", "16", "true"},
+			want: map[string]string{"ProjectName": "Button", "Variant": "primary", "Size": "16", "Rounded": "true"},
+		},
+		{
+			name: "fewer arguments than argsOrder",
+			args: []string{"Button"},
+			want: map[string]string{"ProjectName": "Button"},
+		},
+		{
+			name:    "too many arguments",
+			args:    []string{"Button", "primary", "16", "true", "extra"},
+			wantErr: "expected at most 4 (ProjectName, Variant, Size, Rounded), got 5",
+		},
+		{
+			name:   "invalid select option",
+			args:   []string{"Button", "tertiary"},
+			wantIs: ErrInvalidVariable,
+		},
+		{
+			name:   "invalid int",
+			args:   []string{"Button", "primary", "big"},
+			wantIs: ErrInvalidVariable,
+		},
+		{
+			name:   "invalid bool",
+			args:   []string{"Button", "primary", "16", "maybe"},
+			wantIs: ErrInvalidVariable,
+		},
+		{
+			name:    "no argsOrder",
+			config:  "name: component\n",
+			args:    []string{"Button"},
+			wantErr: "does not accept positional arguments",
+		},
+		{
+			name:   "argsOrder names an undeclared variable",
+			config: "name: component\nargsOrder: [Color]\n",
+			args:   []string{"red"},
+			wantIs: ErrUnknownVariable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			templateConfig := tt.config
+			if templateConfig == "" {
+				templateConfig = config
+			}
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": templateConfig})
+
+			got, err := manager.PositionalValues(name, tt.args)
+			switch {
+			case tt.wantIs != nil:
+				if !errors.Is(err, tt.wantIs) {
+					t.Fatalf("PositionalValues() error = %v, want %v", err, tt.wantIs)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("PositionalValues() error = %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("PositionalValues() error = %v", err)
+			case !reflect.DeepEqual(got, tt.want):
+				t.Errorf("PositionalValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BatchProject 為批次檔中的一個專案；Template 空白時使用 basic
+type BatchProject struct {
+	Name     string            `yaml:"name"`
+	Template string            `yaml:"template"`
+	Vars     map[string]string `yaml:"vars"`
+}
+
+// BatchFile 為 generator batch 使用的批次檔格式
+type BatchFile struct {
+	Projects []BatchProject `yaml:"projects"`
+}
+
+// LoadBatchFile 讀取並檢查批次檔：每個專案都需要名稱，且名稱不可重複
+func LoadBatchFile(path string) (*BatchFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch BatchFile
+	if err := yaml.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(batch.Projects) == 0 {
+		return nil, fmt.Errorf("%s does not list any projects", path)
+	}
+
+	seen := make(map[string]bool)
+	for i := range batch.Projects {
+		project := &batch.Projects[i]
+		project.Name = strings.TrimSpace(project.Name)
+		if project.Name == "" {
+			return nil, fmt.Errorf("projects[%d]: name must not be empty", i)
+		}
+		if seen[project.Name] {
+			return nil, fmt.Errorf("projects[%d]: duplicate project name '%s'", i, project.Name)
+		}
+		seen[project.Name] = true
+		if strings.TrimSpace(project.Template) == "" {
+			project.Template = "basic"
+		}
+	}
+	return &batch, nil
+}
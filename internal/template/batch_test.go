@@ -0,0 +1,57 @@
+package template
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadBatchFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []BatchProject
+		wantErr string
+	}{
+		{
+			name: "projects with defaults",
+			content: `projects:
+  - name: svc-users
+    template: api
+    vars:
+      Port: "8081"
+  - name: " svc-orders "
+`,
+			want: []BatchProject{
+				{Name: "svc-users", Template: "api", Vars: map[string]string{"Port": "8081"}},
+				{Name: "svc-orders", Template: "basic"},
+			},
+		},
+		{name: "no projects", content: "projects: []\n", wantErr: "does not list any projects"},
+		{name: "empty name", content: "projects:\n  - template: api\n", wantErr: "projects[0]: name must not be empty"},
+		{name: "duplicate name", content: "projects:\n  - name: a\n  - name: a\n", wantErr: "projects[1]: duplicate project name 'a'"},
+		{name: "invalid YAML", content: "projects: [", wantErr: "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"batch.yaml": tt.content})
+
+			batch, err := LoadBatchFile(filepath.Join(dir, "batch.yaml"))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadBatchFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadBatchFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(batch.Projects, tt.want) {
+				t.Errorf("projects = %+v, want %+v", batch.Projects, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cacheSourceFile 記錄快取項目對應的來源；下載內容放在同一項目的 template/ 目錄
+const cacheSourceFile = "source"
+
+// CacheEntry 為一筆已快取的遠端模板下載
+type CacheEntry struct {
+	Source   string
+	Path     string
+	Size     int64
+	CachedAt time.Time
+}
+
+// CacheDir 回傳遠端模板下載的快取目錄。
+// Linux 上依序使用 $XDG_CACHE_HOME、~/.cache；其他平台放在 ~/.go-react-generator/cache。
+func CacheDir() (string, error) {
+	if runtime.GOOS != "linux" {
+		legacy, err := legacyDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(legacy, "cache"), nil
+	}
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
+
+// isCacheableSource 回傳來源內容是否固定不變；只有以 digest 指定的來源才會被快取，
+// 以 tag 指定的來源每次都重新下載
+func isCacheableSource(source string) bool {
+	return strings.Contains(source, "@sha256:")
+}
+
+// cacheEntryDir 回傳來源在快取目錄中的位置
+func cacheEntryDir(source string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(source))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])), nil
+}
+
+// fetchCached 下載來源到快取（已存在時直接沿用），回傳下載內容所在的目錄；沿用快取時在 out 輸出提示
+func fetchCached(out io.Writer, fetcher Fetcher, source string) (string, error) {
+	entryDir, err := cacheEntryDir(source)
+	if err != nil {
+		return "", err
+	}
+	contentDir := filepath.Join(entryDir, "template")
+	if _, err := os.Stat(filepath.Join(entryDir, cacheSourceFile)); err == nil {
+		fmt.Fprintf(out, "   • Using cached download of %s\n", source)
+		return contentDir, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(entryDir), defaultDirMode); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	// 先下載到暫存目錄，完成後才搬進快取，避免留下不完整的項目
+	tempDir, err := os.MkdirTemp(filepath.Dir(entryDir), ".fetch-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tempDir)
+
+	fetchDir := filepath.Join(tempDir, "template")
+	if err := os.Mkdir(fetchDir, defaultDirMode); err != nil {
+		return "", err
+	}
+	if err := fetcher.Fetch(source, fetchDir); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, cacheSourceFile), []byte(source+"\n"), defaultFileMode); err != nil {
+		return "", err
+	}
+
+	os.RemoveAll(entryDir)
+	if err := os.Rename(tempDir, entryDir); err != nil {
+		return "", fmt.Errorf("failed to store %s in cache: %w", source, err)
+	}
+	return contentDir, nil
+}
+
+// ListCache 列出所有快取項目，依來源排序；快取目錄不存在時回傳空列表
+func ListCache() ([]CacheEntry, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var cached []CacheEntry
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		entryDir := filepath.Join(dir, entry.Name())
+		source, err := os.ReadFile(filepath.Join(entryDir, cacheSourceFile))
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		size, err := dirSize(entryDir)
+		if err != nil {
+			return nil, err
+		}
+		cached = append(cached, CacheEntry{
+			Source:   strings.TrimSpace(string(source)),
+			Path:     entryDir,
+			Size:     size,
+			CachedAt: info.ModTime(),
+		})
+	}
+
+	sort.Slice(cached, func(i, j int) bool { return cached[i].Source < cached[j].Source })
+	return cached, nil
+}
+
+// ClearCache 刪除 source 的快取項目；source 為空時清除整個快取。回傳刪除的項目數
+func ClearCache(source string) (int, error) {
+	if source == "" {
+		entries, err := ListCache()
+		if err != nil {
+			return 0, err
+		}
+		dir, err := CacheDir()
+		if err != nil {
+			return 0, err
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return 0, fmt.Errorf("failed to clear cache: %w", err)
+		}
+		return len(entries), nil
+	}
+
+	entryDir, err := cacheEntryDir(source)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(filepath.Join(entryDir, cacheSourceFile)); err != nil {
+		return 0, fmt.Errorf("no cache entry for %s", source)
+	}
+	if err := os.RemoveAll(entryDir); err != nil {
+		return 0, fmt.Errorf("failed to remove cache entry: %w", err)
+	}
+	return 1, nil
+}
+
+// dirSize 回傳目錄下所有一般檔案的大小總和
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,121 @@
+package template
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// countingFetcher 記錄下載次數，下載時寫入一個最小的模板
+type countingFetcher struct {
+	fetches int
+	fail    bool
+}
+
+func (f *countingFetcher) Fetch(source, dst string) error {
+	f.fetches++
+	if f.fail {
+		return errors.New("registry unavailable")
+	}
+	return os.WriteFile(filepath.Join(dst, "template.yaml"), []byte("name: cached\n"), 0o644)
+}
+
+func TestFetchCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		fail        bool
+		wantFetches int
+		wantCached  bool
+		wantErr     string
+	}{
+		{name: "digest is fetched once", source: "fake://registry/tpl@sha256:abc123", wantFetches: 1, wantCached: true},
+		{name: "tag is fetched every time", source: "fake://registry/tpl:latest", wantFetches: 2},
+		{name: "failed fetch is not cached", source: "fake://registry/tpl@sha256:abc123", fail: true, wantFetches: 1, wantErr: "registry unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			manager.Reinstall = true
+			fetcher := &countingFetcher{fail: tt.fail}
+			manager.RegisterFetcher("fake", fetcher)
+
+			for i := 0; i < 2; i++ {
+				_, err := manager.InstallTemplate(tt.source)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("InstallTemplate() error = %v, want %q", err, tt.wantErr)
+					}
+					break
+				}
+				if err != nil {
+					t.Fatalf("InstallTemplate() error = %v", err)
+				}
+			}
+			if fetcher.fetches != tt.wantFetches {
+				t.Errorf("fetches = %d, want %d", fetcher.fetches, tt.wantFetches)
+			}
+
+			entries, err := ListCache()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cached := len(entries) == 1 && entries[0].Source == tt.source && entries[0].Size > 0; cached != tt.wantCached {
+				t.Errorf("cache entries = %+v, want cached = %v", entries, tt.wantCached)
+			}
+			if !tt.wantCached && len(entries) != 0 {
+				t.Errorf("cache entries = %+v, want none", entries)
+			}
+		})
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	sources := []string{"fake://registry/a@sha256:aaa", "fake://registry/b@sha256:bbb"}
+
+	tests := []struct {
+		name        string
+		source      string
+		wantRemoved int
+		wantLeft    int
+		wantErr     string
+	}{
+		{name: "one source", source: sources[0], wantRemoved: 1, wantLeft: 1},
+		{name: "everything", source: "", wantRemoved: 2, wantLeft: 0},
+		{name: "unknown source", source: "fake://registry/c@sha256:ccc", wantErr: "no cache entry", wantLeft: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestManager(t)
+			for _, source := range sources {
+				if _, err := fetchCached(io.Discard, &countingFetcher{}, source); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			removed, err := ClearCache(tt.source)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ClearCache() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ClearCache() error = %v", err)
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("removed = %d, want %d", removed, tt.wantRemoved)
+			}
+			entries, err := ListCache()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.wantLeft {
+				t.Errorf("cache entries left = %d, want %d", len(entries), tt.wantLeft)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// evalCondition 以 vars 渲染條件表達式（例如 `{{ ne .DBType "none" }}`），
+// 結果去除空白後不是空字串、"false"、"0" 或 "<no value>" 時視為成立
+func evalCondition(expr string, vars map[string]interface{}) (bool, error) {
+	tmpl, err := template.New("condition").Funcs(templateFuncs()).Parse(expr)
+	if err != nil {
+		return false, err
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(buf.String())) {
+	case "", "false", "0", "<no value>":
+		return false, nil
+	}
+	return true, nil
+}
+
+// isRequired 回傳變數是否必填：required: true，或 requiredIf 對目前已收集的變數成立
+func isRequired(variable TemplateVar, vars map[string]interface{}) (bool, error) {
+	if variable.Required || variable.RequiredIf == "" {
+		return variable.Required, nil
+	}
+	required, err := evalCondition(variable.RequiredIf, vars)
+	if err != nil {
+		return false, newDetailError(ErrInvalidVariable, "invalid requiredIf for variable '%s': %v", variable.Name, err)
+	}
+	return required, nil
+}
+
+// splitRulesByCondition 在走訪模板前對每條規則的 condition 求值一次，回傳生效與停用的規則。
+// 停用的規則視同不存在，其對應的檔案可由其他生效的規則處理
+func (g *Generator) splitRulesByCondition(rules []FileRule, vars map[string]interface{}) (active, disabled []FileRule, err error) {
+	for _, rule := range rules {
+		if strings.TrimSpace(rule.Condition) == "" {
+			active = append(active, rule)
+			continue
+		}
+		ok, err := evalCondition(rule.Condition, vars)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid condition for file rule %q: %w", rule.Source, err)
+		}
+		g.trace("condition", map[string]interface{}{"source": rule.Source, "condition": rule.Condition, "result": ok})
+		if ok {
+			active = append(active, rule)
+		} else {
+			disabled = append(disabled, rule)
+		}
+	}
+	return active, disabled, nil
+}
+
+// rulesBelow 回傳是否有規則可能比對到 dir 之下的路徑，用來判斷能否整個略過被停用的目錄
+func rulesBelow(rules []FileRule, dir string) bool {
+	for _, rule := range rules {
+		src := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(rule.Source), "./"), "/")
+		if rule.Type == "glob" {
+			src = globBase(src)
+		}
+		src = strings.TrimSuffix(src, "/")
+		if src == "" || src == dir || strings.HasPrefix(src, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package template
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEvalCondition(t *testing.T) {
+	vars := map[string]interface{}{"DBType": "postgres", "Docker": "false", "Replicas": "0", "Name": "Demo"}
+
+	tests := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: `{{ ne .DBType "none" }}`, want: true},
+		{expr: `{{ eq .DBType "none" }}`, want: false},
+		{expr: `{{ .Docker }}`, want: false},
+		{expr: `{{ .Replicas }}`, want: false},
+		{expr: `{{ .Missing }}`, want: false},
+		{expr: `  `, want: false},
+		{expr: `{{ .Name | lower }}`, want: true},
+		{expr: `{{ if`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalCondition(tt.expr, vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evalCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evalCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredIf(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: db
+variables:
+  - name: DBType
+    type: select
+    options: [none, postgres]
+    default: none
+  - name: DBName
+    requiredIf: '{{ ne .DBType "none" }}'
+`,
+		"main.go": "package main\n",
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr string
+	}{
+		{name: "condition false", values: map[string]string{}},
+		{name: "condition true and value given", values: map[string]string{"DBType": "postgres", "DBName": "app"}},
+		{name: "condition true and value missing", values: map[string]string{"DBType": "postgres"}, wantErr: "variable 'DBName' is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.Values = tt.values
+
+			_, err := generator.Generate("app", name)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidVariable) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want ErrInvalidVariable containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestIsRequiredInvalidCondition(t *testing.T) {
+	_, err := isRequired(TemplateVar{Name: "DBName", RequiredIf: "{{ if"}, nil)
+	if !errors.Is(err, ErrInvalidVariable) || !strings.Contains(err.Error(), "invalid requiredIf for variable 'DBName'") {
+		t.Fatalf("isRequired() error = %v, want ErrInvalidVariable for the invalid requiredIf", err)
+	}
+}
+
+func TestDirectoryCondition(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: fullstack
+variables:
+  - name: Frontend
+    type: bool
+    default: "false"
+files:
+  - source: backend
+    target: backend
+  - source: frontend
+    target: frontend
+    condition: '{{ eq .Frontend "true" }}'
+  - source: frontend/shared
+    target: shared
+`,
+		"backend/main.go":          "package main\n",
+		"frontend/package.json":    "{}\n",
+		"frontend/src/App.tsx":     "export {}\n",
+		"frontend/src/deep/x.ts":   "export {}\n",
+		"frontend/shared/types.ts": "export {}\n",
+	}
+
+	tests := []struct {
+		name     string
+		frontend string
+		want     []string
+		wantSkip int
+	}{
+		{
+			name:     "included",
+			frontend: "true",
+			want:     []string{"backend/main.go", "frontend/package.json", "frontend/shared/types.ts", "frontend/src/App.tsx", "frontend/src/deep/x.ts"},
+		},
+		{
+			// 停用的 frontend 規則視同不存在，frontend/shared 改由後面的規則處理
+			name:     "excluded",
+			frontend: "false",
+			want:     []string{"backend/main.go", "shared/types.ts"},
+			wantSkip: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			out := NewMemorySink()
+
+			stats, err := generator.GenerateTo(name, map[string]interface{}{"ProjectName": "app", "Frontend": tt.frontend}, out)
+			if err != nil {
+				t.Fatalf("GenerateTo() error = %v", err)
+			}
+			var got []string
+			for _, file := range stats.generatedFiles() {
+				got = append(got, file.Path)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("generated %v, want %v", got, tt.want)
+			}
+			// 被停用的目錄中，未被其他規則涵蓋的部分整個略過：package.json 與 src/ 各計一次
+			if stats.SkippedByCondition != tt.wantSkip {
+				t.Errorf("skipped by condition = %d, want %d", stats.SkippedByCondition, tt.wantSkip)
+			}
+		})
+	}
+}
+
+func TestRulesBelow(t *testing.T) {
+	rules := []FileRule{
+		{Source: "./frontend/shared/"},
+		{Source: "docs/**/*.md", Type: "glob"},
+	}
+
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{dir: "frontend", want: true},
+		{dir: "frontend/shared", want: true},
+		{dir: "docs", want: true},
+		{dir: "backend", want: false},
+		{dir: "front", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			if got := rulesBelow(rules, tt.dir); got != tt.want {
+				t.Errorf("rulesBelow(%q) = %v, want %v", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,38 +1,149 @@
 package template
 
-import "fmt"
+import (
+	"fmt"
+	"io/fs"
+	"runtime"
+	"strconv"
+	"strings"
+)
 
 type TemplateConfig struct {
-	Name         string        `yaml:"name"`
-	DisplayName  string        `yaml:"displayName"`
-	Description  string        `yaml:"description"`
-	Version      string        `yaml:"version"`
-	Author       string        `yaml:"author"`
-	Tags         []string      `yaml:"tags"`
-	Variables    []TemplateVar `yaml:"variables"`
+	Name        string   `yaml:"name"`
+	DisplayName string   `yaml:"displayName"`
+	Description string   `yaml:"description"`
+	Version     string   `yaml:"version"`
+	Author      string   `yaml:"author"`
+	URL         string   `yaml:"url"` // 模板的首頁或原始碼位置
+	Tags        []string `yaml:"tags"`
+	// Imports 為要合併的共用設定片段（相對於模板根目錄的 YAML 檔），見 parseTemplateConfig
+	Imports   []string      `yaml:"imports"`
+	Variables []TemplateVar `yaml:"variables"`
+	// ArgsOrder 為位置參數依序對應的變數名稱（可包含 ProjectName），見 Manager.PositionalValues
+	ArgsOrder    []string      `yaml:"argsOrder"`
 	Files        []FileRule    `yaml:"files"`
+	Includes     []IncludeRule `yaml:"include"`
 	PostGenerate []PostCommand `yaml:"postGenerate"`
+	// Validate 為對產生結果執行的驗證命令，只在 --validate 或 AlwaysValidate 時執行，見 ValidateCommand
+	Validate       []ValidateCommand `yaml:"validate"`
+	AlwaysValidate bool              `yaml:"alwaysValidate"`
+	PostMessage    string            `yaml:"postMessage"`
+	NextSteps      []string          `yaml:"nextSteps"`
+	// Deprecated 標記模板已不建議使用；DeprecationMessage 可指出替代的模板
+	Deprecated         bool   `yaml:"deprecated"`
+	DeprecationMessage string `yaml:"deprecationMessage"`
+	// FileMode 與 DirMode 為產生檔案/目錄的權限（八進位字串，例如 "0600"），未設定時為 0644/0755
+	FileMode string `yaml:"fileMode"`
+	DirMode  string `yaml:"dirMode"`
+	// Format 產生後以對應的格式化工具（gofmt、prettier）處理輸出的檔案，工具未安裝時只顯示警告
+	Format bool `yaml:"format"`
+	// Header 為加在產生檔案開頭的標頭（例如授權聲明，可使用模板變數），只套用到 HeaderExtensions 列出的副檔名，
+	// 並依語言寫成註解
+	Header           string   `yaml:"header"`
+	HeaderExtensions []string `yaml:"headerExtensions"`
+	// StripGitkeep 只建立含 .gitkeep 的空目錄，不把 .gitkeep 複製到專案
+	StripGitkeep bool `yaml:"stripGitkeep"`
+	// GenerateReadme 在模板沒有提供 README.md 時產生一份專案說明，見 Generator.GenReadme
+	GenerateReadme bool `yaml:"generateReadme"`
+	// DataCommand 為收集變數前執行的命令（以 sh -c 執行），stdout 須為 JSON 物件，其鍵值併入模板變數（低於 --set），見 Generator.templateData
+	DataCommand string `yaml:"dataCommand"`
+	// RequiredEnv 為產生前必須設定的環境變數（例如 post-generate 命令需要的 GITHUB_TOKEN）
+	RequiredEnv []string `yaml:"requiredEnv"`
+	// MinGeneratorVersion 為使用此模板所需的最低 generator 版本，例如 "1.4.0"
+	MinGeneratorVersion string `yaml:"minGeneratorVersion"`
 }
 
 type TemplateVar struct {
-	Name        string   `yaml:"name"`
-	Type        string   `yaml:"type"` // string, int, bool, select
-	Required    bool     `yaml:"required"`
-	Default     string   `yaml:"default"`
-	Options     []string `yaml:"options"`
-	Description string   `yaml:"description"`
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"` // string, int, bool, select
+	Required bool   `yaml:"required"`
+	// RequiredIf 為條件表達式，對先前收集的變數成立時此變數才必填，例如 `{{ ne .DBType "none" }}`
+	RequiredIf string   `yaml:"requiredIf"`
+	Default    string   `yaml:"default"`
+	Options    []string `yaml:"options"`
+	// OptionsFrom 為 select 變數產生選項的命令（以 sh -c 執行，可使用先前的變數），stdout 的每一行為一個選項，
+	// 取代 Options；--no-input 時不執行
+	OptionsFrom string `yaml:"optionsFrom"`
+	// OptionsFile 為 select 變數的允許清單檔案（每行一個選項，# 開頭為註解），於產生時讀取並取代 Options，
+	// 讓允許的值可在模板之外維護
+	OptionsFile string `yaml:"optionsFile"`
+	Description string `yaml:"description"`
+	Transform   string `yaml:"transform"` // 例如 "{{ . | trimSpace | lower }}"，於驗證後套用
+	// Secret 標記值為機密（例如密碼）：--print-vars 與 trace 只顯示遮罩，manifest 不保存其值
+	Secret bool `yaml:"secret"`
 }
 
 type FileRule struct {
 	Source    string `yaml:"source"`
 	Target    string `yaml:"target"`
-	Type      string `yaml:"type"` // file, directory
+	Type      string `yaml:"type"` // file, directory, glob
 	Condition string `yaml:"condition"`
+	// OS 限定規則只在這些作業系統（runtime.GOOS，例如 linux、darwin、windows）上生效；空白表示全部
+	OS []string `yaml:"os"`
+	// StripBlankLines 在渲染 .tmpl 檔案後將連續的空白行合併為一行
+	StripBlankLines bool `yaml:"stripBlankLines"`
+	// Replace 對符合規則的非 .tmpl 文字檔進行字面字串取代；值可使用模板變數，例如 "{{ .ProjectName }}"。
+	// 看起來是二進位的檔案不會被修改
+	Replace map[string]string `yaml:"replace"`
+	// Append 將產生的內容附加到已存在的目標檔案（例如 --force 時的 .gitignore），而非覆寫；
+	// DedupLines 附加時略過目標檔案中已有的行
+	Append     bool `yaml:"append"`
+	DedupLines bool `yaml:"dedupLines"`
+}
+
+// IncludeRule 從另一個已安裝的模板引入檔案或目錄
+type IncludeRule struct {
+	Template string `yaml:"template"`
+	Source   string `yaml:"source"`
+	Target   string `yaml:"target"`
 }
 
 type PostCommand struct {
-	Command string `yaml:"command"`
-	WorkDir string `yaml:"workDir"`
+	Command string   `yaml:"command"`
+	WorkDir string   `yaml:"workDir"`
+	OS      []string `yaml:"os"` // 與 FileRule.OS 相同，只在這些作業系統上執行
+	// Phase 為命令所屬階段的標籤（例如 "Backend setup"），執行時在階段改變處顯示為小節標題；不影響執行順序
+	Phase string `yaml:"phase"`
+}
+
+// duplicateVariables 回傳重複宣告的變數名稱（依第一次重複出現的順序）；collectVariables 只會使用第一個宣告
+func duplicateVariables(config *TemplateConfig) []string {
+	seen := make(map[string]bool, len(config.Variables))
+	reported := make(map[string]bool)
+	var duplicates []string
+	for _, variable := range config.Variables {
+		if seen[variable.Name] && !reported[variable.Name] {
+			duplicates = append(duplicates, variable.Name)
+			reported[variable.Name] = true
+		}
+		seen[variable.Name] = true
+	}
+	return duplicates
+}
+
+// targetOS 為比對 os 欄位時使用的作業系統，測試時可覆寫
+var targetOS = runtime.GOOS
+
+// matchesOS 回傳 targetOS 是否在 list 之中；list 為空時總是符合
+func matchesOS(list []string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, name := range list {
+		if strings.EqualFold(strings.TrimSpace(name), targetOS) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFileMode 解析八進位的權限字串，例如 "0600" 或 "755"
+func ParseFileMode(value string) (fs.FileMode, error) {
+	mode, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(value), "0o"), 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("invalid permission mode %q (expected octal such as 0644)", value)
+	}
+	return fs.FileMode(mode), nil
 }
 
 // 項目變數結構
@@ -0,0 +1,128 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// setTargetOS 在測試期間以 goos 取代執行平台
+func setTargetOS(t *testing.T, goos string) {
+	t.Helper()
+	previous := targetOS
+	targetOS = goos
+	t.Cleanup(func() { targetOS = previous })
+}
+
+func TestMatchesOS(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		list []string
+		want bool
+	}{
+		{name: "empty list matches", goos: "linux", want: true},
+		{name: "listed", goos: "linux", list: []string{"linux", "darwin"}, want: true},
+		{name: "not listed", goos: "windows", list: []string{"linux", "darwin"}, want: false},
+		{name: "case and spaces ignored", goos: "darwin", list: []string{" Darwin "}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTargetOS(t, tt.goos)
+			if got := matchesOS(tt.list); got != tt.want {
+				t.Errorf("matchesOS(%q) on %s = %v, want %v", tt.list, tt.goos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateOSFilters(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: platforms
+files:
+  - source: main.go
+    type: file
+  - source: run.bat
+    type: file
+    os: [windows]
+  - source: run.sh
+    type: file
+    os: [linux, darwin]
+postGenerate:
+  - command: "echo windows > cmd-windows.txt"
+    os: [windows]
+  - command: "echo unix > cmd-unix.txt"
+    os: [linux, darwin]
+`,
+		"main.go": "package main\n",
+		"run.bat": "@echo off\n",
+		"run.sh":  "#!/bin/sh\n",
+	}
+
+	tests := []struct {
+		name string
+		goos string
+		want []string
+	}{
+		{name: "linux", goos: "linux", want: []string{"cmd-unix.txt", "main.go", "run.sh"}},
+		{name: "windows", goos: "windows", want: []string{"cmd-windows.txt", "main.go", "run.bat"}},
+		{name: "unlisted platform", goos: "plan9", want: []string{"main.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			// 命令一律以 sh 執行，只有 targetOS 決定要跑哪些
+			setTargetOS(t, tt.goos)
+
+			if _, err := generator.Generate("app", name); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			entries, err := os.ReadDir(filepath.Join(generator.WorkDir, "app"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, entry := range entries {
+				if !strings.HasPrefix(entry.Name(), ".") {
+					got = append(got, entry.Name())
+				}
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("project files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateVariables(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  string
+	}{
+		{name: "unique", names: []string{"A", "B"}},
+		{name: "one duplicate", names: []string{"A", "B", "A"}, want: "A"},
+		// 每個名稱只回報一次，依第一次重複的順序
+		{name: "repeated several times", names: []string{"B", "A", "A", "B", "A"}, want: "A,B"},
+		{name: "case sensitive", names: []string{"Port", "port"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &TemplateConfig{}
+			for _, name := range tt.names {
+				config.Variables = append(config.Variables, TemplateVar{Name: name})
+			}
+			if got := strings.Join(duplicateVariables(config), ","); got != tt.want {
+				t.Errorf("duplicateVariables() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
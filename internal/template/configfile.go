@@ -0,0 +1,41 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigFileNames 為可辨識的模板設定檔名稱，依優先順序檢查；所有載入與安裝流程都使用此清單
+var ConfigFileNames = []string{"template.yaml", "template.yml", ".template.yaml"}
+
+// findConfigFile 讀取 fsys 根目錄中優先順序最高的模板設定檔，回傳其名稱與內容。
+// 都不存在時回傳的錯誤可用 errors.Is(err, fs.ErrNotExist) 判斷
+func findConfigFile(fsys fs.FS) (string, []byte, error) {
+	for _, name := range ConfigFileNames {
+		data, err := fs.ReadFile(fsys, name)
+		if err == nil {
+			return name, data, nil
+		}
+		if !os.IsNotExist(err) {
+			return name, nil, err
+		}
+	}
+	return "", nil, fmt.Errorf("no template config found (%s): %w", strings.Join(ConfigFileNames, ", "), fs.ErrNotExist)
+}
+
+// hasConfigFile 回傳 dir 是否含有任一可辨識的模板設定檔
+func hasConfigFile(dir string) bool {
+	for _, name := range ConfigFileNames {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+func isConfigFileName(path string) bool {
+	return contains(ConfigFileNames, path)
+}
@@ -0,0 +1,88 @@
+package template
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFindConfigFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		wantName string
+		wantData string
+	}{
+		{
+			name:     "template.yaml",
+			files:    fstest.MapFS{"template.yaml": {Data: []byte("name: a\n")}},
+			wantName: "template.yaml",
+			wantData: "name: a\n",
+		},
+		{
+			name:     "template.yml",
+			files:    fstest.MapFS{"template.yml": {Data: []byte("name: b\n")}},
+			wantName: "template.yml",
+			wantData: "name: b\n",
+		},
+		{
+			name:     "hidden config",
+			files:    fstest.MapFS{".template.yaml": {Data: []byte("name: c\n")}},
+			wantName: ".template.yaml",
+			wantData: "name: c\n",
+		},
+		{
+			name: "template.yaml takes precedence",
+			files: fstest.MapFS{
+				"template.yml":  {Data: []byte("name: yml\n")},
+				"template.yaml": {Data: []byte("name: yaml\n")},
+			},
+			wantName: "template.yaml",
+			wantData: "name: yaml\n",
+		},
+		{
+			name:  "config only in a subdirectory",
+			files: fstest.MapFS{"sub/template.yaml": {Data: []byte("name: d\n")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, data, err := findConfigFile(tt.files)
+			if tt.wantName == "" {
+				if !errors.Is(err, fs.ErrNotExist) {
+					t.Fatalf("findConfigFile() error = %v, want fs.ErrNotExist", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findConfigFile() error = %v", err)
+			}
+			if name != tt.wantName || string(data) != tt.wantData {
+				t.Errorf("findConfigFile() = (%q, %q), want (%q, %q)", name, data, tt.wantName, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestInstallAlternateConfigNames(t *testing.T) {
+	for _, configName := range ConfigFileNames {
+		t.Run(configName, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{configName: "name: alt\n", "main.go": "package main\n"})
+
+			generator := newTestGenerator(t, manager)
+			output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"})
+			if err != nil {
+				t.Fatalf("GenerateFS() error = %v", err)
+			}
+			if _, err := fs.Stat(output, configName); err == nil {
+				t.Errorf("%s was copied into the project", configName)
+			}
+			if _, err := fs.Stat(output, "main.go"); err != nil {
+				t.Errorf("main.go missing: %v", err)
+			}
+		})
+	}
+}
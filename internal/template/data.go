@@ -0,0 +1,49 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// dataTimeout 為 dataCommand 的執行時限
+const dataTimeout = 10 * time.Second
+
+// runDataCommand 以 sh -c 在 dir 執行 dataCommand 並回傳 stdout，測試時可替換
+var runDataCommand = func(command, dir string) ([]byte, error) {
+	return commandOutput(command, dir, dataTimeout)
+}
+
+// templateData 執行模板的 dataCommand（可使用 ProjectName 等內建變數），將 stdout 解析為 JSON 物件，
+// 例如 {"GitUser": "alice", "Ports": [8080, 8081]}。dry-run 時不執行，回傳 nil
+func (g *Generator) templateData(config *TemplateConfig, vars map[string]interface{}) (map[string]interface{}, error) {
+	if config.DataCommand == "" {
+		return nil, nil
+	}
+	command := g.processCommandTemplate(config.DataCommand, vars)
+	if g.planning {
+		g.trace("data", map[string]interface{}{"command": command, "skipped": true})
+		return nil, nil
+	}
+
+	output, err := runDataCommand(command, g.WorkDir)
+	if err != nil {
+		return nil, newDetailError(ErrInvalidVariable, "dataCommand %q failed: %v", command, err)
+	}
+	// 數字以 json.Number 保留，與重新產生時從 manifest 還原的值相同
+	var data map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, newDetailError(ErrInvalidVariable, "dataCommand %q must print a JSON object: %v", command, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, newDetailError(ErrInvalidVariable, "dataCommand %q must print a single JSON object", command)
+	}
+	if data == nil {
+		return nil, newDetailError(ErrInvalidVariable, "dataCommand %q must print a JSON object, got null", command)
+	}
+	g.trace("data", map[string]interface{}{"command": command, "keys": len(data)})
+	return data, nil
+}
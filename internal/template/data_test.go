@@ -0,0 +1,140 @@
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stubDataCommand 在測試期間以固定的輸出取代 dataCommand，回傳實際執行的命令
+func stubDataCommand(t *testing.T, output string, err error) *[]string {
+	t.Helper()
+	var commands []string
+	previous := runDataCommand
+	runDataCommand = func(command, dir string) ([]byte, error) {
+		commands = append(commands, command)
+		return []byte(output), err
+	}
+	t.Cleanup(func() { runDataCommand = previous })
+	return &commands
+}
+
+func TestTemplateData(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: data
+dataCommand: "describe {{ .ProjectName }}"
+variables:
+  - name: Owner
+    default: nobody
+`,
+		"info.txt.tmpl": "{{ .Owner }} {{ .GitUser }}{{ range .Ports }} {{ . }}{{ end }}\n",
+	}
+
+	tests := []struct {
+		name       string
+		output     string
+		commandErr error
+		values     map[string]string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:   "object populates variables",
+			output: `{"GitUser": "alice", "Owner": "team-a", "Ports": [8080, 8081]}`,
+			want:   "team-a alice 8080 8081",
+		},
+		{
+			name:   "--set wins over data",
+			output: `{"GitUser": "alice", "Owner": "team-a", "Ports": []}`,
+			values: map[string]string{"Owner": "me", "GitUser": "bob"},
+			want:   "me bob",
+		},
+		{
+			name:    "invalid JSON",
+			output:  "GitUser=alice",
+			wantErr: "must print a JSON object",
+		},
+		{
+			name:    "not an object",
+			output:  `["alice"]`,
+			wantErr: "must print a JSON object",
+		},
+		{
+			name:    "null",
+			output:  "null",
+			wantErr: "got null",
+		},
+		{
+			name:    "more than one object",
+			output:  `{"GitUser": "alice"} {"GitUser": "bob"}`,
+			wantErr: "must print a single JSON object",
+		},
+		{
+			name:       "command fails",
+			commandErr: errors.New("exit status 1"),
+			wantErr:    `dataCommand "describe app" failed`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.Values = tt.values
+			commands := stubDataCommand(t, tt.output, tt.commandErr)
+
+			_, err := generator.Generate("app", name)
+			if len(*commands) != 1 || (*commands)[0] != "describe app" {
+				t.Errorf("dataCommand calls = %q, want the rendered command once", *commands)
+			}
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidVariable) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want ErrInvalidVariable containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(generator.WorkDir, "app", "info.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(data)); got != tt.want {
+				t.Errorf("info.txt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateDataNumbers(t *testing.T) {
+	stubDataCommand(t, `{"Port": 8080, "Ratio": 0.5}`, nil)
+	generator := &Generator{}
+
+	data, err := generator.templateData(&TemplateConfig{DataCommand: "ports"}, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("templateData() error = %v", err)
+	}
+	for name, want := range map[string]json.Number{"Port": "8080", "Ratio": "0.5"} {
+		if got, ok := data[name].(json.Number); !ok || got != want {
+			t.Errorf("%s = %#v, want json.Number %s", name, data[name], want)
+		}
+	}
+}
+
+func TestTemplateDataSkippedWhenPlanning(t *testing.T) {
+	commands := stubDataCommand(t, `{"GitUser": "alice"}`, nil)
+	generator := &Generator{planning: true}
+
+	data, err := generator.templateData(&TemplateConfig{DataCommand: "describe"}, map[string]interface{}{})
+	if err != nil || data != nil {
+		t.Fatalf("templateData() = %v, %v, want nil without running the command", data, err)
+	}
+	if len(*commands) != 0 {
+		t.Errorf("dataCommand ran during planning: %q", *commands)
+	}
+}
@@ -0,0 +1,84 @@
+package template
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// DefaultsFileName 為模板根目錄中提供變數預設值的 .env 檔案，不會被複製到專案
+const DefaultsFileName = "defaults.env"
+
+// isTemplateMetaFile 回傳 path 是否為模板本身的設定檔（含 imports 引用的片段），而非要產生的內容
+func isTemplateMetaFile(config *TemplateConfig, path string) bool {
+	return isConfigFileName(path) || path == DefaultsFileName || path == InstallInfoFileName || isImportedFragment(config, path)
+}
+
+// loadEnvDefaults 讀取模板的 defaults.env；檔案不存在時回傳空的 map
+func loadEnvDefaults(files fs.FS) (map[string]string, error) {
+	data, err := fs.ReadFile(files, DefaultsFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseDotenv(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", DefaultsFileName, err)
+	}
+	return values, nil
+}
+
+// withEnvDefaults 回傳以 defaults.env 補上預設值的設定副本：
+// 只有 template.yaml 未宣告預設值的變數才會採用 defaults.env 中的同名值
+func withEnvDefaults(tmpl *Template) (*TemplateConfig, error) {
+	if tmpl.Config == nil || tmpl.Files == nil {
+		return tmpl.Config, nil
+	}
+
+	defaults, err := loadEnvDefaults(tmpl.Files)
+	if err != nil || len(defaults) == 0 {
+		return tmpl.Config, err
+	}
+
+	config := *tmpl.Config
+	config.Variables = append([]TemplateVar(nil), tmpl.Config.Variables...)
+	for i, variable := range config.Variables {
+		if value, ok := defaults[variable.Name]; ok && variable.Default == "" {
+			config.Variables[i].Default = value
+		}
+	}
+	return &config, nil
+}
+
+// parseDotenv 解析 KEY=VALUE 格式的內容，支援 # 註解、export 前綴與成對的引號
+func parseDotenv(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
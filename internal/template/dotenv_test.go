@@ -0,0 +1,107 @@
+package template
+
+import (
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:    "values, comments and export",
+			content: "# comment\n\nPORT=8080\nexport AUTHOR = Jane Doe \nEMPTY=\n",
+			want:    map[string]string{"PORT": "8080", "AUTHOR": "Jane Doe", "EMPTY": ""},
+		},
+		{
+			name:    "quotes",
+			content: "A=\"hello world\"\nB='single'\nC=\"unbalanced'\nD=a=b\n",
+			want:    map[string]string{"A": "hello world", "B": "single", "C": "\"unbalanced'", "D": "a=b"},
+		},
+		{name: "missing separator", content: "PORT=1\nBROKEN\n", wantErr: "line 2: expected KEY=VALUE"},
+		{name: "missing key", content: "=value\n", wantErr: "line 1: expected KEY=VALUE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDotenv([]byte(tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseDotenv() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDotenv() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDotenv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvDefaults(t *testing.T) {
+	config := `name: envdefaults
+variables:
+  - name: Port
+  - name: Author
+    default: from-yaml
+`
+
+	tests := []struct {
+		name       string
+		defaults   string
+		values     map[string]string
+		wantPort   string
+		wantAuthor string
+		wantErr    string
+	}{
+		{name: "fills a missing default", defaults: "Port=9000\n", wantPort: "9000", wantAuthor: "from-yaml"},
+		{name: "template.yaml default wins", defaults: "Port=9000\nAuthor=from-env\n", wantPort: "9000", wantAuthor: "from-yaml"},
+		{name: "--set wins", defaults: "Port=9000\n", values: map[string]string{"Port": "1234"}, wantPort: "1234", wantAuthor: "from-yaml"},
+		{name: "invalid file", defaults: "Port\n", wantErr: "failed to parse defaults.env"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml":   config,
+				"defaults.env":    tt.defaults,
+				"values.txt.tmpl": "{{ .Port }} {{ .Author }}",
+			})
+			generator := newTestGenerator(t, manager)
+			generator.Values = tt.values
+
+			result, err := generator.Generate("app", name)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			project := os.DirFS(result.ProjectDir)
+			data, err := fs.ReadFile(project, "values.txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := string(data), tt.wantPort+" "+tt.wantAuthor; got != want {
+				t.Errorf("values.txt = %q, want %q", got, want)
+			}
+			if _, err := fs.Stat(project, DefaultsFileName); err == nil {
+				t.Errorf("%s was copied into the project", DefaultsFileName)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package template
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DryRunReport 為 DryRun 的結果：將產生的 manifest，以及每個 post-generate 命令的檢查結果
+type DryRunReport struct {
+	Manifest *Manifest
+	Commands []CommandCheck
+}
+
+// CommandCheck 為單一 post-generate 命令的預檢結果；Problems 為空表示命令可以執行
+type CommandCheck struct {
+	// Command 為渲染後的命令，渲染失敗時為原始命令
+	Command string
+	WorkDir string
+	// Skipped 表示命令因 os 限制不會在此平台執行
+	Skipped  bool
+	Problems []string
+}
+
+// Problems 回傳所有命令的問題數量
+func (r *DryRunReport) Problems() int {
+	count := 0
+	for _, check := range r.Commands {
+		count += len(check.Problems)
+	}
+	return count
+}
+
+// DryRun 與 Plan 相同地在記憶體中渲染模板，並預檢 post-generate 命令：渲染命令模板、
+// 確認 workDir 位於專案內且由模板產生、命令使用的工具在 PATH 上。不寫入檔案也不執行任何命令
+func (g *Generator) DryRun(projectName, templateName string) (*DryRunReport, error) {
+	manifest, err := g.Plan(projectName, templateName)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := g.manager.GetTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDir := g.projectDir(projectName)
+	projectPath, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path: %w", err)
+	}
+
+	vars := make(map[string]interface{}, len(manifest.Variables)+2)
+	for name, value := range manifest.Variables {
+		vars[name] = value
+	}
+	// manifest 不含 secret 變數的值；檢查命令時以遮罩代替，報告中也不會出現實際的值
+	for _, name := range manifest.Secrets {
+		vars[name] = secretMask
+	}
+	vars["ProjectPath"] = projectPath
+	vars["ProjectDir"] = projectDir
+
+	report := &DryRunReport{Manifest: manifest}
+	if tmpl.Config == nil {
+		return report, nil
+	}
+	for _, command := range tmpl.Config.PostGenerate {
+		report.Commands = append(report.Commands, checkPostCommand(command, projectDir, manifest.Files, vars))
+	}
+	return report, nil
+}
+
+func checkPostCommand(command PostCommand, projectDir string, files []string, vars map[string]interface{}) CommandCheck {
+	check := CommandCheck{Command: command.Command, WorkDir: filepath.Join(projectDir, command.WorkDir)}
+	if !matchesOS(command.OS) {
+		check.Skipped = true
+		return check
+	}
+
+	tmpl, err := template.New("command").Option("missingkey=error").Funcs(templateFuncs()).Parse(command.Command)
+	if err == nil {
+		var buf strings.Builder
+		if err = tmpl.Execute(&buf, vars); err == nil {
+			check.Command = buf.String()
+		}
+	}
+	if err != nil {
+		check.Problems = append(check.Problems, fmt.Sprintf("template error: %v", err))
+	}
+
+	workDir := path.Clean(strings.TrimPrefix(filepath.ToSlash(command.WorkDir), "./"))
+	switch {
+	case !isWithinDir(projectDir, check.WorkDir):
+		check.Problems = append(check.Problems, fmt.Sprintf("workDir %q is outside the project directory", command.WorkDir))
+	case workDir != "." && workDir != "" && !generatesUnder(files, workDir):
+		check.Problems = append(check.Problems, fmt.Sprintf("workDir %q is not created by the template", command.WorkDir))
+	}
+
+	for _, word := range commandWords(check.Command) {
+		if _, err := exec.LookPath(word); err != nil {
+			problem := fmt.Sprintf("%s not found in PATH", word)
+			if hint := toolHints[word]; hint != "" {
+				problem += "; " + hint
+			}
+			check.Problems = append(check.Problems, problem)
+		}
+	}
+	return check
+}
+
+// generatesUnder 回傳 files 中是否有位於 dir 之下的檔案（皆為以 / 分隔的相對路徑）
+func generatesUnder(files []string, dir string) bool {
+	for _, file := range files {
+		if strings.HasPrefix(file, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeTools 讓 PATH 只包含名為 tools 的假執行檔
+func fakeTools(t *testing.T, tools ...string) {
+	t.Helper()
+	bin := t.TempDir()
+	for _, tool := range tools {
+		if err := os.WriteFile(filepath.Join(bin, tool), []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+}
+
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     string
+		workDir     string
+		os          string
+		wantCommand string
+		wantSkipped bool
+		// wantProblems 為各問題應包含的文字
+		wantProblems []string
+	}{
+		{name: "runnable", command: "go mod tidy", wantCommand: "go mod tidy"},
+		{name: "rendered", command: "go mod init {{ .ProjectName }}", workDir: "api", wantCommand: "go mod init app"},
+		{name: "tool missing", command: "npm install", wantCommand: "npm install", wantProblems: []string{"npm not found in PATH; install Node.js"}},
+		{name: "template error", command: "go run {{ .Missing }}", wantCommand: "go run {{ .Missing }}", wantProblems: []string{`template error:`}},
+		{name: "workDir outside the project", command: "go build", workDir: "../elsewhere", wantCommand: "go build", wantProblems: []string{`workDir "../elsewhere" is outside the project directory`}},
+		{name: "workDir not generated", command: "go build", workDir: "web", wantCommand: "go build", wantProblems: []string{`workDir "web" is not created by the template`}},
+		{name: "other OS", command: "npm install", os: "[plan9]", wantCommand: "npm install", wantSkipped: true},
+		// manifest 不含 secret 的值，命令中以遮罩顯示
+		{name: "secret variable", command: "go run . --token {{ .Token }}", wantCommand: "go run . --token " + secretMask},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			command := "  - command: " + quoteYAML(tt.command) + "\n"
+			if tt.workDir != "" {
+				command += "    workDir: " + tt.workDir + "\n"
+			}
+			if tt.os != "" {
+				command += "    os: " + tt.os + "\n"
+			}
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml": "name: dry\nvariables:\n  - name: Token\n    secret: true\n    default: hunter2\npostGenerate:\n" + command,
+				"api/main.go":   "package main\n",
+			})
+			fakeTools(t, "go")
+			generator := newTestGenerator(t, manager)
+
+			report, err := generator.DryRun("app", name)
+			if err != nil {
+				t.Fatalf("DryRun() error = %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(generator.WorkDir, "app")); !os.IsNotExist(err) {
+				t.Error("dry run created the project directory")
+			}
+			if !reflect.DeepEqual(report.Manifest.Files, []string{"api/main.go"}) {
+				t.Errorf("manifest files = %v, want [api/main.go]", report.Manifest.Files)
+			}
+			if len(report.Commands) != 1 {
+				t.Fatalf("commands = %+v, want one check", report.Commands)
+			}
+			check := report.Commands[0]
+			if check.Command != tt.wantCommand || check.Skipped != tt.wantSkipped {
+				t.Errorf("check = %+v, want command %q, skipped %v", check, tt.wantCommand, tt.wantSkipped)
+			}
+			if len(check.Problems) != len(tt.wantProblems) {
+				t.Fatalf("problems = %q, want %q", check.Problems, tt.wantProblems)
+			}
+			for i, want := range tt.wantProblems {
+				if !strings.Contains(check.Problems[i], want) {
+					t.Errorf("problem %d = %q, want %q", i, check.Problems[i], want)
+				}
+			}
+			if report.Problems() != len(tt.wantProblems) {
+				t.Errorf("Problems() = %d, want %d", report.Problems(), len(tt.wantProblems))
+			}
+		})
+	}
+}
+
+// quoteYAML 以雙引號包住 YAML 字串值
+func quoteYAML(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
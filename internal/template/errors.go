@@ -0,0 +1,66 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 常見失敗情況的 sentinel 錯誤，可使用 errors.Is 比對
+var (
+	ErrTemplateNotFound   = errors.New("template not found")
+	ErrDirExists          = errors.New("directory already exists")
+	ErrInvalidVariable    = errors.New("invalid variable value")
+	ErrPostCommandFailed  = errors.New("post-generate command failed")
+	ErrUnsafeProjectDir   = errors.New("project directory contains the current working directory")
+	ErrTemplateDeprecated = errors.New("template is deprecated")
+	ErrNoMatchingFiles    = errors.New("no template files matched the file rules")
+	ErrFileNotGenerated   = errors.New("file was not generated from a template")
+	ErrInvalidGoName      = errors.New("invalid Go module or package name")
+	ErrGeneratorTooOld    = errors.New("template requires a newer generator version")
+	ErrUnknownVariable    = errors.New("variable is not declared by the template")
+	ErrMissingTool        = errors.New("tool required by post-generate commands not found")
+	ErrMissingEnv         = errors.New("required environment variable is not set")
+	ErrValidationFailed   = errors.New("template validation command failed")
+	// ErrEmbeddedTemplates 表示內嵌模板無法載入；模板在編譯時嵌入，通常代表建置有問題
+	ErrEmbeddedTemplates = errors.New("no built-in templates available")
+)
+
+// VariableError 表示變數值不合法
+type VariableError struct {
+	Name    string
+	Value   string
+	Options []string
+}
+
+func (e *VariableError) Error() string {
+	return fmt.Sprintf("invalid value '%s' for variable '%s'. valid options: %v", e.Value, e.Name, e.Options)
+}
+
+func (e *VariableError) Unwrap() error { return ErrInvalidVariable }
+
+// PostCommandError 表示 post-generate 命令執行失敗
+type PostCommandError struct {
+	Command string
+	WorkDir string
+	Err     error
+}
+
+func (e *PostCommandError) Error() string {
+	return fmt.Sprintf("command failed: %s: %v", e.Command, e.Err)
+}
+
+func (e *PostCommandError) Unwrap() []error { return []error{ErrPostCommandFailed, e.Err} }
+
+// detailError 保留原本易讀的錯誤訊息，同時讓 errors.Is 能比對到對應的 sentinel
+type detailError struct {
+	sentinel error
+	msg      string
+}
+
+func (e *detailError) Error() string { return e.msg }
+
+func (e *detailError) Unwrap() error { return e.sentinel }
+
+func newDetailError(sentinel error, format string, args ...interface{}) error {
+	return &detailError{sentinel: sentinel, msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,88 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		template string
+		values   map[string]string
+		existing bool
+		want     error
+		// wantResult 表示錯誤發生時專案已產生，仍應回傳 result
+		wantResult bool
+	}{
+		{
+			name:     "unknown template",
+			config:   "name: errs\n",
+			template: "missing",
+			want:     ErrTemplateNotFound,
+		},
+		{
+			name:     "project directory exists",
+			config:   "name: errs\n",
+			existing: true,
+			want:     ErrDirExists,
+		},
+		{
+			name:   "required variable without a value",
+			config: "name: errs\nvariables:\n  - name: Owner\n    required: true\n",
+			want:   ErrInvalidVariable,
+		},
+		{
+			name:   "select value outside the options",
+			config: "name: errs\nvariables:\n  - name: DB\n    type: select\n    options: [sqlite]\n",
+			values: map[string]string{"DB": "mysql"},
+			want:   ErrInvalidVariable,
+		},
+		{
+			name:       "failing post-generate command",
+			config:     "name: errs\npostGenerate:\n  - command: exit 3\n  - command: \"true\"\n",
+			want:       ErrPostCommandFailed,
+			wantResult: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml":  tt.config,
+				"README.md.tmpl": "# {{ .ProjectName }}\n",
+			})
+			if tt.template != "" {
+				name = tt.template
+			}
+
+			generator := newTestGenerator(t, manager)
+			generator.Values = tt.values
+			if tt.existing {
+				if err := os.Mkdir(filepath.Join(generator.WorkDir, "app"), 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result, err := generator.Generate("app", name)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Generate() error = %v, want errors.Is %v", err, tt.want)
+			}
+			if tt.wantResult {
+				if result == nil || len(result.Commands) != 2 {
+					t.Fatalf("Generate() result = %+v, want both commands recorded", result)
+				}
+				var cmdErr *PostCommandError
+				if !errors.As(err, &cmdErr) || cmdErr.Command != "exit 3" {
+					t.Errorf("errors.As PostCommandError = %+v", cmdErr)
+				}
+			} else if result != nil {
+				t.Errorf("Generate() result = %+v, want nil", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ExportTemplates 將所有已安裝的用戶模板複製到 dst/<name>，供備份或搬移到其他機器。
+// dst 中已存在同名目錄時中止，不覆寫任何內容。回傳已匯出的模板名稱（依名稱排序）
+func (m *Manager) ExportTemplates(dst string) ([]string, error) {
+	names := make([]string, 0, len(m.userTemplates))
+	for name := range m.userTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dst, name)); err == nil {
+			return nil, newDetailError(ErrDirExists, "directory '%s' already exists", filepath.Join(dst, name))
+		}
+	}
+	if err := os.MkdirAll(dst, defaultDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	for i, name := range names {
+		if err := copyDir(m.userTemplates[name].LocalPath, filepath.Join(dst, name), false); err != nil {
+			return names[:i], fmt.Errorf("failed to export template '%s': %w", name, err)
+		}
+	}
+	return names, nil
+}
+
+// TemplateDirs 回傳 dir 底下所有含有模板設定檔的子目錄（依名稱排序），例如 ExportTemplates 的輸出
+func TemplateDirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if hasConfigFile(path) {
+			dirs = append(dirs, path)
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,89 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExportTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []string
+		existing  string
+		want      []string
+		wantErr   error
+	}{
+		{name: "user templates", templates: []string{"web", "api"}, want: []string{"api", "web"}},
+		{name: "nothing installed", want: []string{}},
+		{name: "existing directory", templates: []string{"web", "api"}, existing: "web", wantErr: ErrDirExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			for _, name := range tt.templates {
+				installTestTemplate(t, manager, map[string]string{"template.yaml": "name: " + name + "\n", "main.go": "package " + name + "\n"})
+			}
+			dst := filepath.Join(t.TempDir(), "backup")
+			if tt.existing != "" {
+				writeFiles(t, filepath.Join(dst, tt.existing), map[string]string{"keep.txt": "mine"})
+			}
+
+			names, err := manager.ExportTemplates(dst)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ExportTemplates() error = %v, want %v", err, tt.wantErr)
+				}
+				if _, err := os.Stat(filepath.Join(dst, tt.existing, "keep.txt")); err != nil {
+					t.Error("existing directory was modified")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportTemplates() error = %v", err)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("exported %v, want %v", names, tt.want)
+			}
+
+			dirs, err := TemplateDirs(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var wantDirs []string
+			for _, name := range tt.want {
+				wantDirs = append(wantDirs, filepath.Join(dst, name))
+			}
+			if !reflect.DeepEqual(dirs, wantDirs) {
+				t.Errorf("TemplateDirs() = %v, want %v", dirs, wantDirs)
+			}
+		})
+	}
+}
+
+func TestTemplateDirs(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a/template.yaml":        "name: a\n",
+		"b/template.yml":         "name: b\n",
+		"notes/readme.md":        "not a template\n",
+		"nested/c/template.yaml": "name: c\n",
+		"file.txt":               "x\n",
+	})
+
+	dirs, err := TemplateDirs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "a"), filepath.Join(dir, "b")}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("TemplateDirs() = %v, want %v", dirs, want)
+	}
+
+	if _, err := TemplateDirs(filepath.Join(dir, "missing")); err == nil {
+		t.Error("TemplateDirs() of a missing directory succeeded")
+	}
+}
@@ -0,0 +1,113 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Fetcher 將遠端模板下載到本機目錄 dst，之後交由本機安裝流程處理
+type Fetcher interface {
+	Fetch(source, dst string) error
+}
+
+// RegisterFetcher 為指定的來源 scheme（例如 "oci"）註冊下載器
+func (m *Manager) RegisterFetcher(scheme string, fetcher Fetcher) {
+	m.fetchers[scheme] = fetcher
+}
+
+func (m *Manager) fetcherFor(source string) (Fetcher, bool) {
+	scheme, _, ok := strings.Cut(source, "://")
+	if !ok {
+		return nil, false
+	}
+	fetcher, exists := m.fetchers[scheme]
+	return fetcher, exists
+}
+
+// installFetchedTemplate 將遠端模板下載到暫存目錄後以本機安裝流程安裝，
+// 以套用相同的設定檢查與簽章驗證。以 digest 指定的來源會下載到快取目錄並重複使用，見 CacheDir。
+// 來源可用 //subdir 選取儲存庫或封存中的子目錄，例如 "https://github.com/me/templates//go-api"
+func (m *Manager) installFetchedTemplate(fetcher Fetcher, source string, info *InstallInfo) (*InstallResult, error) {
+	source, subdir := splitSubdir(source)
+	if info != nil {
+		info.Subdir = subdir
+	}
+
+	var fetchedDir string
+	if isCacheableSource(source) {
+		dir, err := fetchCached(m.status(), fetcher, source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+		}
+		fetchedDir = dir
+	} else {
+		dir, err := os.MkdirTemp("", "aaa-generator-fetch-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		if err := fetcher.Fetch(source, dir); err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+		}
+		fetchedDir = dir
+	}
+
+	if subdir != "" {
+		root := filepath.Join(fetchedDir, filepath.FromSlash(subdir))
+		if !isWithinDir(fetchedDir, root) {
+			return nil, fmt.Errorf("subdirectory %q escapes the fetched template", subdir)
+		}
+		if !hasConfigFile(root) {
+			return nil, fmt.Errorf("invalid template %s: template.yaml not found in %s", source, subdir)
+		}
+		return m.installLocalTemplate(root, info)
+	}
+
+	root, err := findTemplateRoot(fetchedDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", source, err)
+	}
+	return m.installLocalTemplate(root, info)
+}
+
+// splitSubdir 拆出來源中 scheme 之後的 //subdir 選取器。子目錄結尾的 @ref 會移回來源，
+// 因此 "repo//go-api@v2" 與 "repo@v2//go-api" 都選取 v2 的 go-api 目錄
+func splitSubdir(source string) (base, subdir string) {
+	start := 0
+	if i := strings.Index(source, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	i := strings.Index(source[start:], "//")
+	if i < 0 {
+		return source, ""
+	}
+
+	base, subdir = source[:start+i], strings.Trim(source[start+i+2:], "/")
+	if at := strings.LastIndex(subdir, "@"); at >= 0 {
+		base += subdir[at:]
+		subdir = strings.Trim(subdir[:at], "/")
+	}
+	return base, subdir
+}
+
+// findTemplateRoot 回傳含有模板設定檔的目錄：dir 本身，或其唯一的子目錄
+func findTemplateRoot(dir string) (string, error) {
+	if hasConfigFile(dir) {
+		return dir, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		sub := filepath.Join(dir, entries[0].Name())
+		if hasConfigFile(sub) {
+			return sub, nil
+		}
+	}
+	return "", fmt.Errorf("template.yaml not found at the template root")
+}
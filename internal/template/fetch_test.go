@@ -0,0 +1,151 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// treeFetcher 下載時把固定的檔案樹寫入目的目錄，並記錄實際下載的來源
+type treeFetcher struct {
+	t      *testing.T
+	files  map[string]string
+	source string
+}
+
+func (f *treeFetcher) Fetch(source, dst string) error {
+	f.source = source
+	writeFiles(f.t, dst, f.files)
+	return nil
+}
+
+func TestInstallFetchedTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		wantName string
+		wantErr  string
+	}{
+		{
+			name:     "template at the root",
+			files:    map[string]string{"template.yaml": "name: root\n", "main.go": "package main\n"},
+			wantName: "root",
+		},
+		{
+			name:     "single wrapping directory",
+			files:    map[string]string{"tpl-v1/template.yaml": "name: wrapped\n", "tpl-v1/main.go": "package main\n"},
+			wantName: "wrapped",
+		},
+		{
+			name:    "several top-level directories",
+			files:   map[string]string{"a/template.yaml": "name: a\n", "b/template.yaml": "name: b\n"},
+			wantErr: "template.yaml not found at the template root",
+		},
+		{
+			name:    "no template config",
+			files:   map[string]string{"README.md": "# nothing\n"},
+			wantErr: "template.yaml not found at the template root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			manager.RegisterFetcher("fake", &treeFetcher{t: t, files: tt.files})
+
+			result, err := manager.InstallTemplate("fake://registry/tpl:latest")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("InstallTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InstallTemplate() error = %v", err)
+			}
+			if result.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", result.Name, tt.wantName)
+			}
+			if _, err := os.Stat(filepath.Join(result.Path, "main.go")); err != nil {
+				t.Errorf("main.go not installed: %v", err)
+			}
+		})
+	}
+}
+
+func TestSplitSubdir(t *testing.T) {
+	tests := []struct {
+		source     string
+		wantBase   string
+		wantSubdir string
+	}{
+		{source: "https://github.com/me/templates", wantBase: "https://github.com/me/templates"},
+		{source: "https://github.com/me/templates//go-api", wantBase: "https://github.com/me/templates", wantSubdir: "go-api"},
+		{source: "https://github.com/me/templates//go-api/", wantBase: "https://github.com/me/templates", wantSubdir: "go-api"},
+		{source: "https://github.com/me/templates//go/api", wantBase: "https://github.com/me/templates", wantSubdir: "go/api"},
+		{source: "https://github.com/me/templates//go-api@v2", wantBase: "https://github.com/me/templates@v2", wantSubdir: "go-api"},
+		{source: "https://github.com/me/templates@v2//go-api", wantBase: "https://github.com/me/templates@v2", wantSubdir: "go-api"},
+		{source: "oci://ghcr.io/me/templates:v1//go-api", wantBase: "oci://ghcr.io/me/templates:v1", wantSubdir: "go-api"},
+		{source: "git@github.com:me/templates.git//go-api", wantBase: "git@github.com:me/templates.git", wantSubdir: "go-api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			base, subdir := splitSubdir(tt.source)
+			if base != tt.wantBase || subdir != tt.wantSubdir {
+				t.Errorf("splitSubdir() = (%q, %q), want (%q, %q)", base, subdir, tt.wantBase, tt.wantSubdir)
+			}
+		})
+	}
+}
+
+func TestInstallSubdir(t *testing.T) {
+	files := map[string]string{
+		"go-api/template.yaml": "name: go-api\n",
+		"go-api/main.go":       "package main\n",
+		"docs/README.md":       "# docs\n",
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{name: "selected subdirectory", source: "fake://registry/templates:latest//go-api"},
+		{name: "subdirectory without config", source: "fake://registry/templates:latest//docs", wantErr: "template.yaml not found in docs"},
+		{name: "subdirectory escaping the fetch", source: "fake://registry/templates:latest//../go-api", wantErr: "escapes the fetched template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			fetcher := &treeFetcher{t: t, files: files}
+			manager.RegisterFetcher("fake", fetcher)
+
+			result, err := manager.InstallTemplate(tt.source)
+			if fetcher.source != "fake://registry/templates:latest" {
+				t.Errorf("fetched source = %q, want the selector stripped", fetcher.source)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("InstallTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InstallTemplate() error = %v", err)
+			}
+			if result.Name != "go-api" {
+				t.Errorf("Name = %q, want go-api", result.Name)
+			}
+			info, err := LoadInstallInfo(result.Path)
+			if err != nil || info == nil {
+				t.Fatalf("LoadInstallInfo() = %v, %v", info, err)
+			}
+			if info.Subdir != "go-api" {
+				t.Errorf("Subdir = %q, want go-api", info.Subdir)
+			}
+		})
+	}
+}
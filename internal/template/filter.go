@@ -0,0 +1,30 @@
+package template
+
+import "strings"
+
+// excluded 回傳檔案是否因 IncludeOnly 或 Exclude 被略過：先只保留符合 IncludeOnly 的檔案，
+// 再排除符合 Exclude 的檔案。樣式比對來源路徑或目標路徑（去除 .tmpl）
+func (g *Generator) excluded(source, target string) bool {
+	target = strings.TrimSuffix(target, ".tmpl")
+	if len(g.IncludeOnly) > 0 && !matchesAnyPath(g.IncludeOnly, source, target) {
+		return true
+	}
+	return matchesAnyPath(g.Exclude, source, target)
+}
+
+// matchesAnyPath 回傳任一路徑是否符合任一 glob 樣式（支援 **）；
+// 樣式也比對路徑的上層目錄，因此 ".github" 會涵蓋 ".github/" 下的所有檔案
+func matchesAnyPath(patterns []string, paths ...string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.Trim(strings.TrimPrefix(strings.TrimSpace(pattern), "./"), "/")
+		if pattern == "" {
+			continue
+		}
+		for _, name := range paths {
+			if matchGlob(pattern, name) || matchGlob(pattern+"/**", name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package template
+
+import "testing"
+
+func TestMatchesAnyPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		paths    []string
+		want     bool
+	}{
+		{name: "exact file", patterns: []string{"README.md"}, paths: []string{"README.md"}, want: true},
+		{name: "no match", patterns: []string{"README.md"}, paths: []string{"docs/README.md"}},
+		{name: "directory covers its files", patterns: []string{".github"}, paths: []string{".github/workflows/ci.yml"}, want: true},
+		{name: "trailing slash and ./ prefix", patterns: []string{"./docs/"}, paths: []string{"docs/guide.md"}, want: true},
+		{name: "star stays within a segment", patterns: []string{"*.md"}, paths: []string{"docs/guide.md"}},
+		{name: "double star crosses segments", patterns: []string{"**/*.md"}, paths: []string{"docs/guide.md"}, want: true},
+		{name: "any of several paths", patterns: []string{"main.go"}, paths: []string{"cmd/main.go.tmpl", "main.go"}, want: true},
+		{name: "blank pattern is ignored", patterns: []string{"  ", ""}, paths: []string{"main.go"}},
+		{name: "prefix is not a directory", patterns: []string{"doc"}, paths: []string{"docs/guide.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAnyPath(tt.patterns, tt.paths...); got != tt.want {
+				t.Errorf("matchesAnyPath(%q, %q) = %v, want %v", tt.patterns, tt.paths, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	tests := []struct {
+		name        string
+		includeOnly []string
+		exclude     []string
+		source      string
+		target      string
+		want        bool
+	}{
+		{name: "nothing excluded", source: "main.go", target: "main.go"},
+		{name: "source path", exclude: []string{"templates/**"}, source: "templates/a.tmpl", target: "a", want: true},
+		{name: "target path", exclude: []string{"cmd/app"}, source: "cmd/main.go", target: "cmd/app/main.go", want: true},
+		{name: "target without .tmpl", exclude: []string{"Dockerfile"}, source: "docker/file", target: "Dockerfile.tmpl", want: true},
+		{name: "unrelated pattern", exclude: []string{"*.md"}, source: "main.go", target: "main.go"},
+		{name: "include-only keeps a match", includeOnly: []string{"frontend"}, source: "frontend/app.ts", target: "web/app.ts"},
+		{name: "include-only drops the rest", includeOnly: []string{"frontend"}, source: "backend/main.go", target: "backend/main.go", want: true},
+		{name: "include-only then exclude", includeOnly: []string{"frontend"}, exclude: []string{"**/*.test.ts"}, source: "frontend/app.test.ts", target: "frontend/app.test.ts", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := &Generator{IncludeOnly: tt.includeOnly, Exclude: tt.exclude}
+			if got := generator.excluded(tt.source, tt.target); got != tt.want {
+				t.Errorf("excluded(%q, %q) = %v, want %v", tt.source, tt.target, got, tt.want)
+			}
+		})
+	}
+}
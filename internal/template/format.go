@@ -0,0 +1,65 @@
+package template
+
+import (
+	"bytes"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"aaa-generator/internal/console"
+)
+
+// formatter 為產生後可執行的格式化工具；Command 與 Args 之後接上要格式化的檔案
+type formatter struct {
+	Command    string
+	Args       []string
+	Extensions []string
+}
+
+// formatters 依副檔名選擇格式化工具，未安裝的工具會被略過並顯示警告
+var formatters = []formatter{
+	{Command: "gofmt", Args: []string{"-w"}, Extensions: []string{".go"}},
+	{Command: "prettier", Args: []string{"--write"}, Extensions: []string{".js", ".jsx", ".ts", ".tsx", ".css", ".scss", ".json"}},
+}
+
+// shouldFormat 回傳這次產生是否要格式化輸出：Generator.Format 或模板的 format: true
+func (g *Generator) shouldFormat(config *TemplateConfig) bool {
+	return g.Format || (config != nil && config.Format)
+}
+
+// formatFiles 以對應的格式化工具處理 projectDir 中產生的檔案（files 為相對路徑、以 / 分隔）。
+// 工具不存在或執行失敗只顯示警告，不會中止產生
+func (g *Generator) formatFiles(projectDir string, files []string) {
+	for _, f := range formatters {
+		var targets []string
+		for _, file := range files {
+			if contains(f.Extensions, strings.ToLower(path.Ext(file))) {
+				targets = append(targets, filepath.FromSlash(file))
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+
+		if _, err := exec.LookPath(f.Command); err != nil {
+			g.status().Printf(console.Warning("   ⚠️  Warning: %s not found in PATH, %d file(s) left unformatted\n"), f.Command, len(targets))
+			continue
+		}
+
+		var output bytes.Buffer
+		cmd := exec.Command(f.Command, append(append([]string(nil), f.Args...), targets...)...)
+		cmd.Dir = projectDir
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		if err := cmd.Run(); err != nil {
+			g.status().Printf(console.Warning("   ⚠️  Warning: %s failed: %v\n"), f.Command, err)
+			if text := strings.TrimSpace(output.String()); text != "" {
+				g.status().Printf("      %s\n", strings.ReplaceAll(text, "\n", "\n      "))
+			}
+			continue
+		}
+		g.status().Printf("   • Formatted %d file(s) with %s\n", len(targets), f.Command)
+		g.trace("format", map[string]interface{}{"command": f.Command, "files": len(targets)})
+	}
+}
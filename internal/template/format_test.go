@@ -0,0 +1,104 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeFormatter 在 PATH 上放一個記錄參數到 log 的格式化工具；script 為額外的 shell 指令
+func fakeFormatter(t *testing.T, name, script string) string {
+	t.Helper()
+	bin := t.TempDir()
+	log := filepath.Join(bin, name+".log")
+	body := "#!/bin/sh\necho \"$@\" >> " + log + "\n" + script + "\n"
+	if err := os.WriteFile(filepath.Join(bin, name), []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	return log
+}
+
+func TestFormatFiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		noTool     bool
+		files      []string
+		wantArgs   string
+		wantOutput []string
+	}{
+		{
+			name:       "go files with gofmt",
+			files:      []string{"main.go", "cmd/app/APP.GO", "README.md"},
+			wantArgs:   "-w main.go cmd/app/APP.GO\n",
+			wantOutput: []string{"Formatted 2 file(s) with gofmt"},
+		},
+		{
+			name:  "no matching files",
+			files: []string{"README.md"},
+		},
+		{
+			name:       "formatter missing",
+			noTool:     true,
+			files:      []string{"main.go"},
+			wantOutput: []string{"gofmt not found in PATH, 1 file(s) left unformatted"},
+		},
+		{
+			name:       "formatter fails",
+			script:     "echo 'main.go:1:1: expected package' >&2\nexit 2",
+			files:      []string{"main.go"},
+			wantArgs:   "-w main.go\n",
+			wantOutput: []string{"gofmt failed: exit status 2", "main.go:1:1: expected package"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := fakeFormatter(t, "gofmt", tt.script)
+			if tt.noTool {
+				t.Setenv("PATH", t.TempDir())
+			}
+			generator := &Generator{}
+
+			output := captureStdout(t, func() { generator.formatFiles(t.TempDir(), tt.files) })
+
+			args, _ := os.ReadFile(log)
+			if string(args) != tt.wantArgs {
+				t.Errorf("gofmt args = %q, want %q", args, tt.wantArgs)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+			if len(tt.wantOutput) == 0 && output != "" {
+				t.Errorf("unexpected output:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestShouldFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   bool
+		config *TemplateConfig
+		want   bool
+	}{
+		{name: "neither", config: &TemplateConfig{}},
+		{name: "flag", flag: true, config: &TemplateConfig{}, want: true},
+		{name: "template format: true", config: &TemplateConfig{Format: true}, want: true},
+		{name: "nil config", flag: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := &Generator{Format: tt.flag}
+			if got := generator.shouldFormat(tt.config); got != tt.want {
+				t.Errorf("shouldFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFragment 為 imports 引用的共用設定片段，只能提供變數與 post-generate 命令
+type configFragment struct {
+	Variables    []TemplateVar `yaml:"variables"`
+	PostGenerate []PostCommand `yaml:"postGenerate"`
+}
+
+// parseTemplateConfig 解析 template.yaml 並合併 imports 列出的設定片段。
+// 片段路徑相對於模板根目錄（files），依序合併在模板本身的變數與命令之前；
+// 模板本身宣告的同名變數優先，片段中的同名變數會被忽略
+func parseTemplateConfig(data []byte, files fs.FS) (*TemplateConfig, error) {
+	var config TemplateConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if len(config.Imports) == 0 {
+		return &config, nil
+	}
+
+	declared := make(map[string]bool, len(config.Variables))
+	for _, variable := range config.Variables {
+		declared[variable.Name] = true
+	}
+
+	var variables []TemplateVar
+	var commands []PostCommand
+	for _, name := range config.Imports {
+		fragment, err := loadConfigFragment(files, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range fragment.Variables {
+			if declared[variable.Name] {
+				continue
+			}
+			declared[variable.Name] = true
+			variables = append(variables, variable)
+		}
+		commands = append(commands, fragment.PostGenerate...)
+	}
+
+	config.Variables = append(variables, config.Variables...)
+	config.PostGenerate = append(commands, config.PostGenerate...)
+	return &config, nil
+}
+
+// loadConfigFragment 讀取並解析單一設定片段
+func loadConfigFragment(files fs.FS, name string) (*configFragment, error) {
+	data, err := readConfigFragment(files, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var fragment configFragment
+	if err := yaml.Unmarshal(data, &fragment); err != nil {
+		return nil, fmt.Errorf("import %q: %w", name, err)
+	}
+	return &fragment, nil
+}
+
+// readConfigFragment 讀取單一設定片段的內容；路徑必須位於模板目錄內
+func readConfigFragment(files fs.FS, name string) ([]byte, error) {
+	fragmentPath := path.Clean(strings.TrimPrefix(strings.TrimSpace(name), "./"))
+	if !fs.ValidPath(fragmentPath) || fragmentPath == "." {
+		return nil, fmt.Errorf("import %q: path must stay inside the template directory", name)
+	}
+	if files == nil {
+		return nil, fmt.Errorf("import %q: template files are not available", name)
+	}
+
+	data, err := fs.ReadFile(files, fragmentPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("import %q: fragment not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("import %q: %w", name, err)
+	}
+	return data, nil
+}
+
+// isImportedFragment 回傳 path 是否為 config 引用的設定片段
+func isImportedFragment(config *TemplateConfig, filePath string) bool {
+	if config == nil {
+		return false
+	}
+	for _, name := range config.Imports {
+		if path.Clean(strings.TrimPrefix(strings.TrimSpace(name), "./")) == filePath {
+			return true
+		}
+	}
+	return false
+}
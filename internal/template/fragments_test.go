@@ -0,0 +1,129 @@
+package template
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseTemplateConfigImports(t *testing.T) {
+	files := fstest.MapFS{
+		"shared/go.yaml":   {Data: []byte("variables:\n  - name: GoVersion\n    default: \"1.22\"\n  - name: Module\n    default: fragment\npostGenerate:\n  - command: go mod tidy\n")},
+		"shared/lint.yaml": {Data: []byte("variables:\n  - name: GoVersion\n    default: \"1.21\"\npostGenerate:\n  - command: golangci-lint run\n")},
+		"shared/bad.yaml":  {Data: []byte("variables: [\n")},
+	}
+
+	tests := []struct {
+		name         string
+		config       string
+		files        fstest.MapFS
+		wantVars     []string
+		wantDefaults map[string]string
+		wantCommands []string
+		wantErr      string
+	}{
+		{
+			name:         "no imports",
+			config:       "name: t\nvariables:\n  - name: Module\n",
+			files:        files,
+			wantVars:     []string{"Module"},
+			wantCommands: []string{},
+		},
+		{
+			name:         "fragments merged before the template",
+			config:       "name: t\nimports: [shared/go.yaml, ./shared/lint.yaml]\nvariables:\n  - name: Module\n    default: template\npostGenerate:\n  - command: git init\n",
+			files:        files,
+			wantVars:     []string{"GoVersion", "Module"},
+			wantDefaults: map[string]string{"GoVersion": "1.22", "Module": "template"},
+			wantCommands: []string{"go mod tidy", "golangci-lint run", "git init"},
+		},
+		{
+			name:    "missing fragment",
+			config:  "name: t\nimports: [shared/none.yaml]\n",
+			files:   files,
+			wantErr: `import "shared/none.yaml": fragment not found`,
+		},
+		{
+			name:    "invalid fragment",
+			config:  "name: t\nimports: [shared/bad.yaml]\n",
+			files:   files,
+			wantErr: `import "shared/bad.yaml":`,
+		},
+		{
+			name:    "path outside the template",
+			config:  "name: t\nimports: [../other/go.yaml]\n",
+			files:   files,
+			wantErr: "path must stay inside the template directory",
+		},
+		{
+			name:    "no template files",
+			config:  "name: t\nimports: [shared/go.yaml]\n",
+			wantErr: "template files are not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config *TemplateConfig
+			var err error
+			if tt.files == nil {
+				config, err = parseTemplateConfig([]byte(tt.config), nil)
+			} else {
+				config, err = parseTemplateConfig([]byte(tt.config), tt.files)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseTemplateConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTemplateConfig() error = %v", err)
+			}
+
+			var names []string
+			for _, variable := range config.Variables {
+				names = append(names, variable.Name)
+				if want, ok := tt.wantDefaults[variable.Name]; ok && variable.Default != want {
+					t.Errorf("%s default = %q, want %q", variable.Name, variable.Default, want)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.wantVars) {
+				t.Errorf("variables = %q, want %q", names, tt.wantVars)
+			}
+			commands := []string{}
+			for _, command := range config.PostGenerate {
+				commands = append(commands, command.Command)
+			}
+			if !reflect.DeepEqual(commands, tt.wantCommands) {
+				t.Errorf("postGenerate = %q, want %q", commands, tt.wantCommands)
+			}
+		})
+	}
+}
+
+func TestIsImportedFragment(t *testing.T) {
+	config := &TemplateConfig{Imports: []string{"./shared/go.yaml", " shared/lint.yaml "}}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "shared/go.yaml", want: true},
+		{path: "shared/lint.yaml", want: true},
+		{path: "shared/other.yaml"},
+		{path: "go.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isImportedFragment(config, tt.path); got != tt.want {
+				t.Errorf("isImportedFragment(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+	if isImportedFragment(nil, "shared/go.yaml") {
+		t.Error("isImportedFragment(nil) = true, want false")
+	}
+}
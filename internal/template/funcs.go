@@ -0,0 +1,228 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"path"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// templateFuncs 回傳模板、命令與目標路徑共用的函式
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"trimSpace":  strings.TrimSpace,
+		"camelCase":  camelCase,
+		"pascalCase": pascalCase,
+		"snakeCase":  snakeCase,
+		"kebabCase":  kebabCase,
+		"indent":     indent,
+		"nindent":    nindent,
+		"include": func(string, interface{}) (string, error) {
+			return "", fmt.Errorf("include is only available in .tmpl files")
+		},
+		"randAlphaNum": func(n int) string { return randomString(rand.Intn, alphaNumChars, n) },
+		"randNumeric":  func(n int) string { return randomString(rand.Intn, numericChars, n) },
+	}
+}
+
+const (
+	alphaNumChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	numericChars  = "0123456789"
+)
+
+// randomFuncs 以 rng 取代隨機函式的來源，讓相同的 seed 產生相同的輸出
+func randomFuncs(rng *rand.Rand) template.FuncMap {
+	return template.FuncMap{
+		"randAlphaNum": func(n int) string { return randomString(rng.Intn, alphaNumChars, n) },
+		"randNumeric":  func(n int) string { return randomString(rng.Intn, numericChars, n) },
+	}
+}
+
+func randomString(intn func(int) int, chars string, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = chars[intn(len(chars))]
+	}
+	return string(b)
+}
+
+// bindInclude 讓 include 能渲染同一個檔案中以 {{ define }} 定義的區塊，
+// 例如 {{ include "imports" . | nindent 4 }}
+func bindInclude(tmpl *template.Template) *template.Template {
+	return tmpl.Funcs(template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			var buf bytes.Buffer
+			if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		},
+	})
+}
+
+// indent 在每一行非空白行前加上 spaces 個空格
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nindent 與 indent 相同，但先換行；內容為空時不輸出任何東西，避免留下空行
+func nindent(spaces int, s string) string {
+	if strings.TrimSpace(s) == "" {
+		return ""
+	}
+	return "\n" + indent(spaces, s)
+}
+
+// RenderName 以 {{ .Name }} 渲染命名樣式，例如 "svc-{{ .Name | kebabCase }}"
+func RenderName(pattern, name string) (string, error) {
+	tmpl, err := template.New("name").Funcs(templateFuncs()).Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse name template %q: %w", pattern, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]interface{}{"Name": name}); err != nil {
+		return "", fmt.Errorf("failed to render name template %q: %w", pattern, err)
+	}
+
+	rendered := strings.TrimSpace(buf.String())
+	if rendered == "" {
+		return "", fmt.Errorf("name template %q rendered an empty name", pattern)
+	}
+	return rendered, nil
+}
+
+// renderText 以變數渲染一般文字，例如 postMessage
+func renderText(text string, vars map[string]interface{}) (string, error) {
+	tmpl, err := template.New("text").Funcs(templateFuncs()).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %q: %w", text, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("failed to render %q: %w", text, err)
+	}
+	return buf.String(), nil
+}
+
+// renderPath 以變數渲染含有 {{ }} 的目標路徑，並確認結果仍位於專案目錄內
+func renderPath(target string, vars map[string]interface{}) (string, error) {
+	if !strings.Contains(target, "{{") {
+		return target, nil
+	}
+
+	tmpl, err := template.New("path").Funcs(templateFuncs()).Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse target path %s: %w", target, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("failed to render target path %s: %w", target, err)
+	}
+
+	rendered := path.Clean(buf.String())
+	if path.IsAbs(rendered) || rendered == ".." || strings.HasPrefix(rendered, "../") {
+		return "", fmt.Errorf("target path %s renders outside the project: %s", target, rendered)
+	}
+	return rendered, nil
+}
+
+// collapseBlankLines 將連續的空白行合併為一行，移除條件區塊被省略後留下的空行
+func collapseBlankLines(content []byte) []byte {
+	lines := strings.Split(string(content), "\n")
+	result := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		isBlank := strings.TrimSpace(line) == ""
+		if isBlank && blank {
+			continue
+		}
+		if isBlank {
+			line = ""
+		}
+		result = append(result, line)
+		blank = isBlank
+	}
+	return []byte(strings.Join(result, "\n"))
+}
+
+// splitWords 依非英數字元與大小寫邊界拆分字詞，例如 "myHTTPServer" → my, HTTP, Server
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(current))
+				current = nil
+			}
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func pascalCase(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+func camelCase(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.ToLower(words[0]))
+	for _, word := range words[1:] {
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+func snakeCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "_"))
+}
+
+func kebabCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
@@ -0,0 +1,321 @@
+package template
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestCaseHelpers(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantCamel  string
+		wantPascal string
+		wantSnake  string
+		wantKebab  string
+	}{
+		{input: "user profile", wantCamel: "userProfile", wantPascal: "UserProfile", wantSnake: "user_profile", wantKebab: "user-profile"},
+		{input: "myHTTPServer", wantCamel: "myHttpServer", wantPascal: "MyHttpServer", wantSnake: "my_http_server", wantKebab: "my-http-server"},
+		{input: "order-service_v2", wantCamel: "orderServiceV2", wantPascal: "OrderServiceV2", wantSnake: "order_service_v2", wantKebab: "order-service-v2"},
+		{input: "API", wantCamel: "api", wantPascal: "Api", wantSnake: "api", wantKebab: "api"},
+		{input: "  --  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			for name, got := range map[string][2]string{
+				"camelCase":  {camelCase(tt.input), tt.wantCamel},
+				"pascalCase": {pascalCase(tt.input), tt.wantPascal},
+				"snakeCase":  {snakeCase(tt.input), tt.wantSnake},
+				"kebabCase":  {kebabCase(tt.input), tt.wantKebab},
+			} {
+				if got[0] != got[1] {
+					t.Errorf("%s(%q) = %q, want %q", name, tt.input, got[0], got[1])
+				}
+			}
+		})
+	}
+}
+
+func TestRenderPath(t *testing.T) {
+	vars := map[string]interface{}{"ComponentName": "UserProfile", "Dir": "../.."}
+
+	tests := []struct {
+		target  string
+		want    string
+		wantErr string
+	}{
+		{target: "src/main.go", want: "src/main.go"},
+		{target: "components/{{ .ComponentName | kebabCase }}/index.ts", want: "components/user-profile/index.ts"},
+		{target: "{{ .ComponentName | snakeCase }}//./model.go", want: "user_profile/model.go"},
+		{target: "{{ .Dir }}/evil", wantErr: "renders outside the project"},
+		{target: "{{ .ComponentName ", wantErr: "failed to parse target path"},
+		{target: "{{ .ComponentName | nosuch }}", wantErr: "failed to parse target path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			got, err := renderPath(tt.target, vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("renderPath() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderPath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("renderPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateRenderedTargets(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: targets
+files:
+  - source: component.ts.tmpl
+    target: "components/{{ .ProjectName | kebabCase }}.ts"
+    type: file
+  - source: model
+    target: "models/{{ .ProjectName | snakeCase }}"
+`,
+		"component.ts.tmpl":                   "export class {{ .ProjectName | pascalCase }} {}\n",
+		"model/{{ .ProjectName | lower }}.go": "package model\n",
+	})
+	generator := newTestGenerator(t, manager)
+
+	output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "UserProfile"})
+	if err != nil {
+		t.Fatalf("GenerateFS() error = %v", err)
+	}
+	for path, want := range map[string]string{
+		"components/user-profile.ts":         "export class UserProfile {}\n",
+		"models/user_profile/userprofile.go": "package model\n",
+	} {
+		data, err := fs.ReadFile(output, path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", path, data, want)
+		}
+	}
+}
+
+func TestResolveProjectName(t *testing.T) {
+	tests := []struct {
+		name           string
+		nameTemplate   string
+		moduleTemplate string
+		want           string
+		wantModule     string
+		wantErr        string
+	}{
+		{name: "no patterns", want: "api"},
+		{name: "name pattern", nameTemplate: "svc-{{ .Name | kebabCase }}", want: "svc-api"},
+		{name: "module pattern", moduleTemplate: "github.com/acme/{{ .Name }}", want: "api", wantModule: "github.com/acme/api"},
+		{name: "unknown key", nameTemplate: "{{ .Project }}", wantErr: "failed to render name template"},
+		{name: "parse error", moduleTemplate: "{{ .Name", wantErr: "failed to parse name template"},
+		{name: "empty result", nameTemplate: "{{ if false }}x{{ end }}  ", wantErr: "rendered an empty name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := &Generator{NameTemplate: tt.nameTemplate, ModuleTemplate: tt.moduleTemplate}
+			got, err := generator.ResolveProjectName("api")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveProjectName() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveProjectName() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveProjectName() = %q, want %q", got, tt.want)
+			}
+			if module := generator.Values["ModuleName"]; module != tt.wantModule {
+				t.Errorf("ModuleName = %q, want %q", module, tt.wantModule)
+			}
+		})
+	}
+}
+
+func TestIndent(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantIndent  string
+		wantNindent string
+	}{
+		{name: "single line", input: "x", wantIndent: "  x", wantNindent: "\n  x"},
+		{name: "blank lines stay empty", input: "a\n\nb", wantIndent: "  a\n\n  b", wantNindent: "\n  a\n\n  b"},
+		{name: "empty content", input: "", wantIndent: "", wantNindent: ""},
+		{name: "whitespace only", input: " \n", wantIndent: " \n", wantNindent: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := indent(2, tt.input); got != tt.wantIndent {
+				t.Errorf("indent() = %q, want %q", got, tt.wantIndent)
+			}
+			if got := nindent(2, tt.input); got != tt.wantNindent {
+				t.Errorf("nindent() = %q, want %q", got, tt.wantNindent)
+			}
+		})
+	}
+}
+
+func TestProcessTemplateInclude(t *testing.T) {
+	const content = `{{ define "imports" }}{{ if .HTTP }}"net/http"{{ end }}{{ end -}}
+import (
+	"fmt"{{ include "imports" . | nindent 1 }}
+)
+`
+
+	tests := []struct {
+		name    string
+		content string
+		vars    map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{name: "block included", content: content, vars: map[string]interface{}{"HTTP": true}, want: "import (\n\t\"fmt\"\n \"net/http\"\n)\n"},
+		{name: "empty block leaves no line", content: content, vars: map[string]interface{}{"HTTP": false}, want: "import (\n\t\"fmt\"\n)\n"},
+		{name: "undefined block", content: `{{ include "missing" . }}`, wantErr: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := &Generator{}
+			got, err := generator.processTemplate([]byte(tt.content), "main.go", tt.vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("processTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("processTemplate() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("processTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := renderText(`{{ include "x" . }}`, nil); err == nil || !strings.Contains(err.Error(), "only available in .tmpl files") {
+		t.Errorf("include outside .tmpl error = %v", err)
+	}
+}
+
+func TestSeededRandomFuncs(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml":   "name: random\n",
+		"secret.txt.tmpl": "{{ randAlphaNum 16 }} {{ randNumeric 6 }}",
+	})
+
+	render := func(seed int64) string {
+		t.Helper()
+		generator := newTestGenerator(t, manager)
+		generator.Seed = seed
+		output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"})
+		if err != nil {
+			t.Fatalf("GenerateFS() error = %v", err)
+		}
+		data, err := fs.ReadFile(output, "secret.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	tests := []struct {
+		name      string
+		seeds     [2]int64
+		wantEqual bool
+	}{
+		{name: "same seed", seeds: [2]int64{42, 42}, wantEqual: true},
+		{name: "different seeds", seeds: [2]int64{42, 43}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := render(tt.seeds[0]), render(tt.seeds[1])
+			if (first == second) != tt.wantEqual {
+				t.Errorf("outputs %q and %q, want equal = %v", first, second, tt.wantEqual)
+			}
+			alnum, numeric, _ := strings.Cut(first, " ")
+			if len(alnum) != 16 || strings.Trim(alnum, alphaNumChars) != "" {
+				t.Errorf("randAlphaNum 16 = %q", alnum)
+			}
+			if len(numeric) != 6 || strings.Trim(numeric, numericChars) != "" {
+				t.Errorf("randNumeric 6 = %q", numeric)
+			}
+		})
+	}
+}
+
+func TestCollapseBlankLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no blank lines", input: "a\nb\n", want: "a\nb\n"},
+		{name: "run collapsed", input: "a\n\n\n\nb\n", want: "a\n\nb\n"},
+		{name: "whitespace-only lines", input: "a\n  \n\t\nb", want: "a\n\nb"},
+		{name: "trailing run", input: "a\n\n\n", want: "a\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(collapseBlankLines([]byte(tt.input))); got != tt.want {
+				t.Errorf("collapseBlankLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateStripBlankLines(t *testing.T) {
+	const content = "a\n{{ if .X }}x\n{{ end }}\n{{ if .Y }}y\n{{ end }}\n\nb\n"
+
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: strip
+files:
+  - source: stripped.txt.tmpl
+    type: file
+    stripBlankLines: true
+  - source: kept.txt.tmpl
+    type: file
+`,
+		"stripped.txt.tmpl": content,
+		"kept.txt.tmpl":     content,
+	})
+	generator := newTestGenerator(t, manager)
+
+	output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"})
+	if err != nil {
+		t.Fatalf("GenerateFS() error = %v", err)
+	}
+	for path, want := range map[string]string{
+		"stripped.txt": "a\n\nb\n",
+		"kept.txt":     "a\n\n\n\nb\n",
+	} {
+		data, err := fs.ReadFile(output, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", path, data, want)
+		}
+	}
+}
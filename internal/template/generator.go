@@ -2,72 +2,507 @@ package template
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
+
+	"aaa-generator/internal/console"
 )
 
 type Generator struct {
 	manager *Manager
+
+	// NoSymlinks 將模板中的符號連結展開為實際檔案，而非在輸出中重建連結
+	NoSymlinks bool
+	// Count 產生完成後輸出建立/略過檔案與執行命令的數量摘要
+	Count bool
+	// Values 由命令列指定的變數值，優先於 template.yaml 中的預設值
+	Values map[string]string
+	// Force 允許在既有的專案目錄中重新產生（覆寫模板產生的檔案）
+	Force bool
+	// Prune 重新產生時刪除先前由模板產生、但這次不再產生的檔案
+	Prune bool
+	// QuietPost 暫存 post-generate 命令的輸出，只在命令失敗時顯示
+	QuietPost bool
+	// NameTemplate 與 ModuleTemplate 為專案與模組名稱的命名樣式，見 ResolveProjectName
+	NameTemplate   string
+	ModuleTemplate string
+	// Confirm 在需要使用者確認的危險操作前呼叫，回傳 false 時中止；nil 視為拒絕
+	Confirm func(prompt string) bool
+	// Format 產生後以 gofmt/prettier 格式化輸出的檔案，與模板的 format: true 相同
+	Format bool
+	// StripGitkeep 只建立含 .gitkeep 的目錄而不複製 .gitkeep，與模板的 stripGitkeep: true 相同
+	StripGitkeep bool
+	// GenReadme 在模板沒有提供 README.md 時，以專案名稱、模板、變數與 nextSteps 產生一份，與模板的 generateReadme: true 相同
+	GenReadme bool
+	// Validate 產生後執行模板的 validate 命令，與模板的 alwaysValidate: true 相同
+	Validate bool
+	// PrintVars 在收集變數後、產生檔案前輸出最終的變數值與來源（secret 變數以遮罩顯示）
+	PrintVars bool
+	// Trace 若設定，寫入每個變數、檔案規則與命令的決策記錄（JSON Lines）
+	Trace io.Writer
+	// Status 為進度訊息與 post-generate 命令輸出的目的地，nil 時寫到 os.Stdout；
+	// 呼叫端可改寫到 stderr，讓 stdout 只留下機器可讀的結果（例如 --path-only）
+	Status *console.Printer
+	// Progress 在終端機上即時顯示 post-generate 命令的經過時間（搭配 QuietPost）
+	Progress bool
+	// AllowDeprecated 允許使用標記為 deprecated 的模板產生專案
+	AllowDeprecated bool
+	// Input 與 Output 為變數提示的輸入與輸出，nil 時使用 os.Stdin / Status；
+	// 可用於測試或讓 GUI 包裝程式提供答案。Input 為 *bufio.Reader 時直接使用，可與呼叫端的其他提示共用
+	Input  io.Reader
+	Output io.Writer
+	// NoInput 不讀取標準輸入：缺少必要變數或變數值不合法時直接回傳錯誤
+	NoInput bool
+	// RepromptInvalid 在 --set 等提供的變數值驗證失敗時，改為重新詢問該變數而非中止（用於終端機）
+	RepromptInvalid bool
+	// CheckModule 對 Go 模板另外檢查模組名稱是否已出現在本機的模組快取中（可能與已發佈的模組衝突）
+	CheckModule bool
+	// Strict 將 Go 模組/套件名稱與 post-generate 命令缺少工具的警告視為錯誤
+	Strict bool
+	// OutputDir 若設定，專案建立在此目錄之下，而非目前工作目錄
+	OutputDir string
+	// WorkDir 若設定，相對的專案目錄與 OutputDir 以此為基準解析，而非目前工作目錄（例如 -C）
+	WorkDir string
+	// FileMode 與 DirMode 若非 0，覆寫模板設定的檔案/目錄權限
+	FileMode fs.FileMode
+	DirMode  fs.FileMode
+	// Replace 對所有複製的非 .tmpl 文字檔進行字面取代（值可使用模板變數），見 FileRule.Replace
+	Replace map[string]string
+	// Version 為目前 generator 的版本，用於檢查模板的 minGeneratorVersion；空白或 "dev" 時不檢查
+	Version string
+	// Exclude 為產生時略過的 glob 樣式（比對來源或目標路徑），獨立於模板的檔案規則
+	Exclude []string
+	// IncludeOnly 若設定，只產生來源或目標路徑符合這些 glob 樣式的檔案（在 Exclude 之前套用）
+	IncludeOnly []string
+	// Seed 隨機模板函式（randAlphaNum 等）的種子；0 表示以時間為種子
+	Seed int64
+
+	rng      *rand.Rand
+	fileMode fs.FileMode
+	// planning 在 Plan / DryRun 期間為 true：不啟動任何程序（optionsFrom、--check-module 的 go env），見 Plan
+	planning bool
+	// header 與 headerConfig 為這次產生要加在檔案開頭的標頭（已渲染）與其設定，見 prependHeader
+	header       string
+	headerConfig *TemplateConfig
+	varSources   map[string]string
+}
+
+// GenerateStats 記錄一次產生過程中的檔案與命令數量
+type GenerateStats struct {
+	FilesCreated  int
+	SkippedByRule int
+	// SkippedByCondition 為規則 condition 不成立而略過的檔案數，整個略過的目錄計為一個
+	SkippedByCondition int
+	// Excluded 為被 Generator.IncludeOnly 或 Exclude 略過的檔案數
+	Excluded    int
+	CommandsRun int
+
+	files []string
+	// appended 為 append 規則寫入的目標；這些檔案可能在產生前就已存在，--prune 不會刪除
+	appended []string
+	// kinds 記錄每個產生檔案的種類（template、copy、symlink、readme），見 GeneratedFile
+	kinds map[string]string
+}
+
+// GenerateResult 為 Generate 成功後的結果
+type GenerateResult struct {
+	ProjectName string
+	// ProjectDir 為專案實際所在的目錄（設定 OutputDir 時包含其路徑）
+	ProjectDir string
+	Stats      GenerateStats
+	// Message 為模板 postMessage 渲染後的內容
+	Message string
+	// NextSteps 為模板 nextSteps 渲染後的內容；模板未宣告時為空
+	NextSteps []string
+	// Template 與 Version 為使用的模板名稱與版本；Files 與 Commands 為產生的檔案與執行的 post-generate 命令，
+	// 供 WriteSummary 輸出
+	Template string
+	Version  string
+	Files    []GeneratedFile
+	Commands []CommandResult
+	// Deprecated 與 DeprecationMessage 取自模板設定（需搭配 AllowDeprecated 才會產生）
+	Deprecated         bool
+	DeprecationMessage string
+	// Variables 為 PrintVars 時解析出的變數（secret 已遮罩）；未設定 PrintVars 時為 nil
+	Variables map[string]ResolvedVariable
+}
+
+func (s *GenerateStats) addFile(path, kind string) {
+	s.FilesCreated++
+	s.files = append(s.files, path)
+	if s.kinds == nil {
+		s.kinds = make(map[string]string)
+	}
+	s.kinds[path] = kind
+}
+
+func (s GenerateStats) String() string {
+	summary := fmt.Sprintf("%d files created, %d skipped (rules), %d skipped (conditions)",
+		s.FilesCreated, s.SkippedByRule, s.SkippedByCondition)
+	if s.Excluded > 0 {
+		summary += fmt.Sprintf(", %d excluded", s.Excluded)
+	}
+	return fmt.Sprintf("%s, %d commands run", summary, s.CommandsRun)
 }
 
 func NewGenerator(manager *Manager) *Generator {
 	return &Generator{manager: manager}
 }
 
-func (g *Generator) Generate(projectName, templateName string) error {
-	if _, err := os.Stat(projectName); err == nil {
-		return fmt.Errorf("directory '%s' already exists", projectName)
+// projectDir 回傳專案實際寫入的目錄：OutputDir 之下的 projectName，相對路徑再以 WorkDir 為基準；
+// 兩者皆未設定時與 projectName 相同
+func (g *Generator) projectDir(projectName string) string {
+	dir := projectName
+	if g.OutputDir != "" {
+		dir = filepath.Join(g.OutputDir, projectName)
+	}
+	return resolveIn(g.WorkDir, dir)
+}
+
+// permissions 決定產生檔案與目錄的權限：Generator 的設定優先於 template.yaml，最後為預設值
+func (g *Generator) permissions(config *TemplateConfig) (fs.FileMode, fs.FileMode, error) {
+	fileMode, dirMode := defaultFileMode, defaultDirMode
+
+	if config != nil && config.FileMode != "" {
+		mode, err := ParseFileMode(config.FileMode)
+		if err != nil {
+			return 0, 0, fmt.Errorf("fileMode: %w", err)
+		}
+		fileMode = mode
+	}
+	if config != nil && config.DirMode != "" {
+		mode, err := ParseFileMode(config.DirMode)
+		if err != nil {
+			return 0, 0, fmt.Errorf("dirMode: %w", err)
+		}
+		dirMode = mode
+	}
+
+	if g.FileMode != 0 {
+		fileMode = g.FileMode
+	}
+	if g.DirMode != 0 {
+		dirMode = g.DirMode
+	}
+	return fileMode, dirMode, nil
+}
+
+// checkDeprecated 對已淘汰的模板顯示警告，未設定 AllowDeprecated 時拒絕產生
+func (g *Generator) checkDeprecated(tmpl *Template, templateName string) error {
+	if tmpl.Config == nil || !tmpl.Config.Deprecated {
+		return nil
+	}
+
+	g.status().Printf(console.Warning("⚠️  Template '%s' is deprecated\n"), templateName)
+	if tmpl.Config.DeprecationMessage != "" {
+		g.status().Printf("   %s\n", tmpl.Config.DeprecationMessage)
+	}
+
+	if !g.AllowDeprecated {
+		return newDetailError(ErrTemplateDeprecated, "template '%s' is deprecated (use --allow-deprecated to generate anyway)", templateName)
+	}
+	return nil
+}
+
+// checkRequiredEnv 在產生任何檔案前確認模板 requiredEnv 宣告的環境變數皆已設定
+func checkRequiredEnv(tmpl *Template, templateName string) error {
+	if tmpl.Config == nil {
+		return nil
+	}
+
+	var missing []string
+	for _, name := range tmpl.Config.RequiredEnv {
+		if strings.TrimSpace(os.Getenv(name)) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return newDetailError(ErrMissingEnv, "template '%s' needs environment variables that are not set: %s", templateName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ResolveProjectName 套用 NameTemplate 取得專案名稱，並以 ModuleTemplate 設定 ModuleName。
+// 未設定命名樣式時直接回傳 name。
+func (g *Generator) ResolveProjectName(name string) (string, error) {
+	if g.ModuleTemplate != "" {
+		module, err := RenderName(g.ModuleTemplate, name)
+		if err != nil {
+			return "", err
+		}
+		if g.Values == nil {
+			g.Values = make(map[string]string)
+		}
+		g.Values["ModuleName"] = module
+	}
+
+	if g.NameTemplate == "" {
+		return name, nil
+	}
+	return RenderName(g.NameTemplate, name)
+}
+
+// Generate 產生專案並執行 post-generate 命令。命令失敗時其餘命令照常執行，專案仍算產生完成：
+// 回傳的 result 不為 nil，錯誤則包裝每個失敗命令的 PostCommandError（errors.Is(err, ErrPostCommandFailed) 成立）
+func (g *Generator) Generate(projectName, templateName string) (*GenerateResult, error) {
+	projectDir := g.projectDir(projectName)
+
+	if err := g.checkProjectDir(projectDir); err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(projectDir); err == nil {
+		if !g.Force {
+			return nil, newDetailError(ErrDirExists, "directory '%s' already exists", projectDir)
+		}
 	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to check project directory: %w", err)
+		return nil, fmt.Errorf("failed to check project directory: %w", err)
 	}
 
 	tmpl, err := g.manager.GetTemplate(templateName)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := g.checkGeneratorVersion(tmpl, templateName); err != nil {
+		return nil, err
+	}
+	if err := g.checkDeprecated(tmpl, templateName); err != nil {
+		return nil, err
+	}
+	if err := checkRequiredEnv(tmpl, templateName); err != nil {
+		return nil, err
 	}
 
-	vars, err := g.collectVariables(tmpl.Config, projectName)
+	config, err := withEnvDefaults(tmpl)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	vars, err := g.collectVariables(config, projectName)
+	if err != nil {
+		return nil, err
+	}
+	var resolved map[string]ResolvedVariable
+	if g.PrintVars {
+		resolved = g.resolvedVariables(config, vars)
+		printVariables(g.status(), resolved)
+	}
+
+	if err := g.checkGoNames(config, vars); err != nil {
+		return nil, err
+	}
+	if err := g.checkPostCommandTools(config, vars); err != nil {
+		return nil, err
+	}
+
+	fileMode, dirMode, err := g.permissions(tmpl.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	g.status().Println("🔄 Creating project directory...")
+	if err := os.MkdirAll(projectDir, dirMode); err != nil {
+		return nil, fmt.Errorf("failed to create project directory: %w", err)
 	}
+	g.status().Println(console.Success("✅ Project directory created"))
 
-	fmt.Println("🔄 Creating project directory...")
-	if err := os.MkdirAll(projectName, 0o755); err != nil {
-		return fmt.Errorf("failed to create project directory: %w", err)
+	g.status().Println("🔄 Generating project files...")
+	sink := NewDiskSink(projectDir)
+	sink.DirMode = dirMode
+	g.fileMode = fileMode
+	stats, err := g.generateFiles(tmpl, sink, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate files: %w", err)
+	}
+	if g.shouldGenerateReadme(tmpl.Config) {
+		if err := g.generateReadme(sink, projectDir, tmpl, vars, &stats); err != nil {
+			return nil, err
+		}
+	}
+	g.status().Println(console.Success("✅ Project files generated"))
+
+	if g.shouldFormat(tmpl.Config) {
+		g.status().Println("🔄 Formatting generated files...")
+		g.formatFiles(projectDir, stats.files)
+	}
+
+	manifest := newManifest(tmpl, vars, stats.files, stats.appended)
+	if g.Prune {
+		if err := g.pruneFiles(projectDir, manifest); err != nil {
+			return nil, fmt.Errorf("failed to prune files: %w", err)
+		}
+	}
+	if err := manifest.save(projectDir); err != nil {
+		return nil, fmt.Errorf("failed to save manifest: %w", err)
+	}
+
+	// 提供專案路徑給 post-generate 命令使用，例如 `code {{ .ProjectPath }}`
+	projectPath, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path: %w", err)
+	}
+	vars["ProjectPath"] = projectPath
+	vars["ProjectDir"] = projectDir
+
+	if err := g.runValidation(tmpl.Config, projectDir, vars, validateBefore); err != nil {
+		return nil, err
+	}
+
+	g.status().Println("🔄 Running post-generation commands...")
+	commands, postErr := g.runPostCommands(tmpl.Config, projectDir, vars)
+	stats.CommandsRun = len(commands)
+	if postErr != nil {
+		postErr = fmt.Errorf("post-generate commands failed: %w", postErr)
+		g.status().Println(console.Warning("⚠️  Post-generation commands finished with errors"))
+	} else {
+		g.status().Println(console.Success("✅ Post-generation commands completed"))
 	}
-	fmt.Println("✅ Project directory created")
 
-	fmt.Println("🔄 Generating project files...")
-	if err := g.generateFiles(tmpl, projectName, vars); err != nil {
-		return fmt.Errorf("failed to generate files: %w", err)
+	if err := g.runValidation(tmpl.Config, projectDir, vars, validateAfter); err != nil {
+		return nil, err
 	}
-	fmt.Println("✅ Project files generated")
 
-	fmt.Println("🔄 Running post-generation commands...")
-	if err := g.runPostCommands(tmpl.Config, projectName, vars); err != nil {
-		return fmt.Errorf("failed to run post commands: %w", err)
+	if g.Count {
+		g.status().Printf("📊 %s\n", stats)
 	}
-	fmt.Println("✅ Post-generation commands completed")
 
+	result := &GenerateResult{ProjectName: projectName, ProjectDir: projectDir, Stats: stats, Files: stats.generatedFiles(), Commands: commands, Variables: resolved}
+	if tmpl.Config != nil {
+		result.Template = tmpl.Config.Name
+		result.Version = tmpl.Config.Version
+		result.Deprecated = tmpl.Config.Deprecated
+		result.DeprecationMessage = tmpl.Config.DeprecationMessage
+	}
+	if tmpl.Config != nil && strings.TrimSpace(tmpl.Config.PostMessage) != "" {
+		message, err := renderText(tmpl.Config.PostMessage, vars)
+		if err != nil {
+			g.status().Printf(console.Warning("   ⚠️  Warning: %v\n"), err)
+			message = tmpl.Config.PostMessage
+		}
+		result.Message = strings.TrimRight(message, "\n")
+	}
+	if tmpl.Config != nil {
+		for _, step := range tmpl.Config.NextSteps {
+			rendered, err := renderText(step, vars)
+			if err != nil {
+				g.status().Printf(console.Warning("   ⚠️  Warning: %v\n"), err)
+				rendered = step
+			}
+			result.NextSteps = append(result.NextSteps, rendered)
+		}
+	}
+
+	return result, postErr
+}
+
+// checkGoNames 對 Go 模板檢查 ModuleName 與推導出的套件名稱；預設只顯示警告，Strict 時拒絕產生
+func (g *Generator) checkGoNames(config *TemplateConfig, vars map[string]interface{}) error {
+	if !isGoTemplate(config) {
+		return nil
+	}
+
+	moduleName := fmt.Sprintf("%v", vars["ModuleName"])
+	if normalized := NormalizeModulePath(moduleName); normalized != moduleName {
+		g.status().Printf(console.Warning("⚠️  Normalized module name '%s' to '%s'\n"), moduleName, normalized)
+		moduleName = normalized
+		vars["ModuleName"] = normalized
+	}
+
+	problems := CheckGoNames(moduleName)
+	if g.CheckModule && !g.planning && len(problems) == 0 {
+		if cached, cache := ModuleInCache(moduleName); cached {
+			problems = append(problems, fmt.Sprintf("module '%s' already exists in the local module cache (%s); it may collide with a published module", moduleName, cache))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	if g.Strict {
+		return newDetailError(ErrInvalidGoName, "invalid Go names: %s", strings.Join(problems, "; "))
+	}
+	for _, problem := range problems {
+		g.status().Printf(console.Warning("⚠️  %s\n"), problem)
+	}
 	return nil
 }
 
+// checkProjectDir 拒絕指向目前工作目錄或其上層目錄的專案路徑（例如 "." 或 ".."），
+// 避免覆寫正在使用的專案；搭配 Force 並經使用者確認後才允許。
+func (g *Generator) checkProjectDir(projectName string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	target, err := filepath.Abs(projectName)
+	if err != nil {
+		return fmt.Errorf("failed to resolve project path: %w", err)
+	}
+	if real, err := filepath.EvalSymlinks(target); err == nil {
+		target = real
+	}
+	if real, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = real
+	}
+
+	if !isWithinDir(target, cwd) {
+		return nil
+	}
+
+	if g.Force && g.Confirm != nil && g.Confirm(fmt.Sprintf("Generate into %s, which contains the current directory?", target)) {
+		return nil
+	}
+	return newDetailError(ErrUnsafeProjectDir, "refusing to generate into '%s': it is the current directory or one of its parents (use --force to override)", projectName)
+}
+
 func (g *Generator) collectVariables(config *TemplateConfig, projectName string) (map[string]interface{}, error) {
+	g.varSources = make(map[string]string)
 	vars := map[string]interface{}{
 		"ProjectName": projectName,
 		"ModuleName":  projectName,
 	}
 
+	// ProjectName 固定為專案目錄名稱，其餘內建變數可被覆寫
+	g.traceVariable("ProjectName", projectName, "builtin", false)
+	if value, ok := g.Values["ModuleName"]; ok {
+		vars["ModuleName"] = value
+		g.traceVariable("ModuleName", value, "set", false)
+	} else {
+		g.traceVariable("ModuleName", projectName, "builtin", false)
+	}
+
 	if config == nil {
+		g.addExtraValues(vars)
 		return vars, nil
 	}
 
-	reader := bufio.NewReader(os.Stdin)
+	// dataCommand 的值優先於預設值、低於 --set：未宣告的鍵保留 JSON 型別直接提供給模板，已宣告的變數以其取代預設值
+	data, err := g.templateData(config, vars)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range data {
+		_, declared := findVariable(config, name)
+		_, set := g.Values[name]
+		_, exists := vars[name]
+		if !declared && !set && !exists {
+			vars[name] = value
+			g.traceVariable(name, value, "data", false)
+		}
+	}
+
+	reader := bufio.NewReader(g.promptInput())
+	out := g.promptOutput()
 
 	for _, variable := range config.Variables {
 		if _, exists := vars[variable.Name]; exists {
@@ -75,79 +510,372 @@ func (g *Generator) collectVariables(config *TemplateConfig, projectName string)
 		}
 
 		value := variable.Default
+		source := "default"
+		if fromData, ok := data[variable.Name]; ok {
+			value = fmt.Sprint(fromData)
+			source = "data"
+		}
+		if override, ok := g.Values[variable.Name]; ok {
+			value = override
+			source = "set"
+		}
+		value, err := renderValue(variable, value, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		// optionsFile 的允許清單在產生時讀取，NoInput 時同樣用來驗證提供的值
+		if variable.Type == "select" && variable.OptionsFile != "" {
+			if variable.Options, err = g.fileOptions(variable, vars); err != nil {
+				return nil, err
+			}
+		}
+
+		// optionsFrom 的選項在產生時執行命令取得，提供的值（包括 NoInput 時）同樣以其驗證；
+		// dry-run 不啟動程序，只要求直接提供值而不驗證
+		if variable.Type == "select" && variable.OptionsFrom != "" {
+			switch {
+			case g.planning:
+				if strings.TrimSpace(value) == "" {
+					return nil, newDetailError(ErrInvalidVariable, "variable '%s' lists its options with a command, which --dry-run doesn't run (set it with --set %s=...)", variable.Name, variable.Name)
+				}
+			case g.NoInput && strings.TrimSpace(value) == "":
+				return nil, newDetailError(ErrInvalidVariable, "variable '%s' lists its options with a command and --no-input can't prompt for it (set it with --set %s=...)", variable.Name, variable.Name)
+			default:
+				if variable.Options, err = g.dynamicOptions(variable, vars); err != nil {
+					return nil, err
+				}
+			}
+		}
 
-		if variable.Required && strings.TrimSpace(value) == "" {
-			var err error
+		required, err := isRequired(variable, vars)
+		if err != nil {
+			return nil, err
+		}
+		if required && strings.TrimSpace(value) == "" {
+			if g.NoInput {
+				return nil, newDetailError(ErrInvalidVariable, "variable '%s' is required (set it with --set %s=...)", variable.Name, variable.Name)
+			}
 			value, err = g.promptForVariable(reader, variable)
 			if err != nil {
 				return nil, err
 			}
+			source = "prompt"
 		}
 
-		if variable.Type == "select" && len(variable.Options) > 0 {
-			if !contains(variable.Options, value) {
-				return nil, fmt.Errorf("invalid value '%s' for variable '%s'. valid options: %v", value, variable.Name, variable.Options)
+		for variable.Type == "select" && len(variable.Options) > 0 && !contains(variable.Options, value) {
+			invalid := &VariableError{Name: variable.Name, Value: value, Options: variable.Options}
+			if g.NoInput || !g.RepromptInvalid {
+				return nil, invalid
 			}
+			// 只重新詢問不合法的變數，其餘已提供的值保持不變
+			fmt.Fprintf(out, console.Warning("⚠️  %v\n"), invalid)
+			value, err = g.promptForVariable(reader, variable)
+			if err != nil {
+				return nil, err
+			}
+			source = "prompt"
+		}
+
+		value, err = transformValue(variable, value)
+		if err != nil {
+			return nil, err
 		}
 
 		vars[variable.Name] = value
+		g.traceVariable(variable.Name, value, source, variable.Secret)
 	}
 
+	g.addExtraValues(vars)
 	return vars, nil
 }
 
+// addExtraValues 將未在 template.yaml 宣告的命令列變數一併提供給模板
+func (g *Generator) addExtraValues(vars map[string]interface{}) {
+	for name, value := range g.Values {
+		if _, exists := vars[name]; !exists {
+			vars[name] = value
+			g.traceVariable(name, value, "set", false)
+		}
+	}
+}
+
+// promptInput 與 promptOutput 回傳變數提示使用的輸入與輸出
+func (g *Generator) promptInput() io.Reader {
+	if g.Input != nil {
+		return g.Input
+	}
+	return os.Stdin
+}
+
+func (g *Generator) promptOutput() io.Writer {
+	if g.Output != nil {
+		return g.Output
+	}
+	return g.status()
+}
+
+// status 回傳狀態訊息的 Printer；未設定 Status 時寫到目前的 os.Stdout
+func (g *Generator) status() *console.Printer {
+	if g.Status != nil {
+		return g.Status
+	}
+	return console.NewPrinter(os.Stdout)
+}
+
 func (g *Generator) promptForVariable(reader *bufio.Reader, variable TemplateVar) (string, error) {
+	out := g.promptOutput()
 	for {
-		fmt.Printf("Enter %s", variable.Name)
+		fmt.Fprintf(out, "Enter %s", variable.Name)
 		if variable.Description != "" {
-			fmt.Printf(" (%s)", variable.Description)
+			fmt.Fprintf(out, " (%s)", variable.Description)
+		}
+		if variable.Type == "select" && len(variable.Options) > 0 {
+			fmt.Fprintf(out, " [%s]", strings.Join(variable.Options, "/"))
 		}
-		fmt.Print(": ")
+		fmt.Fprint(out, ": ")
 
 		input, err := reader.ReadString('\n')
-		if err != nil {
+		// 最後一行沒有換行時仍使用讀到的內容
+		if err != nil && !(errors.Is(err, io.EOF) && input != "") {
 			return "", err
 		}
 		value := strings.TrimSpace(input)
 		if value == "" {
-			fmt.Println("Value cannot be empty. Please try again.")
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintln(out, "Value cannot be empty. Please try again.")
 			continue
 		}
 		return value, nil
 	}
 }
 
-func (g *Generator) generateFiles(tmpl *Template, projectName string, vars map[string]interface{}) error {
+// GenerateFS 將模板渲染為記憶體中的檔案系統，不寫入磁碟也不執行 post-generate 命令。
+// vars 中未提供的變數會使用 template.yaml 的預設值，缺少必要變數時回傳 ErrInvalidVariable。
+func (g *Generator) GenerateFS(templateName string, vars map[string]interface{}) (fs.FS, error) {
+	out := NewMemorySink()
+	if _, err := g.GenerateTo(templateName, vars, out); err != nil {
+		return nil, err
+	}
+
+	return out.FS(), nil
+}
+
+// GenerateTo 將模板渲染到指定的 Sink，不執行 post-generate 命令。
+// vars 的處理方式與 GenerateFS 相同。
+func (g *Generator) GenerateTo(templateName string, vars map[string]interface{}, out Sink) (GenerateStats, error) {
+	tmpl, err := g.manager.GetTemplate(templateName)
+	if err != nil {
+		return GenerateStats{}, err
+	}
+	if err := g.checkGeneratorVersion(tmpl, templateName); err != nil {
+		return GenerateStats{}, err
+	}
+
+	config, err := withEnvDefaults(tmpl)
+	if err != nil {
+		return GenerateStats{}, err
+	}
+
+	resolved, err := defaultVariables(config, vars)
+	if err != nil {
+		return GenerateStats{}, err
+	}
+
+	if g.fileMode, _, err = g.permissions(tmpl.Config); err != nil {
+		return GenerateStats{}, err
+	}
+
+	stats, err := g.generateFiles(tmpl, out, resolved)
+	if err != nil {
+		return stats, fmt.Errorf("failed to generate files: %w", err)
+	}
+	return stats, nil
+}
+
+// defaultVariables 以 template.yaml 的預設值補齊 vars，不進行互動提示
+func defaultVariables(config *TemplateConfig, vars map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(vars))
+	for name, value := range vars {
+		result[name] = value
+	}
+
+	if config == nil {
+		return result, nil
+	}
+
+	for _, variable := range config.Variables {
+		value, exists := result[variable.Name]
+		if !exists {
+			required, err := isRequired(variable, result)
+			if err != nil {
+				return nil, err
+			}
+			if required && strings.TrimSpace(variable.Default) == "" {
+				return nil, newDetailError(ErrInvalidVariable, "variable '%s' is required", variable.Name)
+			}
+			if value, err = renderValue(variable, variable.Default, result); err != nil {
+				return nil, err
+			}
+			result[variable.Name] = value
+		}
+
+		if variable.Type == "select" && len(variable.Options) > 0 {
+			if str := fmt.Sprint(value); !contains(variable.Options, str) {
+				return nil, &VariableError{Name: variable.Name, Value: str, Options: variable.Options}
+			}
+		}
+
+		if variable.Transform != "" {
+			transformed, err := transformValue(variable, fmt.Sprint(value))
+			if err != nil {
+				return nil, err
+			}
+			result[variable.Name] = transformed
+		}
+	}
+
+	return result, nil
+}
+
+// renderValue 以已收集的變數渲染預設值或 --set 值中的 {{ }}，例如 "{{ .ProjectName }}-db"。
+// 變數依宣告順序收集，因此只能引用先前的變數；引用尚未收集的變數時回傳錯誤
+func renderValue(variable TemplateVar, value string, vars map[string]interface{}) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+
+	tmpl, err := template.New("value").Option("missingkey=error").Funcs(templateFuncs()).Parse(value)
+	if err != nil {
+		return "", newDetailError(ErrInvalidVariable, "invalid value for variable '%s': %v", variable.Name, err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", newDetailError(ErrInvalidVariable, "failed to render value of variable '%s' (only earlier variables can be referenced): %v", variable.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// transformValue 以變數的 transform 表達式處理收集到的值，表達式中的 . 為原始值
+func transformValue(variable TemplateVar, value string) (string, error) {
+	if variable.Transform == "" {
+		return value, nil
+	}
+
+	tmpl, err := template.New("transform").Funcs(templateFuncs()).Parse(variable.Transform)
+	if err != nil {
+		return "", newDetailError(ErrInvalidVariable, "invalid transform for variable '%s': %v", variable.Name, err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, value); err != nil {
+		return "", newDetailError(ErrInvalidVariable, "failed to transform variable '%s': %v", variable.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// seedRandom 每次產生都重新建立亂數來源，相同的 seed 會得到相同的輸出
+func (g *Generator) seedRandom() {
+	seed := g.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	g.rng = rand.New(rand.NewSource(seed))
+}
+
+func (g *Generator) generateFiles(tmpl *Template, out Sink, vars map[string]interface{}) (GenerateStats, error) {
 	useRules := tmpl.Config != nil && len(tmpl.Config.Files) > 0
+	var stats GenerateStats
 
-	return fs.WalkDir(tmpl.Files, ".", func(path string, d fs.DirEntry, err error) error {
+	g.seedRandom()
+
+	header, err := renderHeader(tmpl.Config, vars)
+	if err != nil {
+		return stats, err
+	}
+	g.header, g.headerConfig = header, tmpl.Config
+
+	var rules, disabled []FileRule
+	if useRules {
+		var err error
+		if rules, disabled, err = g.splitRulesByCondition(tmpl.Config.Files, vars); err != nil {
+			return stats, err
+		}
+	}
+
+	err = fs.WalkDir(tmpl.Files, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 		if path == "." {
 			return nil
 		}
-		if path == "template.yaml" {
+		if isTemplateMetaFile(tmpl.Config, path) {
 			return nil
 		}
 
-		relativePath := path
+		targetPath := path
 		matched := false
+		var rule *FileRule
 		if useRules {
-			if mapped, ok := mapTargetPath(tmpl.Config.Files, path); ok {
-				relativePath = mapped
+			if mapped, matchedRule, ok := mapTargetPath(rules, path); ok {
+				targetPath = mapped
+				rule = matchedRule
 				matched = true
 			}
 		}
 		if useRules && !matched {
+			if _, _, ok := mapTargetPath(disabled, path); ok {
+				// 條件不成立的規則：目錄下沒有其他規則時整個略過，不逐一檢查其中的檔案
+				g.trace("rule", map[string]interface{}{"path": path, "matched": false, "condition": false})
+				if d.IsDir() && !rulesBelow(rules, path) {
+					stats.SkippedByCondition++
+					return fs.SkipDir
+				}
+				if !d.IsDir() {
+					stats.SkippedByCondition++
+				}
+				return nil
+			}
+			g.trace("rule", map[string]interface{}{"path": path, "matched": false})
+			if !d.IsDir() {
+				stats.SkippedByRule++
+			}
 			return nil
 		}
+		if useRules {
+			g.trace("rule", map[string]interface{}{"path": path, "matched": true, "target": targetPath})
+		}
+		if targetPath == "" {
+			targetPath = "."
+		}
 
-		relativePath = filepath.FromSlash(relativePath)
-		targetPath := filepath.Join(projectName, relativePath)
+		rendered, renderErr := renderPath(targetPath, vars)
+		if renderErr != nil {
+			return renderErr
+		}
+		targetPath = rendered
+
+		if g.excluded(path, targetPath) {
+			g.trace("exclude", map[string]interface{}{"path": path, "target": targetPath})
+			if !d.IsDir() {
+				stats.Excluded++
+			}
+			return nil
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			return g.generateSymlink(tmpl, out, path, targetPath, vars, &stats)
+		}
 
 		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0o755)
+			return out.Mkdir(targetPath)
+		}
+		if isGitkeep(path) && g.shouldStripGitkeep(tmpl.Config) {
+			return g.keepDirectory(out, path, targetPath)
 		}
 
 		content, readErr := fs.ReadFile(tmpl.Files, path)
@@ -155,67 +883,371 @@ func (g *Generator) generateFiles(tmpl *Template, projectName string, vars map[s
 			return readErr
 		}
 
-		if strings.HasSuffix(path, ".tmpl") {
-			targetPath = strings.TrimSuffix(targetPath, ".tmpl")
-			return g.processTemplate(content, targetPath, vars)
+		return g.writeFile(out, path, content, targetPath, vars, rule, &stats)
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	// 有規則卻沒有任何檔案符合，幾乎都是 template.yaml 撰寫錯誤
+	if useRules && stats.FilesCreated == 0 && stats.Excluded == 0 && stats.SkippedByCondition == 0 {
+		return stats, newDetailError(ErrNoMatchingFiles, "template '%s': no files matched its file rules (%d file(s) skipped)", tmpl.Config.Name, stats.SkippedByRule)
+	}
+
+	if tmpl.Config != nil {
+		for _, include := range tmpl.Config.Includes {
+			if err := g.generateInclude(include, out, vars, &stats); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// generateInclude 從另一個模板引入檔案；source 為目錄時引入整個目錄
+func (g *Generator) generateInclude(include IncludeRule, out Sink, vars map[string]interface{}, stats *GenerateStats) error {
+	other, err := g.manager.GetTemplate(include.Template)
+	if err != nil {
+		return fmt.Errorf("include from %s: %w", include.Template, err)
+	}
+
+	source := strings.Trim(strings.TrimPrefix(strings.TrimSpace(include.Source), "./"), "/")
+	if source == "" {
+		source = "."
+	}
+	if _, err := fs.Stat(other.Files, source); err != nil {
+		return fmt.Errorf("include %s from template '%s': file not found", include.Source, include.Template)
+	}
+
+	target := strings.TrimSpace(include.Target)
+	if target == "" {
+		target = source
+	}
+	target = strings.Trim(strings.TrimPrefix(target, "./"), "/")
+	if target == "" {
+		target = "."
+	}
+
+	return fs.WalkDir(other.Files, source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if isTemplateMetaFile(other.Config, path) {
+			return nil
+		}
+
+		targetPath := target
+		if path != source {
+			targetPath = joinRuleTarget(target, strings.TrimPrefix(path, source+"/"))
+		}
+		targetPath, err = renderPath(targetPath, vars)
+		if err != nil {
+			return err
+		}
+
+		if g.excluded(path, targetPath) {
+			if !d.IsDir() {
+				stats.Excluded++
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			return out.Mkdir(targetPath)
+		}
+		if isGitkeep(path) && g.shouldStripGitkeep(other.Config) {
+			return g.keepDirectory(out, path, targetPath)
 		}
 
-		return os.WriteFile(targetPath, content, 0o644)
+		content, err := fs.ReadFile(other.Files, path)
+		if err != nil {
+			return err
+		}
+		return g.writeFile(out, path, content, targetPath, vars, nil, stats)
 	})
 }
 
-func (g *Generator) processTemplate(content []byte, targetPath string, vars map[string]interface{}) error {
-	tmpl, err := template.New("template").Parse(string(content))
+// writeFile 渲染（.tmpl）並寫入單一檔案；rule 為符合的檔案規則，沒有規則時為 nil
+func (g *Generator) writeFile(out Sink, path string, content []byte, targetPath string, vars map[string]interface{}, rule *FileRule, stats *GenerateStats) error {
+	isTemplate := strings.HasSuffix(path, ".tmpl")
+	if isTemplate {
+		targetPath = strings.TrimSuffix(targetPath, ".tmpl")
+	}
+	switch {
+	case isTemplate && looksBinary(content):
+		// 二進位檔案（例如誤命名為 logo.png.tmpl）經過模板引擎會損毀，改為原樣複製
+		g.status().Printf(console.Warning("   ⚠️  Warning: %s looks binary, copying it without rendering\n"), path)
+		isTemplate = false
+	case isTemplate:
+		rendered, err := g.processTemplate(content, targetPath, vars)
+		if err != nil {
+			return err
+		}
+		if rule != nil && rule.StripBlankLines {
+			rendered = collapseBlankLines(rendered)
+		}
+		content = rendered
+	default:
+		if replace := g.replacements(rule); len(replace) > 0 && !looksBinary(content) {
+			replaced, err := applyReplacements(content, replace, vars)
+			if err != nil {
+				return fmt.Errorf("failed to apply replacements to %s: %w", path, err)
+			}
+			content = replaced
+		}
+	}
+
+	if rule != nil && rule.Append {
+		if reader, ok := out.(ReadableSink); ok {
+			if existing, err := reader.ReadFile(targetPath); err == nil {
+				content = appendContent(existing, content, rule.DedupLines)
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to read %s for appending: %w", targetPath, err)
+			}
+		}
+	}
+
+	if g.header != "" && wantsHeader(g.headerConfig, targetPath) && !looksBinary(content) {
+		content = prependHeader(content, g.header, targetPath)
+	}
+
+	mode := g.fileMode
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	if err := out.WriteFile(targetPath, content, mode); err != nil {
+		return err
+	}
+	g.trace("write", map[string]interface{}{"source": path, "target": targetPath, "template": isTemplate})
+
+	kind := "copy"
+	if isTemplate {
+		kind = "template"
+	}
+	stats.addFile(targetPath, kind)
+	if rule != nil && rule.Append {
+		stats.appended = append(stats.appended, targetPath)
+	}
+	return nil
+}
+
+// replacements 合併命令列的 Replace 與檔案規則的 replace，規則中的同名鍵優先
+func (g *Generator) replacements(rule *FileRule) map[string]string {
+	if rule == nil || len(rule.Replace) == 0 {
+		return g.Replace
+	}
+	if len(g.Replace) == 0 {
+		return rule.Replace
+	}
+
+	merged := make(map[string]string, len(g.Replace)+len(rule.Replace))
+	for key, value := range g.Replace {
+		merged[key] = value
+	}
+	for key, value := range rule.Replace {
+		merged[key] = value
+	}
+	return merged
+}
+
+// generateSymlink 在輸出中重建模板內的符號連結（保留相對路徑），
+// 或在 NoSymlinks（或 Sink 不支援連結）時將連結目標展開為一般檔案/目錄。
+func (g *Generator) generateSymlink(tmpl *Template, out Sink, path, targetPath string, vars map[string]interface{}, stats *GenerateStats) error {
+	if tmpl.LocalPath == "" {
+		return fmt.Errorf("symlink %s is not supported for this template source", path)
+	}
+
+	linkPath := filepath.Join(tmpl.LocalPath, filepath.FromSlash(path))
+	target, resolved, err := resolveSymlink(tmpl.LocalPath, linkPath)
 	if err != nil {
-		return fmt.Errorf("failed to parse template %s: %w", targetPath, err)
+		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(targetPath), 0o755); err != nil {
+	if linker, ok := out.(SymlinkSink); ok && !g.NoSymlinks {
+		if strings.HasSuffix(targetPath, ".tmpl") && strings.HasSuffix(target, ".tmpl") {
+			targetPath = strings.TrimSuffix(targetPath, ".tmpl")
+			target = strings.TrimSuffix(target, ".tmpl")
+		}
+		if err := linker.Symlink(target, targetPath); err != nil {
+			return err
+		}
+		stats.addFile(targetPath, "symlink")
+		return nil
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
 		return err
 	}
 
-	file, err := os.Create(targetPath)
+	if !info.IsDir() {
+		content, err := os.ReadFile(resolved)
+		if err != nil {
+			return err
+		}
+		return g.writeFile(out, path, content, targetPath, vars, nil, stats)
+	}
+
+	if err := checkSymlinkCycle(linkPath, resolved); err != nil {
+		return err
+	}
+
+	rootReal, err := filepath.EvalSymlinks(tmpl.LocalPath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	resolvedRel, err := filepath.Rel(rootReal, resolved)
+	if err != nil {
+		return err
+	}
+	resolvedRel = filepath.ToSlash(resolvedRel)
+
+	return fs.WalkDir(os.DirFS(resolved), ".", func(sub string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		subTarget, subPath := targetPath, resolvedRel
+		if sub != "." {
+			subTarget = targetPath + "/" + sub
+			subPath = resolvedRel + "/" + sub
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			return g.generateSymlink(tmpl, out, subPath, subTarget, vars, stats)
+		}
+
+		if d.IsDir() {
+			return out.Mkdir(subTarget)
+		}
 
-	if err := tmpl.Execute(file, vars); err != nil {
-		return fmt.Errorf("failed to execute template %s: %w", targetPath, err)
+		content, err := os.ReadFile(filepath.Join(resolved, filepath.FromSlash(sub)))
+		if err != nil {
+			return err
+		}
+		return g.writeFile(out, sub, content, subTarget, vars, nil, stats)
+	})
+}
+
+func (g *Generator) processTemplate(content []byte, targetPath string, vars map[string]interface{}) ([]byte, error) {
+	tmpl := template.New("template").Funcs(templateFuncs()).Funcs(randomFuncs(g.rng))
+	tmpl, err := bindInclude(tmpl).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", targetPath, err)
 	}
 
-	return nil
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", targetPath, err)
+	}
+
+	return buf.Bytes(), nil
 }
 
-func (g *Generator) runPostCommands(config *TemplateConfig, projectName string, vars map[string]interface{}) error {
+// runPostCommands 依序執行 post-generate 命令；失敗的命令顯示警告後繼續執行其餘命令，
+// 最後以 errors.Join 回傳所有失敗命令的 PostCommandError
+func (g *Generator) runPostCommands(config *TemplateConfig, projectName string, vars map[string]interface{}) ([]CommandResult, error) {
 	if config == nil || len(config.PostGenerate) == 0 {
-		return nil
+		return nil, nil
 	}
 
+	var commands []PostCommand
 	for _, command := range config.PostGenerate {
+		if !matchesOS(command.OS) {
+			g.trace("command", map[string]interface{}{"command": command.Command, "skipped": true, "os": command.OS})
+			continue
+		}
+		commands = append(commands, command)
+	}
+
+	var results []CommandResult
+	var failures []error
+	phase := ""
+	for i, command := range commands {
+		// 命令依宣告順序執行，階段只在標籤改變時顯示一次
+		if command.Phase != "" && command.Phase != phase {
+			g.status().Printf("   ▸ %s\n", command.Phase)
+		}
+		phase = command.Phase
+
 		cmdStr := g.processCommandTemplate(command.Command, vars)
 		workDir := filepath.Join(projectName, command.WorkDir)
 		if workDir == "" {
 			workDir = projectName
 		}
 
-		fmt.Printf("   • Running: %s\n", cmdStr)
-
 		cmd := exec.Command("sh", "-c", cmdStr)
 		cmd.Dir = workDir
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
 
-		if err := cmd.Run(); err != nil {
-			fmt.Printf("   ⚠️  Warning: command failed: %s\n", cmdStr)
+		label := fmt.Sprintf("   • [%d/%d] Running: %s", i+1, len(commands), cmdStr)
+		stopProgress := func() {}
+		var output bytes.Buffer
+		if g.QuietPost {
+			g.status().Print(label)
+			if g.Progress {
+				stopProgress = showElapsed(g.status(), label, time.Now())
+			}
+			cmd.Stdout = &output
+			cmd.Stderr = &output
+		} else {
+			g.status().Println(label)
+			cmd.Stdout = g.status().Writer()
+			cmd.Stderr = console.NewPrinter(os.Stderr).Writer()
 		}
+
+		started := time.Now()
+		err := cmd.Run()
+		stopProgress()
+		elapsed := time.Since(started).Round(100 * time.Millisecond)
+		g.trace("command", map[string]interface{}{"command": cmdStr, "phase": command.Phase, "workDir": workDir, "exitCode": cmd.ProcessState.ExitCode(), "success": err == nil, "elapsed": elapsed.String()})
+		if err != nil {
+			if g.QuietPost {
+				g.status().Println()
+				g.status().Write(output.Bytes())
+			}
+			cmdErr := &PostCommandError{Command: cmdStr, WorkDir: workDir, Err: err}
+			g.status().Printf(console.Warning("   ⚠️  Warning: %v\n"), cmdErr)
+			failures = append(failures, cmdErr)
+		} else if g.QuietPost {
+			g.status().Printf(console.Success(" ✅ (%s)\n"), elapsed)
+		} else {
+			g.status().Printf(console.Success("   ✅ Done in %s\n"), elapsed)
+		}
+		results = append(results, CommandResult{Command: cmdStr, WorkDir: workDir, ExitCode: cmd.ProcessState.ExitCode(), Duration: time.Since(started)})
 	}
 
-	return nil
+	return results, errors.Join(failures...)
+}
+
+// showElapsed 在終端機上持續更新 label 後的經過時間，回傳的函式會停止更新並還原為 label
+func showElapsed(out io.Writer, label string, started time.Time) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				fmt.Fprintf(out, "\r\033[K%s", label)
+				return
+			case <-ticker.C:
+				fmt.Fprintf(out, "\r\033[K%s (%s)", label, time.Since(started).Round(time.Second))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
 }
 
 func (g *Generator) processCommandTemplate(command string, vars map[string]interface{}) string {
-	tmpl, err := template.New("command").Parse(command)
+	tmpl, err := template.New("command").Funcs(templateFuncs()).Parse(command)
 	if err != nil {
 		return command
 	}
@@ -228,16 +1260,17 @@ func (g *Generator) processCommandTemplate(command string, vars map[string]inter
 	return buf.String()
 }
 
-func mapTargetPath(rules []FileRule, path string) (string, bool) {
+func mapTargetPath(rules []FileRule, path string) (string, *FileRule, bool) {
 	normalized := strings.TrimPrefix(path, "./")
 	normalized = strings.TrimPrefix(normalized, "/")
 	if normalized == "" {
-		return "", true
+		return "", nil, true
 	}
 
-	for _, rule := range rules {
+	for i := range rules {
+		rule := &rules[i]
 		src := strings.TrimSpace(rule.Source)
-		if src == "" {
+		if src == "" || !matchesOS(rule.OS) {
 			continue
 		}
 		src = strings.TrimPrefix(src, "./")
@@ -252,14 +1285,14 @@ func mapTargetPath(rules []FileRule, path string) (string, bool) {
 		case "directory":
 			src = strings.TrimSuffix(src, "/")
 			if src == "" {
-				return joinRuleTarget(rule.Target, normalized), true
+				return joinRuleTarget(rule.Target, normalized), rule, true
 			}
 			if normalized == src {
-				return joinRuleTarget(rule.Target, ""), true
+				return joinRuleTarget(rule.Target, ""), rule, true
 			}
 			if strings.HasPrefix(normalized, src+"/") {
 				rel := strings.TrimPrefix(normalized, src+"/")
-				return joinRuleTarget(rule.Target, rel), true
+				return joinRuleTarget(rule.Target, rel), rule, true
 			}
 		case "file":
 			src = strings.TrimSuffix(src, "/")
@@ -269,12 +1302,18 @@ func mapTargetPath(rules []FileRule, path string) (string, bool) {
 					rel = filepath.Base(src)
 				}
 				rel = strings.TrimPrefix(rel, "./")
-				return rel, true
+				return rel, rule, true
+			}
+		case "glob":
+			src = strings.TrimSuffix(src, "/")
+			if matchGlob(src, normalized) {
+				rel := strings.TrimPrefix(normalized, globBase(src))
+				return joinRuleTarget(rule.Target, rel), rule, true
 			}
 		}
 	}
 
-	return normalized, false
+	return normalized, nil, false
 }
 
 func joinRuleTarget(target, rel string) string {
@@ -0,0 +1,927 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCounts(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: counts
+variables:
+  - name: Docker
+    type: bool
+    default: "false"
+files:
+  - source: src
+    target: src
+  - source: Dockerfile.tmpl
+    type: file
+    condition: '{{ eq .Docker "true" }}'
+  - source: docker
+    target: docker
+    condition: '{{ eq .Docker "true" }}'
+postGenerate:
+  - command: "true"
+`,
+		"src/main.go":     "package main\n",
+		"src/util.go":     "package main\n",
+		"Dockerfile.tmpl": "FROM {{ .ProjectName }}\n",
+		"docker/a.yml":    "a\n",
+		"docker/b.yml":    "b\n",
+		"notes.txt":       "not matched by any rule\n",
+	}
+
+	tests := []struct {
+		name        string
+		values      map[string]string
+		includeOnly []string
+		exclude     []string
+		want        SummaryCounts
+	}{
+		{
+			name: "conditional files disabled",
+			// Dockerfile 與整個 docker 目錄各計為一個
+			want: SummaryCounts{Created: 2, SkippedByRule: 1, SkippedByCondition: 2, CommandsRun: 1},
+		},
+		{
+			name:   "conditional files enabled",
+			values: map[string]string{"Docker": "true"},
+			want:   SummaryCounts{Created: 5, SkippedByRule: 1, CommandsRun: 1},
+		},
+		{
+			name:    "excluded file",
+			exclude: []string{"src/util.go"},
+			want:    SummaryCounts{Created: 1, SkippedByRule: 1, SkippedByCondition: 2, Excluded: 1, CommandsRun: 1},
+		},
+		{
+			name:        "include-only subset",
+			values:      map[string]string{"Docker": "true"},
+			includeOnly: []string{"docker"},
+			want:        SummaryCounts{Created: 2, SkippedByRule: 1, Excluded: 3, CommandsRun: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.Values = tt.values
+			generator.IncludeOnly = tt.includeOnly
+			generator.Exclude = tt.exclude
+
+			result, err := generator.Generate("app", name)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			summary, err := result.Summary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if summary.Counts != tt.want {
+				t.Errorf("counts = %+v, want %+v", summary.Counts, tt.want)
+			}
+			if len(summary.Files) != tt.want.Created {
+				t.Errorf("summary lists %d files, want %d", len(summary.Files), tt.want.Created)
+			}
+		})
+	}
+}
+
+func TestGenerateDeprecated(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		allow       bool
+		wantErr     error
+		wantWarning bool
+	}{
+		{
+			name:   "not deprecated",
+			config: "name: current\n",
+		},
+		{
+			name:        "deprecated is rejected",
+			config:      "name: old\ndeprecated: true\ndeprecationMessage: use current instead\n",
+			wantErr:     ErrTemplateDeprecated,
+			wantWarning: true,
+		},
+		{
+			name:        "deprecated with AllowDeprecated",
+			config:      "name: old\ndeprecated: true\ndeprecationMessage: use current instead\n",
+			allow:       true,
+			wantWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": tt.config, "main.go": "package main\n"})
+			generator := newTestGenerator(t, manager)
+			generator.AllowDeprecated = tt.allow
+
+			var result *GenerateResult
+			var err error
+			output := captureStdout(t, func() { result, err = generator.Generate("app", name) })
+
+			if warned := strings.Contains(output, "is deprecated") && strings.Contains(output, "use current instead"); warned != tt.wantWarning {
+				t.Errorf("deprecation warning printed = %v, want %v\n%s", warned, tt.wantWarning, output)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want %v", err, tt.wantErr)
+				}
+				if _, statErr := os.Stat(filepath.Join(generator.WorkDir, "app")); !os.IsNotExist(statErr) {
+					t.Error("project directory was created for a rejected template")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			summary, err := result.Summary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(summary)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(data), `"deprecated":true`); got != tt.wantWarning {
+				t.Errorf("summary JSON deprecated = %v, want %v\n%s", got, tt.wantWarning, data)
+			}
+		})
+	}
+}
+
+func TestGeneratePostMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		want        string
+		wantWarning bool
+	}{
+		{name: "no message"},
+		{name: "rendered", message: "|\n  cd {{ .ProjectName }}\n  make run\n", want: "cd app\nmake run"},
+		{name: "helper functions", message: "'Welcome to {{ .ProjectName | upper }}'", want: "Welcome to APP"},
+		{name: "render error falls back", message: "'Run {{ nosuch }}'", want: "Run {{ nosuch }}", wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := "name: msg\n"
+			if tt.message != "" {
+				config += "postMessage: " + tt.message
+			}
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": config, "main.go": "package main\n"})
+			generator := newTestGenerator(t, manager)
+
+			var result *GenerateResult
+			var err error
+			output := captureStdout(t, func() { result, err = generator.Generate("app", name) })
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if result.Message != tt.want {
+				t.Errorf("Message = %q, want %q", result.Message, tt.want)
+			}
+			if warned := strings.Contains(output, "Warning"); warned != tt.wantWarning {
+				t.Errorf("warning printed = %v, want %v\n%s", warned, tt.wantWarning, output)
+			}
+		})
+	}
+}
+
+func TestVariableTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform string
+		value     string
+		want      string
+		wantErr   string
+	}{
+		{name: "no transform", value: " Acme ", want: " Acme "},
+		{name: "trim and lower", transform: "{{ . | trimSpace | lower }}", value: "  Acme Corp ", want: "acme corp"},
+		{name: "case helper", transform: "{{ . | kebabCase }}", value: "AcmeCorp", want: "acme-corp"},
+		{name: "parse error", transform: "{{ . | ", value: "x", wantErr: "invalid transform for variable 'Org'"},
+		{name: "execution error", transform: "{{ .Field }}", value: "x", wantErr: "failed to transform variable 'Org'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := "name: transform\nvariables:\n  - name: Org\n    default: placeholder\n"
+			if tt.transform != "" {
+				config += "    transform: " + quoteYAML(tt.transform) + "\n"
+			}
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": config, "org.txt.tmpl": "{{ .Org }}"})
+			generator := newTestGenerator(t, manager)
+			generator.Values = map[string]string{"Org": tt.value}
+
+			_, err := generator.Generate("app", name)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidVariable) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(generator.WorkDir, "app", "org.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Org = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostCommandProjectPath(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: paths
+postGenerate:
+  - command: echo "{{ .ProjectPath }}|{{ .ProjectDir }}" > paths.txt
+    workDir: sub
+`,
+		"sub/main.go": "package main\n",
+	})
+	generator := newTestGenerator(t, manager)
+	generator.OutputDir = "out"
+
+	var result *GenerateResult
+	var err error
+	captureStdout(t, func() { result, err = generator.Generate("app", name) })
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(result.ProjectDir, "sub", "paths.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(generator.WorkDir, "out", "app")
+	if got := strings.TrimSpace(string(data)); got != want+"|"+result.ProjectDir {
+		t.Errorf("paths.txt = %q, want ProjectPath %s", got, want)
+	}
+}
+
+func TestQuietPost(t *testing.T) {
+	const config = `name: noisy
+postGenerate:
+  - command: echo installing deps
+  - command: echo broken build; exit 2
+`
+
+	tests := []struct {
+		name    string
+		quiet   bool
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "output streamed",
+			want: []string{"installing deps", "broken build", "Done in"},
+		},
+		{
+			name:    "output only on failure",
+			quiet:   true,
+			want:    []string{"[1/2] Running: echo installing deps ✅", "broken build", "exit status 2"},
+			notWant: []string{"installing deps\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": config, "main.go": "package main\n"})
+			generator := newTestGenerator(t, manager)
+			generator.QuietPost = tt.quiet
+
+			var err error
+			output := captureStdout(t, func() { _, err = generator.Generate("app", name) })
+			var cmdErr *PostCommandError
+			if !errors.As(err, &cmdErr) || !strings.Contains(cmdErr.Command, "exit 2") {
+				t.Fatalf("Generate() error = %v, want a PostCommandError for the failing command", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output should contain %q, got:\n%s", want, output)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(output, notWant) {
+					t.Errorf("output should not contain %q, got:\n%s", notWant, output)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateInclude(t *testing.T) {
+	shared := map[string]string{
+		"template.yaml":        "name: shared\n",
+		"ci/build.yml.tmpl":    "name: build {{ .ProjectName }}\n",
+		"ci/lint.yml":          "name: lint\n",
+		"LICENSE":              "MIT\n",
+		"docs/CONTRIBUTING.md": "# contributing\n",
+	}
+
+	tests := []struct {
+		name    string
+		include string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:    "directory with target",
+			include: "  - template: shared\n    source: ./ci/\n    target: .github/workflows\n",
+			want:    map[string]string{".github/workflows/build.yml": "name: build app\n", ".github/workflows/lint.yml": "name: lint\n"},
+		},
+		{
+			name:    "single file keeps its path",
+			include: "  - template: shared\n    source: LICENSE\n",
+			want:    map[string]string{"LICENSE": "MIT\n"},
+		},
+		{
+			name:    "rendered target",
+			include: "  - template: shared\n    source: docs/CONTRIBUTING.md\n    target: '{{ .ProjectName }}-docs/CONTRIBUTING.md'\n",
+			want:    map[string]string{"app-docs/CONTRIBUTING.md": "# contributing\n"},
+		},
+		{
+			name:    "whole template skips its config",
+			include: "  - template: shared\n    source: .\n    target: vendor/shared\n",
+			want:    map[string]string{"vendor/shared/LICENSE": "MIT\n", "vendor/shared/ci/build.yml": "name: build app\n"},
+		},
+		{name: "unknown template", include: "  - template: nope\n    source: LICENSE\n", wantErr: "include from nope"},
+		{name: "missing source", include: "  - template: shared\n    source: NOTICE\n", wantErr: "include NOTICE from template 'shared': file not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			installTestTemplate(t, manager, shared)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml": "name: app\ninclude:\n" + tt.include,
+				"main.go":       "package main\n",
+			})
+			generator := newTestGenerator(t, manager)
+
+			output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GenerateFS() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateFS() error = %v", err)
+			}
+			for path, want := range tt.want {
+				data, err := fs.ReadFile(output, path)
+				if err != nil || string(data) != want {
+					t.Errorf("%s = %q, %v; want %q", path, data, err, want)
+				}
+			}
+			if _, err := fs.Stat(output, "vendor/shared/template.yaml"); err == nil {
+				t.Error("the included template's config was copied")
+			}
+		})
+	}
+}
+
+func TestCheckProjectDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		force   bool
+		// answer 為 Confirm 的回覆；空白表示未設定 Confirm
+		answer  string
+		wantErr bool
+	}{
+		{name: "subdirectory", project: "app"},
+		{name: "current directory", project: ".", wantErr: true},
+		{name: "parent directory", project: "..", wantErr: true},
+		{name: "symlink to the current directory", project: "here", wantErr: true},
+		{name: "force without confirmation", project: ".", force: true, wantErr: true},
+		{name: "force declined", project: ".", force: true, answer: "no", wantErr: true},
+		{name: "force confirmed", project: ".", force: true, answer: "yes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cwd := filepath.Join(t.TempDir(), "work")
+			if err := os.Mkdir(cwd, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Symlink(".", filepath.Join(cwd, "here")); err != nil {
+				t.Fatal(err)
+			}
+			t.Chdir(cwd)
+
+			generator := NewGenerator(nil)
+			generator.WorkDir = cwd
+			generator.Force = tt.force
+			if tt.answer != "" {
+				generator.Confirm = func(string) bool { return tt.answer == "yes" }
+			}
+
+			err := generator.checkProjectDir(generator.projectDir(tt.project))
+			if tt.wantErr && !errors.Is(err, ErrUnsafeProjectDir) {
+				t.Fatalf("checkProjectDir(%q) error = %v, want ErrUnsafeProjectDir", tt.project, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("checkProjectDir(%q) error = %v", tt.project, err)
+			}
+		})
+	}
+}
+
+func TestGenerateNextSteps(t *testing.T) {
+	tests := []struct {
+		name        string
+		steps       string
+		want        []string
+		wantWarning bool
+	}{
+		{name: "none declared"},
+		{name: "rendered", steps: "nextSteps:\n  - cd {{ .ProjectName }}\n  - make run PORT={{ .Port }}\n", want: []string{"cd app", "make run PORT=8080"}},
+		{name: "render error falls back", steps: "nextSteps:\n  - 'go run {{ nosuch }}'\n", want: []string{"go run {{ nosuch }}"}, wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			config := "name: steps\nvariables:\n  - name: Port\n    default: \"8080\"\n" + tt.steps
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": config, "main.go": "package main\n"})
+			generator := newTestGenerator(t, manager)
+
+			var result *GenerateResult
+			var err error
+			output := captureStdout(t, func() { result, err = generator.Generate("app", name) })
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if strings.Join(result.NextSteps, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("NextSteps = %q, want %q", result.NextSteps, tt.want)
+			}
+			if warned := strings.Contains(output, "Warning:"); warned != tt.wantWarning {
+				t.Errorf("warning printed = %v, want %v\n%s", warned, tt.wantWarning, output)
+			}
+		})
+	}
+}
+
+func TestGenerateNoMatchingFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		values  map[string]string
+		exclude []string
+		wantErr bool
+	}{
+		{name: "no rules copies everything", rules: ""},
+		{name: "rule matches", rules: "files:\n  - source: src\n    target: src\n"},
+		{name: "typo in source", rules: "files:\n  - source: scr\n    target: src\n", wantErr: true},
+		// 條件不成立或被排除而沒有檔案時是使用者的選擇，不算錯誤
+		{
+			name:   "condition disabled",
+			rules:  "variables:\n  - name: Src\n    type: bool\n    default: \"false\"\nfiles:\n  - source: src\n    target: src\n    condition: '{{ eq .Src \"true\" }}'\n",
+			values: map[string]string{"Src": "false"},
+		},
+		{name: "excluded", rules: "files:\n  - source: src\n    target: src\n", exclude: []string{"src"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml": "name: rules\n" + tt.rules,
+				"src/main.go":   "package main\n",
+			})
+			generator := newTestGenerator(t, manager)
+			generator.Values = tt.values
+			generator.Exclude = tt.exclude
+
+			_, err := generator.Generate("app", name)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoMatchingFiles) {
+					t.Fatalf("Generate() error = %v, want ErrNoMatchingFiles", err)
+				}
+				if !strings.Contains(err.Error(), "1 file(s) skipped") {
+					t.Errorf("error %q does not report the skipped files", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPostCommandProgress(t *testing.T) {
+	const config = `name: slow
+postGenerate:
+  - command: echo first
+  - command: echo elsewhere
+    os: [plan9]
+  - command: sleep 0.5
+`
+
+	tests := []struct {
+		name     string
+		quiet    bool
+		progress bool
+		want     []string
+		notWant  []string
+	}{
+		{
+			// 不符合目前 OS 的命令不計入總數
+			name:    "step counter",
+			want:    []string{"[1/2] Running: echo first\n", "[2/2] Running: sleep 0.5\n", "Done in"},
+			notWant: []string{"elsewhere", "\r"},
+		},
+		{
+			name:    "quiet without progress",
+			quiet:   true,
+			want:    []string{"[2/2] Running: sleep 0.5 ✅ ("},
+			notWant: []string{"\r"},
+		},
+		{
+			name:     "quiet with progress",
+			quiet:    true,
+			progress: true,
+			want:     []string{"\r\033[K   • [2/2] Running: sleep 0.5 (", "\r\033[K   • [2/2] Running: sleep 0.5 ✅ ("},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": config, "main.go": "package main\n"})
+			generator := newTestGenerator(t, manager)
+			generator.QuietPost = tt.quiet
+			generator.Progress = tt.progress
+
+			var err error
+			output := captureStdout(t, func() { _, err = generator.Generate("app", name) })
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output should contain %q, got:\n%q", want, output)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(output, notWant) {
+					t.Errorf("output should not contain %q, got:\n%q", notWant, output)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateOutputDir(t *testing.T) {
+	absolute := t.TempDir()
+
+	tests := []struct {
+		name      string
+		outputDir string
+		// want 為 ProjectDir 相對於 WorkDir 的路徑；absolute 為 true 時直接比對
+		want     string
+		absolute bool
+	}{
+		{name: "working directory", want: "app"},
+		{name: "relative output dir", outputDir: "out", want: filepath.Join("out", "app")},
+		{name: "absolute output dir", outputDir: absolute, want: filepath.Join(absolute, "app"), absolute: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": "name: out\n", "main.go": "package main\n"})
+			generator := newTestGenerator(t, manager)
+			generator.OutputDir = tt.outputDir
+
+			result, err := generator.Generate("app", name)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			want := tt.want
+			if !tt.absolute {
+				want = filepath.Join(generator.WorkDir, tt.want)
+			}
+			if result.ProjectDir != want || result.ProjectName != "app" {
+				t.Errorf("result = %q in %q, want app in %q", result.ProjectName, result.ProjectDir, want)
+			}
+			if _, err := os.Stat(filepath.Join(want, "main.go")); err != nil {
+				t.Errorf("main.go was not generated in %s: %v", want, err)
+			}
+		})
+	}
+}
+
+func TestGenerateBinaryTemplate(t *testing.T) {
+	// 內容含有 {{ 與非 UTF-8 位元組，若經過模板引擎會解析失敗或損毀
+	binary := "\x89PNG\r\n\x1a\n\x00{{ broken \xff"
+
+	tests := []struct {
+		name     string
+		source   string
+		content  string
+		target   string
+		want     string
+		wantWarn bool
+	}{
+		{name: "binary .tmpl copied verbatim", source: "logo.png.tmpl", content: binary, target: "logo.png", want: binary, wantWarn: true},
+		{name: "text .tmpl rendered", source: "README.md.tmpl", content: "# {{ .ProjectName }}\n", target: "README.md", want: "# app\n"},
+		{name: "binary without .tmpl", source: "logo.png", content: binary, target: "logo.png", want: binary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": "name: assets\n", tt.source: tt.content})
+			generator := newTestGenerator(t, manager)
+
+			var err error
+			output := captureStdout(t, func() { _, err = generator.Generate("app", name) })
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(generator.WorkDir, "app", tt.target))
+			if err != nil || string(data) != tt.want {
+				t.Errorf("%s = %q, %v; want %q", tt.target, data, err, tt.want)
+			}
+			if warned := strings.Contains(output, tt.source+" looks binary"); warned != tt.wantWarn {
+				t.Errorf("binary warning = %v, want %v, output:\n%s", warned, tt.wantWarn, output)
+			}
+		})
+	}
+}
+
+func TestRepromptInvalid(t *testing.T) {
+	const config = `name: langs
+variables:
+  - name: Lang
+    type: select
+    options: [go, rust]
+  - name: Owner
+    required: true
+files:
+  - source: README.md.tmpl
+    type: file
+`
+
+	tests := []struct {
+		name     string
+		values   map[string]string
+		noInput  bool
+		reprompt bool
+		input    string
+		want     string
+		wantErr  bool
+		// wantPrompts 為 Lang 被詢問的次數
+		wantPrompts int
+	}{
+		{name: "invalid value fails", values: map[string]string{"Lang": "python", "Owner": "me"}, wantErr: true},
+		{
+			// 第一個回答仍不合法時繼續詢問，Owner 保持 --set 的值
+			name:     "invalid value re-prompted",
+			values:   map[string]string{"Lang": "python", "Owner": "me"},
+			reprompt: true, input: "java\nrust\n",
+			want: "rust by me\n", wantPrompts: 2,
+		},
+		{name: "no input wins over reprompt", values: map[string]string{"Lang": "python", "Owner": "me"}, noInput: true, reprompt: true, wantErr: true},
+		{name: "missing required without input", values: map[string]string{"Lang": "go"}, noInput: true, wantErr: true},
+		{name: "missing required prompted", values: map[string]string{"Lang": "go"}, input: "you\n", want: "go by you\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml":  config,
+				"README.md.tmpl": "{{ .Lang }} by {{ .Owner }}\n",
+			})
+			generator := newTestGenerator(t, manager)
+			generator.Values = tt.values
+			generator.NoInput = tt.noInput
+			generator.RepromptInvalid = tt.reprompt
+			generator.Input = strings.NewReader(tt.input)
+			var prompts strings.Builder
+			generator.Output = &prompts
+
+			_, err := generator.Generate("app", name)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidVariable) {
+					t.Fatalf("Generate() error = %v, want ErrInvalidVariable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(generator.WorkDir, "app", "README.md"))
+			if err != nil || string(data) != tt.want {
+				t.Errorf("README.md = %q, %v; want %q", data, err, tt.want)
+			}
+			if got := strings.Count(prompts.String(), "Enter Lang [go/rust]: "); got != tt.wantPrompts {
+				t.Errorf("Lang prompted %d times, want %d:\n%s", got, tt.wantPrompts, prompts.String())
+			}
+		})
+	}
+}
+
+func TestVariableReferences(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables string
+		values    map[string]string
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "default uses earlier variable",
+			variables: "  - name: Owner\n    default: me\n  - name: DB\n    default: '{{ .ProjectName }}-{{ .Owner }}-db'\n",
+			want:      "app-me-db",
+		},
+		{
+			name:      "--set value uses earlier variable",
+			variables: "  - name: Owner\n    default: me\n  - name: DB\n    default: db\n",
+			values:    map[string]string{"DB": "{{ .Owner | upper }}_DB"},
+			want:      "ME_DB",
+		},
+		{
+			// Owner 在 DB 之後才宣告，收集 DB 時尚未有值
+			name:      "forward reference",
+			variables: "  - name: DB\n    default: '{{ .Owner }}-db'\n  - name: Owner\n    default: me\n",
+			wantErr:   "only earlier variables can be referenced",
+		},
+		{
+			name:      "invalid template",
+			variables: "  - name: DB\n    default: '{{ .Owner'\n",
+			wantErr:   "invalid value for variable 'DB'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml": "name: refs\nvariables:\n" + tt.variables + "files:\n  - source: db.txt.tmpl\n    type: file\n",
+				"db.txt.tmpl":   "{{ .DB }}",
+			})
+
+			// Generate 收集變數時渲染預設值與 --set 值；GenerateFS 只補齊預設值，同樣要渲染
+			modes := []string{"Generate", "GenerateFS"}
+			if tt.values != nil {
+				modes = modes[:1]
+			}
+			for _, mode := range modes {
+				generator := newTestGenerator(t, manager)
+				generator.Values = tt.values
+				var data []byte
+				var err error
+				if mode == "Generate" {
+					if _, err = generator.Generate("app", name); err == nil {
+						data, err = os.ReadFile(filepath.Join(generator.WorkDir, "app", "db.txt"))
+					}
+				} else {
+					var output fs.FS
+					if output, err = generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"}); err == nil {
+						data, err = fs.ReadFile(output, "db.txt")
+					}
+				}
+
+				if tt.wantErr != "" {
+					if !errors.Is(err, ErrInvalidVariable) || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Errorf("%s() error = %v, want ErrInvalidVariable with %q", mode, err, tt.wantErr)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s() error = %v", mode, err)
+				}
+				if string(data) != tt.want {
+					t.Errorf("%s() db.txt = %q, want %q", mode, data, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRequiredEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		missing string
+	}{
+		{name: "all set", env: map[string]string{"AAA_TEST_TOKEN": "t", "AAA_TEST_ORG": "acme"}},
+		{name: "one unset", env: map[string]string{"AAA_TEST_TOKEN": "t"}, missing: "AAA_TEST_ORG"},
+		{name: "blank counts as unset", env: map[string]string{"AAA_TEST_TOKEN": " ", "AAA_TEST_ORG": "acme"}, missing: "AAA_TEST_TOKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"AAA_TEST_TOKEN", "AAA_TEST_ORG"} {
+				t.Setenv(name, tt.env[name])
+			}
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml": "name: needs-env\nrequiredEnv: [AAA_TEST_TOKEN, AAA_TEST_ORG]\n",
+				"main.go":       "package main\n",
+			})
+			generator := newTestGenerator(t, manager)
+
+			_, dryErr := generator.DryRun("app", name)
+			_, err := generator.Generate("app", name)
+			if tt.missing == "" {
+				if dryErr != nil || err != nil {
+					t.Fatalf("DryRun() error = %v, Generate() error = %v", dryErr, err)
+				}
+				return
+			}
+			for stage, err := range map[string]error{"DryRun": dryErr, "Generate": err} {
+				if !errors.Is(err, ErrMissingEnv) || !strings.Contains(err.Error(), "not set: "+tt.missing) {
+					t.Errorf("%s() error = %v, want ErrMissingEnv for %s", stage, err, tt.missing)
+				}
+			}
+			// 在建立任何檔案之前就失敗
+			if _, err := os.Stat(filepath.Join(generator.WorkDir, "app")); !os.IsNotExist(err) {
+				t.Errorf("project directory was created: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostCommandPhases(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands string
+		// want 為依序顯示的階段標題
+		want []string
+	}{
+		{name: "no phases", commands: "  - command: echo a\n  - command: echo b\n"},
+		{
+			name:     "consecutive commands share a header",
+			commands: "  - command: echo a\n    phase: Backend\n  - command: echo b\n    phase: Backend\n  - command: echo c\n    phase: Frontend\n",
+			want:     []string{"Backend", "Frontend"},
+		},
+		{
+			// 階段不會重新排序命令，回到先前的標籤時再顯示一次
+			name:     "phase shown again after a change",
+			commands: "  - command: echo a\n    phase: Backend\n  - command: echo b\n  - command: echo c\n    phase: Backend\n  - command: echo d\n    phase: Frontend\n    os: [plan9]\n",
+			want:     []string{"Backend", "Backend"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml": "name: phased\npostGenerate:\n" + tt.commands,
+				"main.go":       "package main\n",
+			})
+			generator := newTestGenerator(t, manager)
+			var trace bytes.Buffer
+			generator.Trace = &trace
+
+			var err error
+			output := captureStdout(t, func() { _, err = generator.Generate("app", name) })
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			var got []string
+			for _, line := range strings.Split(output, "\n") {
+				if label, ok := strings.CutPrefix(line, "   ▸ "); ok {
+					got = append(got, label)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("phase headers = %q, want %q\n%s", got, tt.want, output)
+			}
+
+			for _, event := range traceEvents(t, trace.Bytes()) {
+				if event["event"] == "command" && event["skipped"] == nil && event["phase"] == nil {
+					t.Errorf("command trace has no phase field: %v", event)
+				}
+			}
+		})
+	}
+}
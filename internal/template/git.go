@@ -0,0 +1,71 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// commitRef 比對看起來像 commit SHA 的 ref；git clone --branch 不接受 commit，需改為 clone 後 checkout
+var commitRef = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// gitFetcher 以 git clone 下載模板，支援以 @ref 指定分支、tag 或 commit
+type gitFetcher struct {
+	// run 執行 git 命令，測試時可替換
+	run func(args ...string) error
+}
+
+func newGitFetcher() *gitFetcher {
+	return &gitFetcher{run: runGit}
+}
+
+func runGit(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// isGitSource 回傳來源是否為 git 儲存庫位址（http(s)、ssh:// 或 git@host:path）
+func isGitSource(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") ||
+		strings.HasPrefix(source, "ssh://") || strings.HasPrefix(source, "git@")
+}
+
+// parseGitSource 拆出來源結尾的 @ref，例如 "https://github.com/me/tpl@v2" → ("https://github.com/me/tpl", "v2")。
+// 只有最後一個 / 之後的 @ 才視為 ref，因此 git@host:path 與 https://user@host/path 中的 @ 不受影響
+func parseGitSource(source string) (url, ref string) {
+	at := strings.LastIndex(source, "@")
+	if at <= strings.LastIndexAny(source, "/:") || at == len(source)-1 {
+		return source, ""
+	}
+	return source[:at], source[at+1:]
+}
+
+func (f *gitFetcher) Fetch(source, dst string) error {
+	url, ref := parseGitSource(source)
+
+	switch {
+	case ref == "":
+		if err := f.run("clone", "--depth", "1", url, dst); err != nil {
+			return fmt.Errorf("git clone failed: %w", err)
+		}
+	case commitRef.MatchString(ref):
+		if err := f.run("clone", url, dst); err != nil {
+			return fmt.Errorf("git clone failed: %w", err)
+		}
+		if err := f.run("-C", dst, "checkout", "--quiet", ref); err != nil {
+			return fmt.Errorf("git checkout %s failed: %w", ref, err)
+		}
+	default:
+		if err := f.run("clone", "--depth", "1", "--branch", ref, url, dst); err != nil {
+			return fmt.Errorf("git clone of %s failed: %w", ref, err)
+		}
+	}
+
+	// 儲存庫的歷史不屬於模板內容
+	return os.RemoveAll(filepath.Join(dst, ".git"))
+}
@@ -0,0 +1,165 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantURL string
+		wantRef string
+	}{
+		{source: "https://github.com/me/tpl", wantURL: "https://github.com/me/tpl"},
+		{source: "https://github.com/me/tpl@v2", wantURL: "https://github.com/me/tpl", wantRef: "v2"},
+		{source: "https://user@github.com/me/tpl", wantURL: "https://user@github.com/me/tpl"},
+		{source: "https://user@github.com/me/tpl@main", wantURL: "https://user@github.com/me/tpl", wantRef: "main"},
+		{source: "git@github.com:me/tpl.git", wantURL: "git@github.com:me/tpl.git"},
+		{source: "git@github.com:me/tpl.git@v1.0", wantURL: "git@github.com:me/tpl.git", wantRef: "v1.0"},
+		{source: "https://github.com/me/tpl@", wantURL: "https://github.com/me/tpl@"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			url, ref := parseGitSource(tt.source)
+			if url != tt.wantURL || ref != tt.wantRef {
+				t.Errorf("parseGitSource() = (%q, %q), want (%q, %q)", url, ref, tt.wantURL, tt.wantRef)
+			}
+		})
+	}
+}
+
+// fakeGit 記錄 git 命令，clone 時在目標目錄寫入模板與 .git
+type fakeGit struct {
+	calls [][]string
+	fail  bool
+}
+
+func (f *fakeGit) run(args ...string) error {
+	f.calls = append(f.calls, args)
+	if f.fail {
+		return errors.New("exit status 128")
+	}
+	if args[0] == "clone" {
+		dst := args[len(args)-1]
+		if err := os.MkdirAll(filepath.Join(dst, ".git"), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, "template.yaml"), []byte("name: remote\n"), 0o644)
+	}
+	return nil
+}
+
+func TestGitFetcherFetch(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		fail      bool
+		wantCalls func(dst string) [][]string
+		wantErr   string
+	}{
+		{
+			name:   "default branch",
+			source: "https://example.com/me/tpl",
+			wantCalls: func(dst string) [][]string {
+				return [][]string{{"clone", "--depth", "1", "https://example.com/me/tpl", dst}}
+			},
+		},
+		{
+			name:   "branch or tag",
+			source: "https://example.com/me/tpl@v2",
+			wantCalls: func(dst string) [][]string {
+				return [][]string{{"clone", "--depth", "1", "--branch", "v2", "https://example.com/me/tpl", dst}}
+			},
+		},
+		{
+			name:   "commit",
+			source: "https://example.com/me/tpl@0123abc",
+			wantCalls: func(dst string) [][]string {
+				return [][]string{
+					{"clone", "https://example.com/me/tpl", dst},
+					{"-C", dst, "checkout", "--quiet", "0123abc"},
+				}
+			},
+		},
+		{
+			name:    "clone failure",
+			source:  "https://example.com/me/tpl@v2",
+			fail:    true,
+			wantErr: "git clone of v2 failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			git := &fakeGit{fail: tt.fail}
+			dst := filepath.Join(t.TempDir(), "clone")
+
+			err := (&gitFetcher{run: git.run}).Fetch(tt.source, dst)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Fetch() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if want := tt.wantCalls(dst); !reflect.DeepEqual(git.calls, want) {
+				t.Errorf("git calls = %q, want %q", git.calls, want)
+			}
+			if _, err := os.Stat(filepath.Join(dst, ".git")); !os.IsNotExist(err) {
+				t.Error(".git was not removed from the fetched template")
+			}
+		})
+	}
+}
+
+func TestInstallFromGitRecordsRef(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		wantSource string
+		wantRef    string
+	}{
+		{
+			name:       "without ref",
+			source:     "https://example.com/me/tpl",
+			wantSource: "https://example.com/me/tpl",
+		},
+		{
+			name:       "with ref",
+			source:     "https://example.com/me/tpl@v2",
+			wantSource: "https://example.com/me/tpl",
+			wantRef:    "v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			git := &fakeGit{}
+			manager.git = &gitFetcher{run: git.run}
+
+			result, err := manager.InstallTemplate(tt.source)
+			if err != nil {
+				t.Fatalf("InstallTemplate() error = %v", err)
+			}
+			info, err := LoadInstallInfo(result.Path)
+			if err != nil || info == nil {
+				t.Fatalf("LoadInstallInfo() = %v, %v", info, err)
+			}
+			if info.Source != tt.wantSource || info.Ref != tt.wantRef {
+				t.Errorf("install info = %+v, want source %q and ref %q", info, tt.wantSource, tt.wantRef)
+			}
+			if len(git.calls) != 1 {
+				t.Fatalf("git calls = %q, want one clone", git.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package template
+
+import (
+	"path"
+	"strings"
+)
+
+// gitkeepFileName 標記模板中刻意保留的空目錄（例如 logs/.gitkeep）
+const gitkeepFileName = ".gitkeep"
+
+func isGitkeep(name string) bool {
+	return path.Base(strings.TrimSuffix(name, ".tmpl")) == gitkeepFileName
+}
+
+// shouldStripGitkeep 回傳是否只建立 .gitkeep 所在的目錄而不複製 .gitkeep 本身
+func (g *Generator) shouldStripGitkeep(config *TemplateConfig) bool {
+	return g.StripGitkeep || (config != nil && config.StripGitkeep)
+}
+
+// keepDirectory 為 .gitkeep 建立其所在的目錄而不寫入檔案，讓空目錄仍出現在輸出中
+func (g *Generator) keepDirectory(out Sink, source, targetPath string) error {
+	dir := path.Dir(targetPath)
+	g.trace("gitkeep", map[string]interface{}{"source": source, "dir": dir})
+	return out.Mkdir(dir)
+}
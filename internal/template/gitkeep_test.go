@@ -0,0 +1,70 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStripGitkeep(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		flag        bool
+		wantGitkeep bool
+	}{
+		{name: "kept by default", config: "name: keep\n", wantGitkeep: true},
+		{name: "--strip-gitkeep", config: "name: keep\n", flag: true},
+		{name: "stripGitkeep: true", config: "name: keep\nstripGitkeep: true\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml":       tt.config,
+				"logs/.gitkeep":       "",
+				"data/cache/.gitkeep": "",
+				"main.go":             "package main\n",
+			})
+			generator := newTestGenerator(t, manager)
+			generator.StripGitkeep = tt.flag
+
+			if _, err := generator.Generate("app", name); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			projectDir := filepath.Join(generator.WorkDir, "app")
+			for _, dir := range []string{"logs", "data/cache"} {
+				info, err := os.Stat(filepath.Join(projectDir, dir))
+				if err != nil || !info.IsDir() {
+					t.Errorf("%s not created: %v", dir, err)
+				}
+				_, err = os.Stat(filepath.Join(projectDir, dir, ".gitkeep"))
+				if gotGitkeep := err == nil; gotGitkeep != tt.wantGitkeep {
+					t.Errorf("%s/.gitkeep exists = %v, want %v", dir, gotGitkeep, tt.wantGitkeep)
+				}
+			}
+		})
+	}
+}
+
+func TestIsGitkeep(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: ".gitkeep", want: true},
+		{name: "logs/.gitkeep", want: true},
+		{name: "logs/.gitkeep.tmpl", want: true},
+		{name: "logs/gitkeep"},
+		{name: ".gitkeep/file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isGitkeep(tt.name); got != tt.want {
+				t.Errorf("isGitkeep(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package template
+
+import (
+	"path"
+	"strings"
+)
+
+// matchGlob 比對以 / 分隔的路徑與 glob 樣式。
+// 每一段使用 path.Match 規則，"**" 則可比對零或多個路徑段。
+func matchGlob(pattern, name string) bool {
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchGlobSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// globBase 回傳樣式中第一個萬用字元之前的固定目錄前綴（含結尾 /）。
+// 符合的路徑會去除此前綴後放到 rule.Target 之下，以保留相對結構。
+func globBase(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	end := len(segments) - 1
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, "*?[") {
+			end = i
+			break
+		}
+	}
+	if end == 0 {
+		return ""
+	}
+	return strings.Join(segments[:end], "/") + "/"
+}
@@ -0,0 +1,93 @@
+package template
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "*.go", name: "main.go", want: true},
+		{pattern: "*.go", name: "cmd/main.go"},
+		{pattern: "**/*.go", name: "main.go", want: true},
+		{pattern: "**/*.go", name: "cmd/app/main.go", want: true},
+		{pattern: "src/**", name: "src", want: true},
+		{pattern: "src/**", name: "src/a/b.txt", want: true},
+		{pattern: "src/**/test/*.go", name: "src/test/a.go", want: true},
+		{pattern: "src/**/test/*.go", name: "src/x/y/test/a.go", want: true},
+		{pattern: "src/**/test/*.go", name: "src/x/test/y/a.go"},
+		{pattern: "config.?ml", name: "config.yml", want: true},
+		{pattern: "[", name: "["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlobBase(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "*.go", want: ""},
+		{pattern: "**/*.go", want: ""},
+		{pattern: "web/src/**/*.ts", want: "web/src/"},
+		{pattern: "assets/img?/*", want: "assets/"},
+		{pattern: "docs/README.md", want: "docs/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := globBase(tt.pattern); got != tt.want {
+				t.Errorf("globBase(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateGlobRule(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: glob
+files:
+  - source: "web/src/**/*.ts"
+    target: frontend
+    type: glob
+`,
+		"web/src/index.ts":             "index\n",
+		"web/src/components/button.ts": "button\n",
+		"web/src/styles.css":           "css\n",
+		"web/README.md":                "readme\n",
+	})
+	generator := newTestGenerator(t, manager)
+
+	output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"})
+	if err != nil {
+		t.Fatalf("GenerateFS() error = %v", err)
+	}
+	var files []string
+	err = fs.WalkDir(output, ".", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	if got, want := strings.Join(files, ","), "frontend/components/button.ts,frontend/index.ts"; got != want {
+		t.Errorf("generated files = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+package template
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// reservedModulePaths 為 go 命令保留的模組路徑或套件樣式，作為模組名稱會造成混淆或無法使用
+var reservedModulePaths = map[string]bool{
+	"go":        true,
+	"toolchain": true,
+	"all":       true,
+	"std":       true,
+	"cmd":       true,
+	"tools":     true,
+	"work":      true,
+}
+
+// majorVersionSuffix 比對模組路徑最後的主版本後綴，例如 "/v2"
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// modulePathChars 比對 go.mod 模組路徑允許的字元
+var modulePathChars = regexp.MustCompile(`^[A-Za-z0-9._~/-]+$`)
+
+// NormalizeModulePath 修正常見的模組路徑輸入錯誤：去除前後空白、https:// 等 scheme、結尾的 .git 與 /，
+// 例如 "https://github.com/me/app.git/" → "github.com/me/app"
+func NormalizeModulePath(modulePath string) string {
+	modulePath = strings.TrimSpace(modulePath)
+	if _, rest, ok := strings.Cut(modulePath, "://"); ok {
+		modulePath = rest
+	}
+	modulePath = strings.TrimRight(modulePath, "/")
+	return strings.TrimSuffix(modulePath, ".git")
+}
+
+// checkModulePath 回傳模組路徑中明顯不合法的形式：空白、空格、不允許的字元，或以 . / 開頭或結尾的路徑元素
+func checkModulePath(modulePath string) []string {
+	switch {
+	case modulePath == "":
+		return []string{"module name is empty"}
+	case strings.ContainsAny(modulePath, " \t\n"):
+		return []string{fmt.Sprintf("module name '%s' contains whitespace (try '%s')", modulePath, strings.Join(strings.Fields(modulePath), "-"))}
+	case !modulePathChars.MatchString(modulePath):
+		return []string{fmt.Sprintf("module name '%s' contains characters not allowed in module paths (letters, digits and ._~-/ only)", modulePath)}
+	}
+
+	for _, element := range strings.Split(modulePath, "/") {
+		if element == "" || strings.HasPrefix(element, ".") || strings.HasSuffix(element, ".") {
+			return []string{fmt.Sprintf("module name '%s' has an empty path element or one starting or ending with a dot", modulePath)}
+		}
+	}
+	return nil
+}
+
+// moduleCacheDir 回傳 Go 模組快取目錄：GOMODCACHE、go env GOMODCACHE，最後為 ~/go/pkg/mod
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if out, err := exec.Command("go", "env", "GOMODCACHE").Output(); err == nil {
+		if dir := strings.TrimSpace(string(out)); dir != "" {
+			return dir
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, "go", "pkg", "mod")
+	}
+	return ""
+}
+
+// escapeModulePath 依模組快取的規則編碼路徑：大寫字母寫成 ! 加小寫，例如 "github.com/BurntSushi" → "github.com/!burnt!sushi"
+func escapeModulePath(modulePath string) string {
+	var b strings.Builder
+	for _, r := range modulePath {
+		if unicode.IsUpper(r) {
+			b.WriteRune('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ModuleInCache 回傳模組路徑是否已出現在本機的模組快取中，表示可能與已發佈的模組衝突
+func ModuleInCache(modulePath string) (bool, string) {
+	cache := moduleCacheDir()
+	if cache == "" {
+		return false, ""
+	}
+	dir := filepath.Join(cache, "cache", "download", filepath.FromSlash(escapeModulePath(modulePath)), "@v")
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return true, cache
+	}
+	return false, cache
+}
+
+// isGoTemplate 回傳模板是否產生 Go 專案（以 tags 中的 "go" 判斷）
+func isGoTemplate(config *TemplateConfig) bool {
+	if config == nil {
+		return false
+	}
+	for _, tag := range config.Tags {
+		if strings.EqualFold(tag, "go") {
+			return true
+		}
+	}
+	return false
+}
+
+// derivedPackageName 回傳以模組路徑最後一段推導的套件名稱，略過 /vN 主版本後綴
+func derivedPackageName(modulePath string) string {
+	modulePath = strings.Trim(modulePath, "/")
+	name := path.Base(modulePath)
+	if majorVersionSuffix.MatchString(name) && path.Dir(modulePath) != "." {
+		name = path.Base(path.Dir(modulePath))
+	}
+	return name
+}
+
+// SanitizePackageName 將名稱轉為合法的 Go 套件名稱：只保留小寫字母、數字與底線，
+// 開頭為數字或結果為關鍵字時加上 "pkg"，例如 "my-app" → "myapp"、"func" → "funcpkg"
+func SanitizePackageName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+			b.WriteRune(r)
+		}
+	}
+
+	sanitized := b.String()
+	switch {
+	case sanitized == "":
+		return "app"
+	case unicode.IsDigit(rune(sanitized[0])):
+		return "pkg" + sanitized
+	case token.IsKeyword(sanitized):
+		return sanitized + "pkg"
+	}
+	return sanitized
+}
+
+// CheckGoNames 檢查模組名稱是否為明顯不合法的路徑、保留路徑，以及推導出的套件名稱是否為 Go 關鍵字或不合法的識別字，
+// 回傳每個問題的說明與建議的替代名稱；沒有問題時回傳 nil
+func CheckGoNames(moduleName string) []string {
+	if problems := checkModulePath(moduleName); len(problems) > 0 {
+		return problems
+	}
+	var problems []string
+
+	if reservedModulePaths[moduleName] {
+		problems = append(problems, fmt.Sprintf("module name '%s' is reserved by the go command (try '%s')",
+			moduleName, moduleName+"app"))
+	}
+
+	pkg := derivedPackageName(moduleName)
+	switch {
+	case token.IsKeyword(pkg):
+		problems = append(problems, fmt.Sprintf("package name '%s' derived from module '%s' is a Go keyword (try '%s')",
+			pkg, moduleName, SanitizePackageName(pkg)))
+	case !token.IsIdentifier(pkg):
+		problems = append(problems, fmt.Sprintf("package name '%s' derived from module '%s' is not a valid Go identifier (try '%s')",
+			pkg, moduleName, SanitizePackageName(pkg)))
+	}
+	return problems
+}
@@ -0,0 +1,206 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckGoNames(t *testing.T) {
+	tests := []struct {
+		module string
+		// want 為各問題應包含的文字；空白表示沒有問題
+		want []string
+	}{
+		{module: "github.com/me/app"},
+		{module: "github.com/me/app/v2"},
+		{module: "example.com/my_app"},
+		{module: "go", want: []string{"reserved by the go command (try 'goapp')"}},
+		{module: "std", want: []string{"reserved by the go command"}},
+		{module: "github.com/me/func", want: []string{"package name 'func' derived from module 'github.com/me/func' is a Go keyword (try 'funcpkg')"}},
+		{module: "github.com/me/type/v3", want: []string{"'type' derived from module"}},
+		{module: "github.com/me/my-app", want: []string{"package name 'my-app'", "not a valid Go identifier (try 'myapp')"}},
+		{module: "github.com/me/9lives", want: []string{"not a valid Go identifier (try 'pkg9lives')"}},
+		{module: "", want: []string{"module name is empty"}},
+		{module: "my app", want: []string{"contains whitespace (try 'my-app')"}},
+		{module: "github.com/me/app?x=1", want: []string{"characters not allowed in module paths"}},
+		{module: "github.com//app", want: []string{"empty path element"}},
+		{module: "github.com/.hidden", want: []string{"starting or ending with a dot"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			problems := CheckGoNames(tt.module)
+			if len(tt.want) == 0 {
+				if len(problems) != 0 {
+					t.Fatalf("CheckGoNames() = %q, want none", problems)
+				}
+				return
+			}
+			got := strings.Join(problems, "\n")
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("CheckGoNames() = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitizePackageName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "my-app", want: "myapp"},
+		{name: "My_App", want: "my_app"},
+		{name: "func", want: "funcpkg"},
+		{name: "2fast", want: "pkg2fast"},
+		{name: "日本", want: "app"},
+		{name: "", want: "app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizePackageName(tt.name); got != tt.want {
+				t.Errorf("SanitizePackageName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratorCheckGoNames(t *testing.T) {
+	goConfig := &TemplateConfig{Tags: []string{"Go"}}
+
+	tests := []struct {
+		name        string
+		config      *TemplateConfig
+		module      string
+		strict      bool
+		wantErr     bool
+		wantWarning string
+	}{
+		{name: "valid module", config: goConfig, module: "github.com/me/app"},
+		{name: "warning by default", config: goConfig, module: "github.com/me/func", wantWarning: "is a Go keyword"},
+		{name: "rejected with strict", config: goConfig, module: "github.com/me/func", strict: true, wantErr: true},
+		{name: "not a Go template", config: &TemplateConfig{Tags: []string{"node"}}, module: "func", strict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := &Generator{Strict: tt.strict}
+			var err error
+			output := captureStdout(t, func() {
+				err = generator.checkGoNames(tt.config, map[string]interface{}{"ModuleName": tt.module})
+			})
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidGoName) {
+					t.Fatalf("checkGoNames() error = %v, want %v", err, ErrInvalidGoName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkGoNames() error = %v", err)
+			}
+			if tt.wantWarning == "" && output != "" {
+				t.Errorf("unexpected output:\n%s", output)
+			}
+			if !strings.Contains(output, tt.wantWarning) {
+				t.Errorf("output = %q, want %q", output, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestNormalizeModulePath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "github.com/me/app", want: "github.com/me/app"},
+		{input: "  github.com/me/app  ", want: "github.com/me/app"},
+		{input: "https://github.com/me/app.git/", want: "github.com/me/app"},
+		{input: "git+ssh://github.com/me/app.git", want: "github.com/me/app"},
+		{input: "github.com/me/app//", want: "github.com/me/app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeModulePath(tt.input); got != tt.want {
+				t.Errorf("NormalizeModulePath(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModuleInCache(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	if err := os.MkdirAll(filepath.Join(cache, "cache", "download", "github.com", "!burnt!sushi", "toml", "@v"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		module string
+		want   bool
+	}{
+		{module: "github.com/BurntSushi/toml", want: true},
+		{module: "github.com/burntsushi/toml"},
+		{module: "github.com/me/app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			cached, dir := ModuleInCache(tt.module)
+			if cached != tt.want || dir != cache {
+				t.Errorf("ModuleInCache() = (%v, %q), want (%v, %q)", cached, dir, tt.want, cache)
+			}
+		})
+	}
+}
+
+func TestCheckGoNamesNormalizeAndCache(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+	if err := os.MkdirAll(filepath.Join(cache, "cache", "download", "github.com", "me", "taken", "@v"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	goConfig := &TemplateConfig{Tags: []string{"go"}}
+
+	tests := []struct {
+		name        string
+		module      string
+		checkModule bool
+		planning    bool
+		wantModule  string
+		wantWarning string
+	}{
+		{name: "normalized", module: "https://github.com/me/app.git", wantModule: "github.com/me/app", wantWarning: "Normalized module name 'https://github.com/me/app.git' to 'github.com/me/app'"},
+		{name: "cache not checked by default", module: "github.com/me/taken", wantModule: "github.com/me/taken"},
+		{name: "cache collision", module: "github.com/me/taken", checkModule: true, wantModule: "github.com/me/taken", wantWarning: "already exists in the local module cache"},
+		{name: "cache skipped while planning", module: "github.com/me/taken", checkModule: true, planning: true, wantModule: "github.com/me/taken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := &Generator{CheckModule: tt.checkModule, planning: tt.planning}
+			vars := map[string]interface{}{"ModuleName": tt.module}
+			var err error
+			output := captureStdout(t, func() { err = generator.checkGoNames(goConfig, vars) })
+			if err != nil {
+				t.Fatalf("checkGoNames() error = %v", err)
+			}
+			if vars["ModuleName"] != tt.wantModule {
+				t.Errorf("ModuleName = %v, want %q", vars["ModuleName"], tt.wantModule)
+			}
+			if tt.wantWarning == "" && output != "" {
+				t.Errorf("unexpected output:\n%s", output)
+			}
+			if !strings.Contains(output, tt.wantWarning) {
+				t.Errorf("output = %q, want %q", output, tt.wantWarning)
+			}
+		})
+	}
+}
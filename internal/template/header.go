@@ -0,0 +1,94 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// commentStyle 為在某種語言中把標頭寫成註解的方式：Open/Close 為區塊註解的開頭與結尾（行註解時為空），
+// Prefix 加在每一行之前
+type commentStyle struct {
+	Open, Prefix, Close string
+}
+
+// headerComments 依副檔名選擇註解語法；未列出的副檔名直接以標頭原文作為前綴
+var headerComments = map[string]commentStyle{
+	".go": {Prefix: "// "}, ".js": {Prefix: "// "}, ".jsx": {Prefix: "// "}, ".ts": {Prefix: "// "}, ".tsx": {Prefix: "// "},
+	".java": {Prefix: "// "}, ".kt": {Prefix: "// "}, ".swift": {Prefix: "// "}, ".rs": {Prefix: "// "},
+	".c": {Prefix: "// "}, ".h": {Prefix: "// "}, ".cpp": {Prefix: "// "}, ".cs": {Prefix: "// "}, ".proto": {Prefix: "// "},
+	".py": {Prefix: "# "}, ".sh": {Prefix: "# "}, ".rb": {Prefix: "# "}, ".yaml": {Prefix: "# "}, ".yml": {Prefix: "# "},
+	".toml": {Prefix: "# "}, ".mk": {Prefix: "# "}, ".sql": {Prefix: "-- "}, ".lua": {Prefix: "-- "},
+	".css": {Open: "/*", Prefix: " * ", Close: " */"}, ".scss": {Open: "/*", Prefix: " * ", Close: " */"},
+	".html": {Open: "<!--", Prefix: "  ", Close: "-->"}, ".vue": {Open: "<!--", Prefix: "  ", Close: "-->"},
+}
+
+// renderHeader 以變數渲染模板的 header；未設定 header 或 headerExtensions 時回傳空字串
+func renderHeader(config *TemplateConfig, vars map[string]interface{}) (string, error) {
+	if config == nil || strings.TrimSpace(config.Header) == "" || len(config.HeaderExtensions) == 0 {
+		return "", nil
+	}
+	header, err := renderText(config.Header, vars)
+	if err != nil {
+		return "", fmt.Errorf("failed to render header: %w", err)
+	}
+	return strings.TrimRight(header, "\n"), nil
+}
+
+// wantsHeader 回傳 targetPath 的副檔名是否在 headerExtensions 之中（可寫成 "go" 或 ".go"）
+func wantsHeader(config *TemplateConfig, targetPath string) bool {
+	ext := strings.ToLower(path.Ext(targetPath))
+	for _, want := range config.HeaderExtensions {
+		want = strings.ToLower(strings.TrimSpace(want))
+		if !strings.HasPrefix(want, ".") {
+			want = "." + want
+		}
+		if want == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// commentHeader 將標頭依副檔名的註解語法包裝，並以一個空白行與內容分隔
+func commentHeader(header, ext string) string {
+	style, ok := headerComments[ext]
+	if !ok {
+		return header + "\n\n"
+	}
+
+	var b strings.Builder
+	if style.Open != "" {
+		b.WriteString(style.Open + "\n")
+	}
+	for _, line := range strings.Split(header, "\n") {
+		b.WriteString(strings.TrimRight(style.Prefix+line, " ") + "\n")
+	}
+	if style.Close != "" {
+		b.WriteString(style.Close + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// prependHeader 在內容開頭加上標頭（#! 行之後），內容已以該標頭開頭時維持不變
+func prependHeader(content []byte, header, targetPath string) []byte {
+	commented := []byte(commentHeader(header, strings.ToLower(path.Ext(targetPath))))
+
+	var shebang []byte
+	body := content
+	if bytes.HasPrefix(content, []byte("#!")) {
+		if i := bytes.IndexByte(content, '\n'); i >= 0 {
+			shebang, body = content[:i+1], content[i+1:]
+		}
+	}
+	if bytes.HasPrefix(body, bytes.TrimRight(commented, "\n")) {
+		return content
+	}
+
+	result := make([]byte, 0, len(shebang)+len(commented)+len(body))
+	result = append(result, shebang...)
+	result = append(result, commented...)
+	return append(result, body...)
+}
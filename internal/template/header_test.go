@@ -0,0 +1,98 @@
+package template
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestPrependHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		content string
+		want    string
+	}{
+		{name: "line comment", target: "main.go", content: "package main\n", want: "// Copyright Acme\n// MIT\n\npackage main\n"},
+		{name: "hash comment", target: "deploy.YAML", content: "a: 1\n", want: "# Copyright Acme\n# MIT\n\na: 1\n"},
+		{name: "block comment", target: "site.css", content: "body {}\n", want: "/*\n * Copyright Acme\n * MIT\n */\n\nbody {}\n"},
+		{name: "after shebang", target: "run.sh", content: "#!/bin/sh\necho hi\n", want: "#!/bin/sh\n# Copyright Acme\n# MIT\n\necho hi\n"},
+		{name: "unknown extension", target: "NOTICE.txt", content: "text\n", want: "Copyright Acme\nMIT\n\ntext\n"},
+		{name: "already present", target: "main.go", content: "// Copyright Acme\n// MIT\n\npackage main\n", want: "// Copyright Acme\n// MIT\n\npackage main\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(prependHeader([]byte(tt.content), "Copyright Acme\nMIT", tt.target)); got != tt.want {
+				t.Errorf("prependHeader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWantsHeader(t *testing.T) {
+	config := &TemplateConfig{HeaderExtensions: []string{"go", ".TS", " py "}}
+
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{target: "main.go", want: true},
+		{target: "web/app.ts", want: true},
+		{target: "tool.PY", want: true},
+		{target: "README.md"},
+		{target: "Makefile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if got := wantsHeader(config, tt.target); got != tt.want {
+				t.Errorf("wantsHeader(%q) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   map[string]string
+	}{
+		{
+			name:   "listed extensions only",
+			config: "name: hdr\nheader: \"Copyright {{ .ProjectName }}\"\nheaderExtensions: [go]\n",
+			want:   map[string]string{"main.go": "// Copyright app\n\npackage main\n", "README.md": "# readme\n"},
+		},
+		{
+			name:   "no extensions listed",
+			config: "name: hdr\nheader: \"Copyright {{ .ProjectName }}\"\n",
+			want:   map[string]string{"main.go": "package main\n", "README.md": "# readme\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml": tt.config,
+				"main.go":       "package main\n",
+				"README.md":     "# readme\n",
+			})
+			generator := newTestGenerator(t, manager)
+
+			output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"})
+			if err != nil {
+				t.Fatalf("GenerateFS() error = %v", err)
+			}
+			for path, want := range tt.want {
+				data, err := fs.ReadFile(output, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != want {
+					t.Errorf("%s = %q, want %q", path, data, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestManager 回傳以暫存 HOME 與 XDG 目錄（含快取）隔離的 Manager，只載入內建模板
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	return manager
+}
+
+// writeFiles 在 dir 下建立 files（相對路徑對應內容）
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// installTestTemplate 將 files 寫成模板並安裝到 manager，回傳模板名稱（取自 template.yaml 的 name）
+func installTestTemplate(t *testing.T, manager *Manager, files map[string]string) string {
+	t.Helper()
+	source := filepath.Join(t.TempDir(), "template")
+	writeFiles(t, source, files)
+	result, err := manager.InstallTemplate(source)
+	if err != nil {
+		t.Fatalf("InstallTemplate() error = %v", err)
+	}
+	if err := manager.loadUserTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	return result.Name
+}
+
+// newTestGenerator 回傳在暫存工作目錄中產生、不詢問輸入的 Generator
+func newTestGenerator(t *testing.T, manager *Manager) *Generator {
+	t.Helper()
+	generator := NewGenerator(manager)
+	generator.WorkDir = t.TempDir()
+	generator.NoInput = true
+	return generator
+}
+
+// captureStdout 執行 fn 並回傳期間寫到 os.Stdout 的內容
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureOutput(t, &os.Stdout, fn)
+}
+
+// captureStderr 與 captureStdout 相同，但擷取 os.Stderr（例如載入模板時的警告）
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureOutput(t, &os.Stderr, fn)
+}
+
+func captureOutput(t *testing.T, stream **os.File, fn func()) string {
+	t.Helper()
+	file, err := os.CreateTemp(t.TempDir(), "output")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	original := *stream
+	*stream = file
+	defer func() { *stream = original }()
+	fn()
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
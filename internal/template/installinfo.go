@@ -0,0 +1,46 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// InstallInfoFileName 記錄用戶模板的安裝來源，位於已安裝模板的根目錄，不會被複製到專案
+const InstallInfoFileName = ".generator-install.json"
+
+// InstallInfo 為模板的安裝來源；Ref 為安裝時指定的 git 分支、tag 或 commit，
+// Subdir 為以 //subdir 選取的子目錄
+type InstallInfo struct {
+	Source      string    `json:"source"`
+	Ref         string    `json:"ref,omitempty"`
+	Subdir      string    `json:"subdir,omitempty"`
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// LoadInstallInfo 讀取已安裝模板目錄中的安裝來源；不存在時回傳 nil
+func LoadInstallInfo(templateDir string) (*InstallInfo, error) {
+	data, err := os.ReadFile(filepath.Join(templateDir, InstallInfoFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var info InstallInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", InstallInfoFileName, err)
+	}
+	return &info, nil
+}
+
+func (info *InstallInfo) save(templateDir string) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(templateDir, InstallInfoFileName), append(data, '\n'), defaultFileMode)
+}
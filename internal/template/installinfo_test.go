@@ -0,0 +1,83 @@
+package template
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadInstallInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		missing    bool
+		wantSource string
+		wantErr    string
+	}{
+		{name: "not recorded", missing: true},
+		{name: "recorded", content: `{"source":"https://example.com/me/tpl","ref":"v2","installedAt":"2026-01-02T03:04:05Z"}`, wantSource: "https://example.com/me/tpl"},
+		{name: "malformed", content: "{", wantErr: "failed to parse " + InstallInfoFileName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if !tt.missing {
+				writeFiles(t, dir, map[string]string{InstallInfoFileName: tt.content})
+			}
+
+			info, err := LoadInstallInfo(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadInstallInfo() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadInstallInfo() error = %v", err)
+			}
+			if tt.missing {
+				if info != nil {
+					t.Errorf("LoadInstallInfo() = %+v, want nil", info)
+				}
+				return
+			}
+			if info.Source != tt.wantSource || info.InstalledAt.IsZero() {
+				t.Errorf("LoadInstallInfo() = %+v, want source %q with a timestamp", info, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestLocalInstallRecordsSource(t *testing.T) {
+	manager := newTestManager(t)
+	source := filepath.Join(t.TempDir(), "template")
+	writeFiles(t, source, map[string]string{"template.yaml": "name: local\n", "main.go": "package main\n"})
+	manager.WorkDir = filepath.Dir(source)
+
+	result, err := manager.InstallTemplate("template")
+	if err != nil {
+		t.Fatalf("InstallTemplate() error = %v", err)
+	}
+	info, err := LoadInstallInfo(result.Path)
+	if err != nil || info == nil {
+		t.Fatalf("LoadInstallInfo() = %v, %v", info, err)
+	}
+	if info.Source != source || info.Ref != "" || info.InstalledAt.IsZero() {
+		t.Errorf("install info = %+v, want absolute source %q", info, source)
+	}
+
+	if err := manager.loadUserTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	generator := newTestGenerator(t, manager)
+	output, err := generator.GenerateFS("local", map[string]interface{}{"ProjectName": "app"})
+	if err != nil {
+		t.Fatalf("GenerateFS() error = %v", err)
+	}
+	if _, err := fs.Stat(output, InstallInfoFileName); !os.IsNotExist(err) {
+		t.Errorf("%s copied into the project: %v", InstallInfoFileName, err)
+	}
+}
@@ -2,13 +2,17 @@ package template
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
-	"gopkg.in/yaml.v3"
+	"aaa-generator/internal/console"
 )
 
 //go:embed all:templates
@@ -17,6 +21,30 @@ var embeddedTemplates embed.FS // `all:` prefix ensures directories are included
 type Manager struct {
 	localTemplates map[string]*Template
 	userTemplates  map[string]*Template
+	// archiveTemplates 為 LoadTemplateArchive 載入的暫時模板，優先於其他來源
+	archiveTemplates map[string]*Template
+
+	// NoSymlinks 安裝模板時將符號連結展開為實際內容，而非重建連結
+	NoSymlinks bool
+	// VerifyKey 若設定，安裝前以此 ed25519 公鑰驗證 template.yaml.sig
+	VerifyKey string
+	// IfNotPresent 同名模板已安裝時略過安裝；Reinstall 則先移除既有模板再安裝
+	IfNotPresent bool
+	Reinstall    bool
+	// WorkDir 若設定，相對的本機安裝來源以此為基準解析，而非目前工作目錄
+	WorkDir string
+	// Merge 將來源疊加到已安裝的同名模板（新增或更新檔案，保留其他檔案），見 InstallResult.Merge
+	Merge bool
+	// Status 為安裝與移除模板訊息的目的地，nil 時寫到 os.Stdout。
+	// 建立 Manager 時的載入警告與目錄搬移訊息一律寫到 stderr，因為此時還無法設定 Status
+	Status *console.Printer
+
+	fetchers map[string]Fetcher
+	// git 下載 http(s)、ssh 與 git@ 來源的儲存庫
+	git      Fetcher
+	warnings []string
+	// readOnly 為 true 時載入模板不寫入任何目錄，見 NewReadOnlyManager
+	readOnly bool
 }
 
 type Template struct {
@@ -30,35 +58,66 @@ type TemplateInfo struct {
 	DisplayName string
 	Description string
 	Version     string
+	Author      string
 	Source      string
 	Tags        []string
 	URL         string
+	// LocalPath 為模板在磁碟上的目錄；內建模板為空字串
+	LocalPath string
+
+	Deprecated         bool
+	DeprecationMessage string
 }
 
 func NewManager() (*Manager, error) {
+	return newManager(false)
+}
+
+// NewReadOnlyManager 與 NewManager 相同，但不建立用戶模板目錄也不搬移舊目錄，供 --dry-run 等不可寫入的情況使用
+func NewReadOnlyManager() (*Manager, error) {
+	return newManager(true)
+}
+
+func newManager(readOnly bool) (*Manager, error) {
 	manager := &Manager{
-		localTemplates: make(map[string]*Template),
-		userTemplates:  make(map[string]*Template),
+		readOnly:         readOnly,
+		localTemplates:   make(map[string]*Template),
+		userTemplates:    make(map[string]*Template),
+		archiveTemplates: make(map[string]*Template),
+		fetchers:         make(map[string]Fetcher),
+		git:              newGitFetcher(),
+	}
+	manager.RegisterFetcher("oci", &ociFetcher{client: http.DefaultClient})
+
+	// 將舊目錄中的用戶模板搬移到新位置
+	if !readOnly {
+		if from, to, err := migrateLegacyTemplates(); err != nil {
+			manager.warn("Failed to migrate user templates: %v", err)
+		} else if from != "" {
+			console.NewPrinter(os.Stderr).Printf("📦 Moved user templates from %s to %s\n", from, to)
+		}
 	}
 
 	// 載入內嵌模板
-	if err := manager.loadEmbeddedTemplates(); err != nil {
-		return nil, fmt.Errorf("failed to load embedded templates: %w", err)
+	if err := manager.loadEmbeddedTemplates(embeddedTemplates); err != nil {
+		return nil, err
 	}
 
 	// 載入用戶自定義模板
 	if err := manager.loadUserTemplates(); err != nil {
 		// 非致命錯誤，僅記錄日誌
-		fmt.Printf("Warning: Failed to load user templates: %v\n", err)
+		manager.warn("Failed to load user templates: %v", err)
 	}
 
 	return manager, nil
 }
 
-func (m *Manager) loadEmbeddedTemplates() error {
-	entries, err := embeddedTemplates.ReadDir("templates")
+// loadEmbeddedTemplates 從 fsys 的 templates/ 目錄載入內建模板；
+// 讀取失敗或沒有任何模板成功載入時回傳 ErrEmbeddedTemplates
+func (m *Manager) loadEmbeddedTemplates(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, "templates")
 	if err != nil {
-		return err
+		return newDetailError(ErrEmbeddedTemplates, "failed to read embedded templates (the binary may have been built incorrectly): %v", err)
 	}
 
 	for _, entry := range entries {
@@ -67,43 +126,48 @@ func (m *Manager) loadEmbeddedTemplates() error {
 		}
 
 		templateName := entry.Name()
-		configPath := fmt.Sprintf("templates/%s/template.yaml", templateName)
-
-		configData, err := embeddedTemplates.ReadFile(configPath)
+		templateFS, err := fs.Sub(fsys, fmt.Sprintf("templates/%s", templateName))
 		if err != nil {
-			fmt.Printf("Warning: Failed to read config for template %s: %v\n", templateName, err)
+			m.warn("Failed to create sub-filesystem for template %s: %v", templateName, err)
 			continue
 		}
 
-		var config TemplateConfig
-		if err := yaml.Unmarshal(configData, &config); err != nil {
-			fmt.Printf("Warning: Failed to parse config for template %s: %v\n", templateName, err)
+		_, configData, err := findConfigFile(templateFS)
+		if err != nil {
+			m.warn("Failed to read config for template %s: %v", templateName, err)
 			continue
 		}
 
-		templateFS, err := fs.Sub(embeddedTemplates, fmt.Sprintf("templates/%s", templateName))
+		config, err := parseTemplateConfig(configData, templateFS)
 		if err != nil {
-			fmt.Printf("Warning: Failed to create sub-filesystem for template %s: %v\n", templateName, err)
+			m.warn("Failed to parse config for template %s: %v", templateName, err)
 			continue
 		}
 
+		m.warnDuplicateVariables("template "+templateName, config)
 		m.localTemplates[templateName] = &Template{
-			Config: &config,
+			Config: config,
 			Files:  templateFS,
 		}
 	}
 
+	if len(m.localTemplates) == 0 {
+		return newDetailError(ErrEmbeddedTemplates, "no built-in templates could be loaded (the binary may have been built incorrectly)")
+	}
+
 	return nil
 }
 
 func (m *Manager) loadUserTemplates() error {
-	homeDir, err := os.UserHomeDir()
+	templatesDir, err := UserTemplatesDir()
 	if err != nil {
 		return err
 	}
 
-	templatesDir := filepath.Join(homeDir, ".go-react-generator", "templates")
 	if _, err := os.Stat(templatesDir); os.IsNotExist(err) {
+		if m.readOnly {
+			return nil
+		}
 		// 目錄不存在，創建它
 		if err := os.MkdirAll(templatesDir, 0755); err != nil {
 			return err
@@ -123,23 +187,28 @@ func (m *Manager) loadUserTemplates() error {
 
 		templateName := entry.Name()
 		templatePath := filepath.Join(templatesDir, templateName)
-		configPath := filepath.Join(templatePath, "template.yaml")
+		templateFS := os.DirFS(templatePath)
 
-		configData, err := os.ReadFile(configPath)
+		// 只有含模板設定檔（見 ConfigFileNames）的目錄才是模板，其他目錄（例如使用者自己的資料夾）直接略過
+		_, configData, err := findConfigFile(templateFS)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
 		if err != nil {
-			fmt.Printf("Warning: Failed to read config for user template %s: %v\n", templateName, err)
+			m.warn("Failed to read config for user template %s: %v", templateName, err)
 			continue
 		}
 
-		var config TemplateConfig
-		if err := yaml.Unmarshal(configData, &config); err != nil {
-			fmt.Printf("Warning: Failed to parse config for user template %s: %v\n", templateName, err)
+		config, err := parseTemplateConfig(configData, templateFS)
+		if err != nil {
+			m.warn("Failed to parse config for user template %s: %v", templateName, err)
 			continue
 		}
 
+		m.warnDuplicateVariables("user template "+templateName, config)
 		m.userTemplates[templateName] = &Template{
-			Config:    &config,
-			Files:     os.DirFS(templatePath),
+			Config:    config,
+			Files:     templateFS,
 			LocalPath: templatePath,
 		}
 	}
@@ -147,38 +216,151 @@ func (m *Manager) loadUserTemplates() error {
 	return nil
 }
 
+// warn 輸出並記錄載入模板時的非致命問題，供 LoadWarnings 查詢
+func (m *Manager) warn(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	m.warnings = append(m.warnings, msg)
+	console.NewPrinter(os.Stderr).Printf(console.Warning("Warning: %s\n"), msg)
+}
+
+// warnDuplicateVariables 對重複宣告的變數名稱發出載入警告；產生時只會使用第一個宣告
+func (m *Manager) warnDuplicateVariables(what string, config *TemplateConfig) {
+	for _, name := range duplicateVariables(config) {
+		m.warn("Variable %q is declared more than once in %s; only the first declaration is used", name, what)
+	}
+}
+
+// LoadWarnings 回傳建立 Manager 時遇到的警告，例如無法解析的模板
+func (m *Manager) LoadWarnings() []string {
+	return m.warnings
+}
+
+// ListTemplates 回傳所有內建與用戶模板，依來源再依名稱排序，每次呼叫的順序都相同
 func (m *Manager) ListTemplates() []TemplateInfo {
 	var templates []TemplateInfo
 
 	// 本地模板
 	for _, tmpl := range m.localTemplates {
-		templates = append(templates, TemplateInfo{
-			Name:        tmpl.Config.Name,
-			DisplayName: tmpl.Config.DisplayName,
-			Description: tmpl.Config.Description,
-			Version:     tmpl.Config.Version,
-			Source:      "built-in",
-			Tags:        tmpl.Config.Tags,
-		})
+		templates = append(templates, newTemplateInfo(tmpl, "built-in"))
 	}
 
 	// 用戶模板
 	for _, tmpl := range m.userTemplates {
-		templates = append(templates, TemplateInfo{
-			Name:        tmpl.Config.Name,
-			DisplayName: tmpl.Config.DisplayName,
-			Description: tmpl.Config.Description,
-			Version:     tmpl.Config.Version,
-			Source:      "user",
-			Tags:        tmpl.Config.Tags,
-		})
+		templates = append(templates, newTemplateInfo(tmpl, "user"))
 	}
 
+	SortTemplates(templates, "source")
 	return templates
 }
 
+// TemplateSortKeys 為 SortTemplates 支援的排序方式
+var TemplateSortKeys = []string{"source", "name", "version"}
+
+// SortTemplates 依 by（source、name 或 version）排序模板；相同時依名稱、再依來源排序。
+// by 不是支援的排序方式時回傳錯誤且不改變順序
+func SortTemplates(templates []TemplateInfo, by string) error {
+	var primary func(a, b TemplateInfo) int
+	switch by {
+	case "source":
+		primary = func(a, b TemplateInfo) int { return strings.Compare(a.Source, b.Source) }
+	case "name":
+		primary = func(a, b TemplateInfo) int { return 0 }
+	case "version":
+		primary = func(a, b TemplateInfo) int { return compareVersions(a.Version, b.Version) }
+	default:
+		return fmt.Errorf("invalid sort %q (expected %s)", by, strings.Join(TemplateSortKeys, ", "))
+	}
+
+	sort.SliceStable(templates, func(i, j int) bool {
+		a, b := templates[i], templates[j]
+		if c := primary(a, b); c != 0 {
+			return c < 0
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Source < b.Source
+	})
+	return nil
+}
+
+// TemplateConflict 表示同一個模板名稱出現在多個來源；Winner 為 GetTemplate 實際使用的來源
+type TemplateConflict struct {
+	Name    string
+	Sources []string
+	Winner  string
+}
+
+func (c TemplateConflict) String() string {
+	return fmt.Sprintf("template '%s' defined in both %s sources; %s wins", c.Name, strings.Join(c.Sources, " and "), c.Winner)
+}
+
+// Conflicts 回傳在多個來源中重複定義的模板名稱（依名稱排序）。
+// 來源依 GetTemplate 的優先順序列出，第一個即為實際使用的來源
+func (m *Manager) Conflicts() []TemplateConflict {
+	sources := []struct {
+		name      string
+		templates map[string]*Template
+	}{
+		{"archive", m.archiveTemplates},
+		{"user", m.userTemplates},
+		{"built-in", m.localTemplates},
+	}
+
+	defined := make(map[string][]string)
+	for _, source := range sources {
+		for name := range source.templates {
+			defined[name] = append(defined[name], source.name)
+		}
+	}
+
+	var conflicts []TemplateConflict
+	for name, found := range defined {
+		if len(found) > 1 {
+			conflicts = append(conflicts, TemplateConflict{Name: name, Sources: found, Winner: found[0]})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Name < conflicts[j].Name })
+	return conflicts
+}
+
+// GetTemplateInfo 回傳單一模板的中繼資料，優先順序與 GetTemplate 相同
+func (m *Manager) GetTemplateInfo(name string) (TemplateInfo, error) {
+	if tmpl, exists := m.archiveTemplates[name]; exists {
+		return newTemplateInfo(tmpl, "archive"), nil
+	}
+	if tmpl, exists := m.userTemplates[name]; exists {
+		return newTemplateInfo(tmpl, "user"), nil
+	}
+	if tmpl, exists := m.localTemplates[name]; exists {
+		return newTemplateInfo(tmpl, "built-in"), nil
+	}
+	return TemplateInfo{}, newDetailError(ErrTemplateNotFound, "template '%s' not found", name)
+}
+
+func newTemplateInfo(tmpl *Template, source string) TemplateInfo {
+	return TemplateInfo{
+		Name:        tmpl.Config.Name,
+		DisplayName: tmpl.Config.DisplayName,
+		Description: tmpl.Config.Description,
+		Version:     tmpl.Config.Version,
+		Author:      tmpl.Config.Author,
+		URL:         tmpl.Config.URL,
+		Source:      source,
+		Tags:        tmpl.Config.Tags,
+		LocalPath:   tmpl.LocalPath,
+
+		Deprecated:         tmpl.Config.Deprecated,
+		DeprecationMessage: tmpl.Config.DeprecationMessage,
+	}
+}
+
 func (m *Manager) GetTemplate(name string) (*Template, error) {
-	// 優先級: 用戶模板 > 本地模板
+	// 優先級: 暫時載入的封存模板 > 用戶模板 > 本地模板
+	if tmpl, exists := m.archiveTemplates[name]; exists {
+		return tmpl, nil
+	}
+
 	if tmpl, exists := m.userTemplates[name]; exists {
 		return tmpl, nil
 	}
@@ -187,58 +369,211 @@ func (m *Manager) GetTemplate(name string) (*Template, error) {
 		return tmpl, nil
 	}
 
-	return nil, fmt.Errorf("template '%s' not found", name)
+	return nil, newDetailError(ErrTemplateNotFound, "template '%s' not found", name)
+}
+
+// InstallResult 為 InstallTemplate 的結果；Skipped 表示因 IfNotPresent 而未重新安裝，
+// Merge 在以 Manager.Merge 疊加到既有模板時記錄檔案的變動數量
+type InstallResult struct {
+	Name    string
+	Source  string
+	Path    string
+	Version string
+	Skipped bool
+	Merge   *MergeStats
 }
 
-func (m *Manager) InstallTemplate(source string) error {
-	if strings.HasPrefix(source, "http") {
+// InstallTemplate 從本機目錄、git 儲存庫或已註冊 scheme 的來源安裝模板，回傳安裝結果而不輸出成功訊息
+func (m *Manager) InstallTemplate(source string) (*InstallResult, error) {
+	if fetcher, ok := m.fetcherFor(source); ok {
+		return m.installFetchedTemplate(fetcher, source, &InstallInfo{Source: source})
+	}
+	if isGitSource(source) {
 		return m.installRemoteTemplate(source)
 	}
-	return m.installLocalTemplate(source)
+
+	source = resolveIn(m.WorkDir, source)
+	info := &InstallInfo{Source: source}
+	if abs, err := filepath.Abs(source); err == nil {
+		info.Source = abs
+	}
+	return m.installLocalTemplate(source, info)
 }
 
-func (m *Manager) installLocalTemplate(sourcePath string) error {
+// installLocalTemplate 安裝本機的模板目錄，成功後在已安裝的模板中記錄 info（安裝來源）
+func (m *Manager) installLocalTemplate(sourcePath string, info *InstallInfo) (*InstallResult, error) {
 	// 檢查源路徑是否存在
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return fmt.Errorf("source path does not exist: %s", sourcePath)
+		return nil, fmt.Errorf("source path does not exist: %s", sourcePath)
 	}
 
 	// 讀取模板配置
-	configPath := filepath.Join(sourcePath, "template.yaml")
-	configData, err := os.ReadFile(configPath)
+	configName, configData, err := findConfigFile(os.DirFS(sourcePath))
 	if err != nil {
-		return fmt.Errorf("failed to read template config: %w", err)
+		return nil, fmt.Errorf("failed to read template config: %w", err)
+	}
+
+	if m.VerifyKey != "" {
+		payload, err := signedPayload(configData, os.DirFS(sourcePath))
+		if err != nil {
+			return nil, err
+		}
+		sigPath := filepath.Join(sourcePath, configName+signatureSuffix)
+		if err := verifyTemplateSignature(payload, sigPath, m.VerifyKey); err != nil {
+			return nil, err
+		}
 	}
 
-	var config TemplateConfig
-	if err := yaml.Unmarshal(configData, &config); err != nil {
-		return fmt.Errorf("failed to parse template config: %w", err)
+	config, err := parseTemplateConfig(configData, os.DirFS(sourcePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template config: %w", err)
 	}
 
 	// 創建用戶模板目錄
-	homeDir, err := os.UserHomeDir()
+	userTemplatesDir, err := UserTemplatesDir()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	userTemplatesDir := filepath.Join(homeDir, ".go-react-generator", "templates")
+	if !isValidTemplateName(config.Name) {
+		return nil, fmt.Errorf("invalid template name in config: %q", config.Name)
+	}
 	targetPath := filepath.Join(userTemplatesDir, config.Name)
 
-	// 複製模板文件
-	if err := copyDir(sourcePath, targetPath); err != nil {
-		return fmt.Errorf("failed to copy template: %w", err)
+	result := &InstallResult{Name: config.Name, Source: sourcePath, Path: targetPath, Version: config.Version}
+	if info != nil {
+		result.Source = info.Source
 	}
 
-	fmt.Printf("✅ Template '%s' installed successfully!\n", config.Name)
+	_, statErr := os.Stat(targetPath)
+	existed := statErr == nil
+	if existed {
+		switch {
+		case m.IfNotPresent:
+			result.Skipped = true
+			return result, nil
+		case m.Reinstall:
+			if err := os.RemoveAll(targetPath); err != nil {
+				return nil, fmt.Errorf("failed to remove installed template: %w", err)
+			}
+			existed = false
+		}
+	}
+
+	// 複製模板文件；Merge 時只疊加到既有的模板
+	if existed && m.Merge {
+		stats, err := mergeDir(sourcePath, targetPath, m.NoSymlinks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge template: %w", err)
+		}
+		result.Merge = &stats
+	} else if err := copyDir(sourcePath, targetPath, m.NoSymlinks); err != nil {
+		if existed {
+			// 覆寫既有模板時只清除這次留下的空目錄，不動原本的內容
+			removeEmptyDirs(targetPath, userTemplatesDir)
+		} else {
+			// 目錄是這次安裝建立的，移除不完整的內容
+			os.RemoveAll(targetPath)
+		}
+		return nil, fmt.Errorf("failed to copy template: %w", err)
+	}
+
+	if info != nil {
+		info.InstalledAt = time.Now().UTC()
+		if err := info.save(targetPath); err != nil {
+			m.status().Printf(console.Warning("⚠️  Warning: failed to record install source: %v\n"), err)
+		}
+	}
+
+	return result, nil
+}
+
+// UninstallTemplate 移除已安裝的用戶模板
+func (m *Manager) UninstallTemplate(name string) error {
+	tmpl, exists := m.userTemplates[name]
+	if !exists {
+		if _, builtin := m.localTemplates[name]; builtin {
+			return fmt.Errorf("template '%s' is built in and cannot be uninstalled", name)
+		}
+		return newDetailError(ErrTemplateNotFound, "template '%s' is not installed", name)
+	}
+
+	userTemplatesDir, err := UserTemplatesDir()
+	if err != nil {
+		return err
+	}
+	if tmpl.LocalPath == "" || !isWithinDir(userTemplatesDir, tmpl.LocalPath) || filepath.Clean(tmpl.LocalPath) == filepath.Clean(userTemplatesDir) {
+		return fmt.Errorf("refusing to remove %s: not inside %s", tmpl.LocalPath, userTemplatesDir)
+	}
+
+	err = os.RemoveAll(tmpl.LocalPath)
+	// RemoveAll 中途失敗時，仍清除已清空的子目錄；非空的內容與 templates 目錄本身保留
+	removeEmptyDirs(tmpl.LocalPath, userTemplatesDir)
+	if err != nil {
+		return fmt.Errorf("failed to remove template: %w", err)
+	}
+	delete(m.userTemplates, name)
+
+	m.status().Printf("🗑️  Template '%s' uninstalled\n", name)
 	return nil
 }
 
-func (m *Manager) installRemoteTemplate(url string) error {
-	// TODO: 實現從 Git URL 下載模板
-	return fmt.Errorf("remote template installation not implemented yet")
+// status 回傳安裝訊息的 Printer；未設定 Status 時寫到目前的 os.Stdout
+func (m *Manager) status() *console.Printer {
+	if m.Status != nil {
+		return m.Status
+	}
+	return console.NewPrinter(os.Stdout)
+}
+
+// removeEmptyDirs 由下而上移除 dir 之下（含 dir）的空目錄，並在 dir 變空時繼續往上移除父目錄，
+// 直到 stop 為止（stop 本身不會被移除）。非空目錄一律保留。
+func removeEmptyDirs(dir, stop string) {
+	if !isWithinDir(stop, dir) {
+		return
+	}
+
+	var dirs []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	// WalkDir 先走父目錄，反向處理才能先移除子目錄
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Remove(dirs[i])
+	}
+
+	stop = filepath.Clean(stop)
+	for current := filepath.Clean(dir); current != stop && isWithinDir(stop, current); current = filepath.Dir(current) {
+		if err := os.Remove(current); err != nil {
+			return
+		}
+	}
+}
+
+// isValidTemplateName 確認名稱可作為 templates 目錄下的單一目錄名稱
+func isValidTemplateName(name string) bool {
+	name = strings.TrimSpace(name)
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
 }
 
-func copyDir(src, dst string) error {
+// installRemoteTemplate 以 git clone 下載模板（可用 @ref 指定分支、tag 或 commit），
+// 下載至暫存目錄後交由 installLocalTemplate 安裝，以套用相同的簽章驗證
+func (m *Manager) installRemoteTemplate(source string) (*InstallResult, error) {
+	base, _ := splitSubdir(source)
+	url, ref := parseGitSource(base)
+	return m.installFetchedTemplate(m.git, source, &InstallInfo{Source: url, Ref: ref})
+}
+
+func copyDir(src, dst string, materialize bool) error {
+	return copyTree(src, src, dst, materialize, nil)
+}
+
+// copyTree 將 src 複製到 dst；符號連結一律以模板根目錄 root 檢查是否跳脫，
+// 展開連結目錄時 src 為連結目標，root 不變。expanding 為正在展開的連結目標，用來偵測互相指向的連結
+func copyTree(root, src, dst string, materialize bool, expanding []string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -251,28 +586,65 @@ func copyDir(src, dst string) error {
 
 		dstPath := filepath.Join(dst, relPath)
 
+		if info.Mode()&os.ModeSymlink != 0 {
+			return copySymlink(root, path, dstPath, materialize, expanding)
+		}
+
 		if info.IsDir() {
 			return os.MkdirAll(dstPath, info.Mode())
 		}
 
-		// 複製文件
-		srcFile, err := os.Open(path)
-		if err != nil {
-			return err
-		}
-		defer srcFile.Close()
+		return copyFile(path, dstPath)
+	})
+}
 
-		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
-			return err
-		}
+// copySymlink 重建模板內的符號連結；materialize 為 true 時改為複製連結目標的內容。
+// root 為模板根目錄，展開的目錄中巢狀的連結同樣以 root 檢查
+func copySymlink(root, linkPath, dstPath string, materialize bool, expanding []string) error {
+	target, resolved, err := resolveSymlink(root, linkPath)
+	if err != nil {
+		return err
+	}
 
-		dstFile, err := os.Create(dstPath)
-		if err != nil {
+	if !materialize {
+		return createSymlink(target, dstPath)
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		if err := checkSymlinkCycle(linkPath, resolved); err != nil {
 			return err
 		}
-		defer dstFile.Close()
+		for _, dir := range expanding {
+			if isWithinDir(resolved, dir) {
+				return fmt.Errorf("symlink %s leads back into %s, which is already being copied", linkPath, resolved)
+			}
+		}
+		return copyTree(root, resolved, dstPath, materialize, append(expanding[:len(expanding):len(expanding)], resolved))
+	}
+	return copyFile(resolved, dstPath)
+}
 
-		_, err = srcFile.WriteTo(dstFile)
+func copyFile(src, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
 		return err
-	})
+	}
+	defer srcFile.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	_, err = srcFile.WriteTo(dstFile)
+	return err
 }
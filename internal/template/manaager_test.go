@@ -0,0 +1,489 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInstallCleanupAndUninstall(t *testing.T) {
+	tests := []struct {
+		name string
+		// outsideLink 讓複製在建立部分目錄後失敗
+		outsideLink bool
+		uninstall   bool
+	}{
+		{name: "failed install removes its directories", outsideLink: true},
+		{name: "uninstall removes the template", uninstall: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			templatesDir, err := UserTemplatesDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 與安裝無關的空目錄不可被移除
+			unrelated := filepath.Join(templatesDir, "unrelated")
+			if err := os.MkdirAll(unrelated, 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			source := filepath.Join(t.TempDir(), "src")
+			writeFiles(t, source, map[string]string{
+				"template.yaml":      "name: demo\n",
+				"a/b/c/file.txt":     "x",
+				"a/b/empty/.gitkeep": "",
+			})
+			if tt.outsideLink {
+				if err := os.Symlink(t.TempDir(), filepath.Join(source, "z-link")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err = manager.InstallTemplate(source)
+			if tt.outsideLink {
+				if err == nil {
+					t.Fatal("InstallTemplate() succeeded, want an error for the outside symlink")
+				}
+			} else if err != nil {
+				t.Fatalf("InstallTemplate() error = %v", err)
+			}
+
+			if tt.uninstall {
+				if err := manager.loadUserTemplates(); err != nil {
+					t.Fatal(err)
+				}
+				if err := manager.UninstallTemplate("demo"); err != nil {
+					t.Fatalf("UninstallTemplate() error = %v", err)
+				}
+			}
+
+			if _, err := os.Stat(filepath.Join(templatesDir, "demo")); !os.IsNotExist(err) {
+				t.Errorf("template directory still exists (err = %v)", err)
+			}
+			if _, err := os.Stat(unrelated); err != nil {
+				t.Errorf("unrelated directory was removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetTemplateInfo(t *testing.T) {
+	manager := newTestManager(t)
+	installTestTemplate(t, manager, map[string]string{
+		"template.yaml": "name: card\nversion: 2.0.0\nauthor: Jane Doe\nurl: https://example.com/card\ntags: [go]\n",
+	})
+
+	tests := []struct {
+		name    string
+		lookup  string
+		want    TemplateInfo
+		wantErr error
+	}{
+		{
+			name:   "installed template",
+			lookup: "card",
+			want:   TemplateInfo{Name: "card", Version: "2.0.0", Author: "Jane Doe", URL: "https://example.com/card", Source: "user"},
+		},
+		{
+			name:   "built-in template",
+			lookup: "basic",
+			want:   TemplateInfo{Name: "basic", Source: "built-in"},
+		},
+		{
+			name:    "unknown template",
+			lookup:  "missing",
+			wantErr: ErrTemplateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := manager.GetTemplateInfo(tt.lookup)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetTemplateInfo() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTemplateInfo() error = %v", err)
+			}
+			if info.Name != tt.want.Name || info.Source != tt.want.Source {
+				t.Errorf("GetTemplateInfo() = %s/%s, want %s/%s", info.Name, info.Source, tt.want.Name, tt.want.Source)
+			}
+			if tt.want.Author != "" && (info.Author != tt.want.Author || info.URL != tt.want.URL || info.Version != tt.want.Version) {
+				t.Errorf("GetTemplateInfo() = %+v, want author, url and version of %+v", info, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortTemplates(t *testing.T) {
+	templates := []TemplateInfo{
+		{Name: "web", Source: "user", Version: "1.10.0"},
+		{Name: "api", Source: "user", Version: "1.9.0"},
+		{Name: "web", Source: "builtin", Version: "2.0.0"},
+		{Name: "cli", Source: "builtin", Version: "1.9.0"},
+	}
+
+	tests := []struct {
+		by      string
+		want    string
+		wantErr bool
+	}{
+		{by: "source", want: "builtin/cli,builtin/web,user/api,user/web"},
+		{by: "name", want: "user/api,builtin/cli,builtin/web,user/web"},
+		{by: "version", want: "user/api,builtin/cli,user/web,builtin/web"},
+		{by: "size", want: "user/web,user/api,builtin/web,builtin/cli", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			sorted := append([]TemplateInfo(nil), templates...)
+			err := SortTemplates(sorted, tt.by)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SortTemplates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, info := range sorted {
+				got = append(got, info.Source+"/"+info.Name)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("SortTemplates(%s) = %s, want %s", tt.by, strings.Join(got, ","), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEmbeddedTemplates(t *testing.T) {
+	good := &fstest.MapFile{Data: []byte("name: good\n")}
+	broken := &fstest.MapFile{Data: []byte("name: [\n")}
+
+	tests := []struct {
+		name  string
+		fsys  fstest.MapFS
+		want  []string
+		warns int
+	}{
+		{name: "templates load", fsys: fstest.MapFS{"templates/good/template.yaml": good}, want: []string{"good"}},
+		{name: "broken template is skipped", fsys: fstest.MapFS{"templates/good/template.yaml": good, "templates/bad/template.yaml": broken}, want: []string{"good"}, warns: 1},
+		{name: "no templates directory", fsys: fstest.MapFS{"other/file": good}},
+		{name: "nothing loads", fsys: fstest.MapFS{"templates/bad/template.yaml": broken, "templates/empty/README.md": good}, warns: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			manager.localTemplates = map[string]*Template{}
+			manager.warnings = nil
+
+			var err error
+			captureStdout(t, func() { err = manager.loadEmbeddedTemplates(tt.fsys) })
+			if len(tt.want) == 0 {
+				if !errors.Is(err, ErrEmbeddedTemplates) {
+					t.Fatalf("loadEmbeddedTemplates() error = %v, want ErrEmbeddedTemplates", err)
+				}
+			} else if err != nil {
+				t.Fatalf("loadEmbeddedTemplates() error = %v", err)
+			}
+			for _, name := range tt.want {
+				if _, ok := manager.localTemplates[name]; !ok {
+					t.Errorf("template %s was not loaded", name)
+				}
+			}
+			if len(manager.LoadWarnings()) != tt.warns {
+				t.Errorf("warnings = %q, want %d", manager.LoadWarnings(), tt.warns)
+			}
+		})
+	}
+}
+
+func TestInstallModes(t *testing.T) {
+	tests := []struct {
+		name         string
+		ifNotPresent bool
+		reinstall    bool
+		wantSkipped  bool
+		wantVersion  string
+		// wantStale 為舊版本才有的檔案是否仍存在
+		wantStale bool
+	}{
+		{name: "overwrite by default", wantVersion: "2.0.0", wantStale: true},
+		{name: "if not present", ifNotPresent: true, wantSkipped: true, wantVersion: "1.0.0", wantStale: true},
+		{name: "reinstall", reinstall: true, wantVersion: "2.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			source := filepath.Join(t.TempDir(), "tpl")
+			writeFiles(t, source, map[string]string{"template.yaml": "name: tpl\nversion: 1.0.0\n", "old.txt": "old\n"})
+			first, err := manager.InstallTemplate(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if first.Skipped {
+				t.Error("first install reported Skipped")
+			}
+
+			if err := os.Remove(filepath.Join(source, "old.txt")); err != nil {
+				t.Fatal(err)
+			}
+			writeFiles(t, source, map[string]string{"template.yaml": "name: tpl\nversion: 2.0.0\n"})
+			manager.IfNotPresent = tt.ifNotPresent
+			manager.Reinstall = tt.reinstall
+			result, err := manager.InstallTemplate(source)
+			if err != nil {
+				t.Fatalf("InstallTemplate() error = %v", err)
+			}
+			if result.Skipped != tt.wantSkipped {
+				t.Errorf("Skipped = %v, want %v", result.Skipped, tt.wantSkipped)
+			}
+
+			if version, err := templateVersion(result.Path); err != nil || version != tt.wantVersion {
+				t.Errorf("installed version = %q, %v; want %s", version, err, tt.wantVersion)
+			}
+			_, err = os.Stat(filepath.Join(result.Path, "old.txt"))
+			if stale := err == nil; stale != tt.wantStale {
+				t.Errorf("old.txt present = %v, want %v", stale, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestTemplateInfoLocalPath(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{"template.yaml": "name: located\n"})
+	templatesDir, err := UserTemplatesDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: name, want: filepath.Join(templatesDir, name)},
+		{name: "basic", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, info := range manager.ListTemplates() {
+				if info.Name == tt.name {
+					if info.LocalPath != tt.want {
+						t.Errorf("LocalPath = %q, want %q", info.LocalPath, tt.want)
+					}
+					return
+				}
+			}
+			t.Fatalf("%s not listed", tt.name)
+		})
+	}
+}
+
+func TestLoadUserTemplatesSkipsNonTemplates(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+		warns int
+	}{
+		{name: "template", files: map[string]string{"good/template.yaml": "name: good\n"}, want: []string{"good"}},
+		{name: "folder without template.yaml", files: map[string]string{"good/template.yaml": "name: good\n", "notes/todo.txt": "x"}, want: []string{"good"}},
+		{name: "broken template.yaml still warns", files: map[string]string{"bad/template.yaml": "name: [\n"}, warns: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			templatesDir, err := UserTemplatesDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeFiles(t, templatesDir, tt.files)
+			manager.warnings = nil
+
+			captureStdout(t, func() { err = manager.loadUserTemplates() })
+			if err != nil {
+				t.Fatalf("loadUserTemplates() error = %v", err)
+			}
+			for _, name := range tt.want {
+				if _, err := manager.GetTemplate(name); err != nil {
+					t.Errorf("template %s was not loaded: %v", name, err)
+				}
+			}
+			if _, err := manager.GetTemplate("notes"); err == nil {
+				t.Error("folder without template.yaml was loaded as a template")
+			}
+			if len(manager.LoadWarnings()) != tt.warns {
+				t.Errorf("warnings = %q, want %d", manager.LoadWarnings(), tt.warns)
+			}
+		})
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    []string
+		archive []string
+		want    []string
+	}{
+		{name: "no conflicts", user: []string{"mine"}},
+		{name: "user shadows built-in", user: []string{"basic", "mine"}, want: []string{"template 'basic' defined in both user and built-in sources; user wins"}},
+		{
+			name:    "archive shadows user and built-in",
+			user:    []string{"basic", "mine"},
+			archive: []string{"basic", "mine"},
+			want: []string{
+				"template 'basic' defined in both archive and user and built-in sources; archive wins",
+				"template 'mine' defined in both archive and user sources; archive wins",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			for _, name := range tt.user {
+				manager.userTemplates[name] = &Template{Config: &TemplateConfig{Name: name}}
+			}
+			for _, name := range tt.archive {
+				manager.archiveTemplates[name] = &Template{Config: &TemplateConfig{Name: name}}
+			}
+
+			var got []string
+			for _, conflict := range manager.Conflicts() {
+				if conflict.Winner != conflict.Sources[0] {
+					t.Errorf("%s: winner %s is not the first source %v", conflict.Name, conflict.Winner, conflict.Sources)
+				}
+				got = append(got, conflict.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Conflicts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallResult(t *testing.T) {
+	files := map[string]string{"template.yaml": "name: result\nversion: 1.2.0\n"}
+
+	tests := []struct {
+		name   string
+		source string
+		// fetched 為 true 時以假的 fetcher 安裝，否則從 WorkDir 下的相對路徑安裝
+		fetched bool
+	}{
+		{name: "local directory", source: "tpl"},
+		{name: "fetched source", source: "fake://example.com/tpl", fetched: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			manager.WorkDir = t.TempDir()
+			wantSource := tt.source
+			if tt.fetched {
+				manager.RegisterFetcher("fake", &treeFetcher{t: t, files: files})
+			} else {
+				writeFiles(t, filepath.Join(manager.WorkDir, tt.source), files)
+				wantSource = filepath.Join(manager.WorkDir, tt.source)
+			}
+			templatesDir, err := UserTemplatesDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result *InstallResult
+			output := captureStdout(t, func() { result, err = manager.InstallTemplate(tt.source) })
+			if err != nil {
+				t.Fatalf("InstallTemplate() error = %v", err)
+			}
+			want := InstallResult{Name: "result", Source: wantSource, Path: filepath.Join(templatesDir, "result"), Version: "1.2.0"}
+			if result.Name != want.Name || result.Source != want.Source || result.Path != want.Path || result.Version != want.Version || result.Skipped {
+				t.Errorf("InstallTemplate() = %+v, want %+v", *result, want)
+			}
+			// 輸出由呼叫端負責
+			if output != "" {
+				t.Errorf("InstallTemplate() printed %q", output)
+			}
+		})
+	}
+}
+
+func TestDuplicateVariableWarnings(t *testing.T) {
+	manager := newTestManager(t)
+	templatesDir, err := UserTemplatesDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, templatesDir, map[string]string{
+		"dup/template.yaml": "name: dup\nvariables:\n  - name: Port\n    default: \"8080\"\n  - name: Port\n    default: \"9090\"\n",
+		"dup/port.txt.tmpl": "{{ .Port }}",
+	})
+	manager.warnings = nil
+	captureStdout(t, func() { err = manager.loadUserTemplates() })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Variable "Port" is declared more than once in user template dup; only the first declaration is used`
+	if warnings := manager.LoadWarnings(); len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("LoadWarnings() = %q, want [%q]", warnings, want)
+	}
+	// 模板仍可使用，且以第一個宣告為準
+	generator := newTestGenerator(t, manager)
+	if _, err := generator.Generate("app", "dup"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(generator.WorkDir, "app", "port.txt")); err != nil || string(data) != "8080" {
+		t.Errorf("port.txt = %q, %v; want 8080", data, err)
+	}
+}
+
+func TestNewReadOnlyManager(t *testing.T) {
+	tests := []struct {
+		name string
+		// legacy 為舊的 ~/.go-react-generator/templates 下的檔案
+		legacy map[string]string
+		want   string
+	}{
+		{name: "empty home", want: "basic"},
+		// 舊位置的模板照常載入，但不搬移
+		{name: "legacy templates", legacy: map[string]string{"old/template.yaml": "name: old\n"}, want: "old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+			legacy := filepath.Join(home, legacyDirName, "templates")
+			writeFiles(t, legacy, tt.legacy)
+
+			var manager *Manager
+			var err error
+			output := captureStderr(t, func() { manager, err = NewReadOnlyManager() })
+			if err != nil {
+				t.Fatalf("NewReadOnlyManager() error = %v", err)
+			}
+			if _, err := manager.GetTemplate(tt.want); err != nil {
+				t.Errorf("GetTemplate(%s) error = %v", tt.want, err)
+			}
+			if _, err := os.Stat(filepath.Join(home, "data")); !os.IsNotExist(err) {
+				t.Errorf("data directory was created: %v", err)
+			}
+			if strings.Contains(output, "Moved user templates") {
+				t.Errorf("legacy templates were migrated:\n%s", output)
+			}
+		})
+	}
+}
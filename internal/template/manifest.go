@@ -0,0 +1,159 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ManifestFileName 為記錄產生結果的檔案，位於專案根目錄
+const ManifestFileName = ".generator-manifest.json"
+
+// Manifest 記錄一次產生所使用的模板、變數與產生的檔案（相對於專案根目錄、以 / 分隔）。
+// 變數保留原本的 JSON 型別（例如 dataCommand 提供的物件與數字），重新產生時照樣還原
+type Manifest struct {
+	Template  string                 `json:"template"`
+	Version   string                 `json:"version"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+	// Secrets 為 secret 變數的名稱；它們的值不寫入 manifest，重新產生時必須再次提供，見 RegenerateFile
+	Secrets []string `json:"secrets,omitempty"`
+	Files   []string `json:"files"`
+	// Appended 為 append 規則寫入的檔案（同時列在 Files 中）。它們可能含有模板以外的內容，pruneFiles 一律保留
+	Appended []string `json:"appended,omitempty"`
+}
+
+func newManifest(tmpl *Template, vars map[string]interface{}, files, appended []string) *Manifest {
+	secret := make(map[string]bool)
+	if tmpl.Config != nil {
+		for _, variable := range tmpl.Config.Variables {
+			secret[variable.Name] = variable.Secret
+		}
+	}
+
+	manifest := &Manifest{
+		Variables: make(map[string]interface{}, len(vars)),
+		Files:     append([]string(nil), files...),
+	}
+	for name, value := range vars {
+		if secret[name] {
+			manifest.Secrets = append(manifest.Secrets, name)
+			continue
+		}
+		manifest.Variables[name] = value
+	}
+	if tmpl.Config != nil {
+		manifest.Template = tmpl.Config.Name
+		manifest.Version = tmpl.Config.Version
+	}
+	for _, file := range appended {
+		if !contains(manifest.Appended, file) {
+			manifest.Appended = append(manifest.Appended, file)
+		}
+	}
+	sort.Strings(manifest.Secrets)
+	sort.Strings(manifest.Files)
+	sort.Strings(manifest.Appended)
+
+	return manifest
+}
+
+// LoadManifest 讀取專案目錄中的 manifest；不存在時回傳 nil
+func LoadManifest(projectDir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, ManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// UseNumber 讓數字以原本的寫法還原（8080 而不是 float64 的 8080.0）
+	var manifest Manifest
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestFileName, err)
+	}
+	return &manifest, nil
+}
+
+func (m *Manifest) save(projectDir string) error {
+	return m.WriteFile(filepath.Join(projectDir, ManifestFileName))
+}
+
+// WriteFile 將 manifest 以縮排的 JSON 寫入 path
+func (m *Manifest) WriteFile(path string) error {
+	data, err := m.marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// WriteTo 將 manifest 以與 WriteFile 相同的 JSON 格式寫到 w，例如 --print-manifest 輸出到 stdout
+func (m *Manifest) WriteTo(w io.Writer) (int64, error) {
+	data, err := m.marshal()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+func (m *Manifest) marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// pruneFiles 刪除舊 manifest 中有、而新 manifest 中沒有的檔案，以及因此變空的目錄。
+// 只會處理 manifest 記錄過的路徑，使用者自行建立的檔案不受影響；append 規則寫入的檔案也保留。
+func (g *Generator) pruneFiles(projectDir string, current *Manifest) error {
+	previous, err := LoadManifest(projectDir)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return nil
+	}
+
+	keep := make(map[string]bool, len(current.Files))
+	for _, file := range current.Files {
+		keep[file] = true
+	}
+
+	for _, file := range previous.Appended {
+		keep[file] = true
+	}
+
+	projectDir = filepath.Clean(projectDir)
+	for _, file := range previous.Files {
+		if keep[file] {
+			continue
+		}
+
+		target := filepath.Join(projectDir, filepath.FromSlash(file))
+		if !isWithinDir(projectDir, target) {
+			continue
+		}
+		if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		g.status().Printf("   • Pruned: %s\n", file)
+
+		// 往上移除變空的目錄，遇到非空目錄（例如還有使用者的檔案）或專案根目錄即停止
+		for dir := filepath.Dir(target); dir != projectDir && isWithinDir(projectDir, dir); dir = filepath.Dir(dir) {
+			if err := os.Remove(dir); err != nil {
+				break
+			}
+		}
+	}
+
+	return nil
+}
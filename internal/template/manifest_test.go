@@ -0,0 +1,281 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateManifestAndPrune(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: prune
+version: 1.2.0
+variables:
+  - name: Extra
+    type: bool
+    default: "true"
+files:
+  - source: main.go
+    type: file
+  - source: extra.go
+    type: file
+    condition: '{{ eq .Extra "true" }}'
+`,
+		"main.go":  "package main\n",
+		"extra.go": "package main\n",
+	}
+
+	tests := []struct {
+		name      string
+		force     bool
+		prune     bool
+		wantErr   error
+		wantExtra bool
+	}{
+		{name: "existing directory without force", wantErr: ErrDirExists, wantExtra: true},
+		{name: "force keeps stale files", force: true, wantExtra: true},
+		{name: "force and prune", force: true, prune: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			projectDir := filepath.Join(generator.WorkDir, "app")
+
+			if _, err := generator.Generate("app", name); err != nil {
+				t.Fatalf("first Generate() error = %v", err)
+			}
+			manifest, err := LoadManifest(projectDir)
+			if err != nil || manifest == nil {
+				t.Fatalf("LoadManifest() = %v, %v", manifest, err)
+			}
+			if manifest.Template != "prune" || manifest.Version != "1.2.0" || !reflect.DeepEqual(manifest.Files, []string{"extra.go", "main.go"}) {
+				t.Errorf("manifest = %+v", manifest)
+			}
+			writeFiles(t, projectDir, map[string]string{"notes.txt": "mine\n"})
+
+			generator.Force = tt.force
+			generator.Prune = tt.prune
+			generator.Values = map[string]string{"Extra": "false"}
+			_, err = generator.Generate("app", name)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("second Generate() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("second Generate() error = %v", err)
+			}
+
+			_, err = os.Stat(filepath.Join(projectDir, "extra.go"))
+			if gotExtra := err == nil; gotExtra != tt.wantExtra {
+				t.Errorf("extra.go exists = %v, want %v", gotExtra, tt.wantExtra)
+			}
+			if _, err := os.Stat(filepath.Join(projectDir, "notes.txt")); err != nil {
+				t.Errorf("user file removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestPruneAppendedFilesAndEmptyDirs(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: prune
+variables:
+  - name: Extra
+    type: bool
+    default: "true"
+files:
+  - source: main.go
+    type: file
+  - source: gitignore.tmpl
+    target: .gitignore
+    append: true
+    condition: '{{ eq .Extra "true" }}'
+  - source: extra
+    target: extra
+    condition: '{{ eq .Extra "true" }}'
+  - source: shared/extra.go
+    target: shared/extra.go
+    condition: '{{ eq .Extra "true" }}'
+`,
+		"main.go":               "package main\n",
+		"gitignore.tmpl":        "{{ .ProjectName }}.log\n",
+		"extra/nested/extra.go": "package nested\n",
+		"shared/extra.go":       "package shared\n",
+	})
+	generator := newTestGenerator(t, manager)
+	projectDir := filepath.Join(generator.WorkDir, "app")
+	// .gitignore 在產生前就已存在，append 規則只是附加到後面
+	writeFiles(t, projectDir, map[string]string{".gitignore": "user.log\n"})
+
+	generator.Force = true
+	captureStdout(t, func() {
+		if _, err := generator.Generate("app", name); err != nil {
+			t.Fatalf("first Generate() error = %v", err)
+		}
+	})
+	manifest, err := LoadManifest(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(manifest.Appended, []string{".gitignore"}) {
+		t.Errorf("Appended = %v, want [.gitignore]", manifest.Appended)
+	}
+	writeFiles(t, projectDir, map[string]string{"shared/notes.txt": "mine\n"})
+
+	generator.Prune = true
+	generator.Values = map[string]string{"Extra": "false"}
+	captureStdout(t, func() {
+		if _, err := generator.Generate("app", name); err != nil {
+			t.Fatalf("second Generate() error = %v", err)
+		}
+	})
+
+	tests := []struct {
+		path   string
+		exists bool
+	}{
+		{path: ".gitignore", exists: true},
+		{path: "main.go", exists: true},
+		{path: "extra/nested/extra.go"},
+		// 刪除檔案後變空的目錄一併移除
+		{path: "extra"},
+		{path: "shared/extra.go"},
+		// 還有使用者檔案的目錄保留
+		{path: "shared/notes.txt", exists: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(tt.path)))
+			if exists := err == nil; exists != tt.exists {
+				t.Errorf("%s exists = %v, want %v", tt.path, exists, tt.exists)
+			}
+		})
+	}
+	if data, _ := os.ReadFile(filepath.Join(projectDir, ".gitignore")); !strings.HasPrefix(string(data), "user.log\n") {
+		t.Errorf(".gitignore = %q, want the user's lines kept", data)
+	}
+}
+
+func TestManifestSecrets(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: secrets
+variables:
+  - name: Token
+    secret: true
+  - name: Region
+    default: eu
+`,
+		"config.txt.tmpl": "{{ .Token }} {{ .Region }}\n",
+	}
+
+	tests := []struct {
+		name     string
+		manifest func(t *testing.T, generator *Generator, template string) *Manifest
+	}{
+		{
+			name: "saved by Generate",
+			manifest: func(t *testing.T, generator *Generator, template string) *Manifest {
+				if _, err := generator.Generate("app", template); err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				data, err := os.ReadFile(filepath.Join(generator.WorkDir, "app", ManifestFileName))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if strings.Contains(string(data), "hunter2") {
+					t.Errorf("%s contains the secret value:\n%s", ManifestFileName, data)
+				}
+				manifest, err := LoadManifest(filepath.Join(generator.WorkDir, "app"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return manifest
+			},
+		},
+		{
+			// --manifest-only 與 --print-manifest 使用的 manifest
+			name: "planned",
+			manifest: func(t *testing.T, generator *Generator, template string) *Manifest {
+				manifest, err := generator.Plan("app", template)
+				if err != nil {
+					t.Fatalf("Plan() error = %v", err)
+				}
+				return manifest
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.Values = map[string]string{"Token": "hunter2"}
+
+			var manifest *Manifest
+			captureStdout(t, func() { manifest = tt.manifest(t, generator, name) })
+			if _, saved := manifest.Variables["Token"]; saved {
+				t.Errorf("Variables = %v, want Token left out", manifest.Variables)
+			}
+			if manifest.Variables["Region"] != "eu" {
+				t.Errorf("Region = %v, want eu", manifest.Variables["Region"])
+			}
+			if !reflect.DeepEqual(manifest.Secrets, []string{"Token"}) {
+				t.Errorf("Secrets = %v, want [Token]", manifest.Secrets)
+			}
+		})
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		missing bool
+		wantErr string
+	}{
+		{name: "no manifest", missing: true},
+		{name: "numbers keep their form", content: `{"template":"t","version":"1","variables":{"Port":8080},"files":["main.go"]}`},
+		{name: "malformed", content: "[", wantErr: "failed to parse " + ManifestFileName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if !tt.missing {
+				writeFiles(t, dir, map[string]string{ManifestFileName: tt.content})
+			}
+
+			manifest, err := LoadManifest(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadManifest() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadManifest() error = %v", err)
+			}
+			if tt.missing {
+				if manifest != nil {
+					t.Errorf("LoadManifest() = %+v, want nil", manifest)
+				}
+				return
+			}
+			var out strings.Builder
+			if _, err := manifest.WriteTo(&out); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), `"Port": 8080`) {
+				t.Errorf("round-tripped manifest = %s", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package template
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+)
+
+// MergeStats 為 --merge 安裝時新增、更新與內容未變的檔案數
+type MergeStats struct {
+	Added     int
+	Updated   int
+	Unchanged int
+}
+
+// mergeDir 將 src 疊加到既有的 dst：新增或更新內容不同的檔案，dst 中其他檔案保持不變
+func mergeDir(src, dst string, materialize bool) (MergeStats, error) {
+	var stats MergeStats
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dst, relPath)
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			stats.Updated++
+			return copySymlink(src, path, dstPath, materialize, nil)
+		}
+		if info.IsDir() {
+			return os.MkdirAll(dstPath, info.Mode())
+		}
+
+		existing, err := os.ReadFile(dstPath)
+		switch {
+		case os.IsNotExist(err):
+			stats.Added++
+		case err != nil:
+			return err
+		default:
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if bytes.Equal(existing, content) {
+				stats.Unchanged++
+				return nil
+			}
+			stats.Updated++
+		}
+		return copyFile(path, dstPath)
+	})
+	return stats, err
+}
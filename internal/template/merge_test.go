@@ -0,0 +1,69 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallMerge(t *testing.T) {
+	installed := map[string]string{
+		"template.yaml": "name: merged\n",
+		"a.txt":         "old a\n",
+		"b.txt":         "b\n",
+	}
+	overlay := map[string]string{
+		"template.yaml": "name: merged\n",
+		"a.txt":         "new a\n",
+		"docs/c.txt":    "c\n",
+	}
+
+	tests := []struct {
+		name      string
+		existing  map[string]string
+		wantStats *MergeStats
+		wantFiles map[string]string
+	}{
+		{
+			name:      "overlay onto an installed template",
+			existing:  installed,
+			wantStats: &MergeStats{Added: 1, Updated: 1, Unchanged: 1},
+			wantFiles: map[string]string{"a.txt": "new a\n", "b.txt": "b\n", "docs/c.txt": "c\n"},
+		},
+		{
+			name:      "nothing installed yet",
+			wantFiles: map[string]string{"a.txt": "new a\n", "docs/c.txt": "c\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			if tt.existing != nil {
+				installTestTemplate(t, manager, tt.existing)
+			}
+			source := filepath.Join(t.TempDir(), "overlay")
+			writeFiles(t, source, overlay)
+			manager.Merge = true
+
+			result, err := manager.InstallTemplate(source)
+			if err != nil {
+				t.Fatalf("InstallTemplate() error = %v", err)
+			}
+			if (result.Merge == nil) != (tt.wantStats == nil) || (result.Merge != nil && *result.Merge != *tt.wantStats) {
+				t.Errorf("Merge = %+v, want %+v", result.Merge, tt.wantStats)
+			}
+			for name, want := range tt.wantFiles {
+				data, err := os.ReadFile(filepath.Join(result.Path, filepath.FromSlash(name)))
+				if err != nil || string(data) != want {
+					t.Errorf("%s = %q, %v; want %q", name, data, err, want)
+				}
+			}
+			if tt.existing == nil {
+				if _, err := os.Stat(filepath.Join(result.Path, "b.txt")); !os.IsNotExist(err) {
+					t.Errorf("b.txt unexpectedly present: %v", err)
+				}
+			}
+		})
+	}
+}
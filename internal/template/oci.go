@@ -0,0 +1,314 @@
+package template
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociTitleAnnotation   = "org.opencontainers.image.title"
+)
+
+// ociFetcher 透過 OCI Distribution API 下載模板 artifact（例如以 oras push 上傳的目錄或檔案）。
+// tar 類型的 layer 會被解開，其他 layer 依 title annotation 寫成檔案。
+type ociFetcher struct {
+	client *http.Client
+}
+
+type ociReference struct {
+	registry   string
+	repository string
+	reference  string
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// parseOCIReference 解析 oci://registry/repository[:tag|@digest]，未指定時使用 latest
+func parseOCIReference(source string) (ociReference, error) {
+	rest := strings.TrimPrefix(source, "oci://")
+	registry, repository, ok := strings.Cut(rest, "/")
+	if !ok || registry == "" || repository == "" {
+		return ociReference{}, fmt.Errorf("invalid OCI reference %q (expected oci://registry/repository[:tag])", source)
+	}
+
+	ref := ociReference{registry: registry, repository: repository, reference: "latest"}
+	if name, digest, found := strings.Cut(repository, "@"); found {
+		ref.repository, ref.reference = name, digest
+	} else if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		ref.repository, ref.reference = repository[:i], repository[i+1:]
+	}
+	return ref, nil
+}
+
+func (r ociReference) url(kind, ref string) string {
+	scheme := "https"
+	host := strings.Split(r.registry, ":")[0]
+	if host == "localhost" || host == "127.0.0.1" {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/%s/%s", scheme, r.registry, r.repository, kind, ref)
+}
+
+func (f *ociFetcher) Fetch(source, dst string) error {
+	ref, err := parseOCIReference(source)
+	if err != nil {
+		return err
+	}
+
+	body, err := f.get(ref.url("manifests", ref.reference), ociManifestMediaType)
+	if err != nil {
+		return err
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return fmt.Errorf("failed to parse OCI manifest: %w", err)
+	}
+	if len(manifest.Layers) == 0 {
+		return fmt.Errorf("OCI artifact has no layers")
+	}
+
+	for _, layer := range manifest.Layers {
+		blob, err := f.get(ref.url("blobs", layer.Digest), "")
+		if err != nil {
+			return err
+		}
+		if err := verifyDigest(blob, layer.Digest); err != nil {
+			return err
+		}
+		if err := writeOCILayer(layer, blob, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get 發出 GET 請求；遇到 401 時依 WWW-Authenticate 取得匿名 Bearer token 後重試
+func (f *ociFetcher) get(target, accept string) ([]byte, error) {
+	resp, err := f.do(target, accept, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		token, err := f.token(challenge)
+		if err != nil {
+			return nil, err
+		}
+		if resp, err = f.do(target, accept, token); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", target, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (f *ociFetcher) do(target, accept, token string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return f.client.Do(req)
+}
+
+func (f *ociFetcher) token(challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("registry requires unsupported authentication: %q", challenge)
+	}
+
+	values := url.Values{}
+	var realm string
+	for _, part := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		if key == "realm" {
+			realm = value
+		} else {
+			values.Set(key, value)
+		}
+	}
+	if realm == "" {
+		return "", fmt.Errorf("registry authentication challenge has no realm")
+	}
+
+	resp, err := f.client.Get(realm + "?" + values.Encode())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get registry token: %s", resp.Status)
+	}
+
+	var payload struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", err
+	}
+	if payload.Token != "" {
+		return payload.Token, nil
+	}
+	return payload.AccessToken, nil
+}
+
+func verifyDigest(data []byte, digest string) error {
+	algorithm, expected, ok := strings.Cut(digest, ":")
+	if !ok || algorithm != "sha256" {
+		return fmt.Errorf("unsupported digest %q", digest)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != expected {
+		return fmt.Errorf("digest mismatch for %s", digest)
+	}
+	return nil
+}
+
+func writeOCILayer(layer ociDescriptor, blob []byte, dst string) error {
+	if strings.Contains(layer.MediaType, "tar") {
+		return extractTar(bytes.NewReader(blob), dst)
+	}
+
+	title := layer.Annotations[ociTitleAnnotation]
+	if title == "" {
+		return fmt.Errorf("layer %s has no %s annotation", layer.Digest, ociTitleAnnotation)
+	}
+	target := filepath.Join(dst, filepath.FromSlash(title))
+	if !isWithinDir(dst, target) {
+		return fmt.Errorf("layer title %q escapes the template directory", title)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, blob, 0o644)
+}
+
+// extractTar 將 tar（可為 gzip 壓縮）解開到 dst，拒絕跳脫 dst 的路徑、指向 dst 之外的符號連結，
+// 以及經由先前解開的符號連結寫入的項目
+func extractTar(r io.Reader, dst string) error {
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = buffered
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, filepath.FromSlash(header.Name))
+		if !isWithinDir(dst, target) {
+			return fmt.Errorf("archive entry %q escapes the template directory", header.Name)
+		}
+		if link, ok := symlinkedParent(dst, target); ok {
+			return fmt.Errorf("archive entry %q is written through symlink %q", header.Name, link)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			// 既有的符號連結會被 OpenFile 追蹤，先移除再建立一般檔案
+			if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(target); err != nil {
+					return err
+				}
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(file, tr); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			resolved := filepath.Join(filepath.Dir(target), filepath.FromSlash(header.Linkname))
+			if filepath.IsAbs(header.Linkname) || !isWithinDir(dst, resolved) {
+				return fmt.Errorf("archive symlink %q points outside the template directory (%s)", header.Name, header.Linkname)
+			}
+			if err := createSymlink(header.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// symlinkedParent 回傳 root 與 target 之間（不含兩者）第一個已存在的符號連結目錄
+func symlinkedParent(root, target string) (string, bool) {
+	rel, err := filepath.Rel(root, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return "", false
+	}
+	current := root
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if err != nil {
+			return "", false
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return current, true
+		}
+	}
+	return "", false
+}
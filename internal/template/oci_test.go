@@ -0,0 +1,307 @@
+package template
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type tarEntry struct {
+	name     string
+	linkname string
+	body     string
+	dir      bool
+}
+
+func buildTar(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0o644}
+		switch {
+		case entry.dir:
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0o755
+		case entry.linkname != "":
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = entry.linkname
+		default:
+			header.Typeflag = tar.TypeReg
+			header.Size = int64(len(entry.body))
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(entry.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTar(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		wantErr string
+		files   map[string]string
+	}{
+		{
+			name: "regular files and contained symlink",
+			entries: []tarEntry{
+				{name: "template.yaml", body: "name: t\n"},
+				{name: "src", dir: true},
+				{name: "src/main.go", body: "package main\n"},
+				{name: "link.go", linkname: "src/main.go"},
+			},
+			files: map[string]string{"template.yaml": "name: t\n", "link.go": "package main\n"},
+		},
+		{
+			name:    "path traversal",
+			entries: []tarEntry{{name: "../evil.txt", body: "x"}},
+			wantErr: "escapes the template directory",
+		},
+		{
+			name:    "absolute symlink",
+			entries: []tarEntry{{name: "link", linkname: "/etc"}},
+			wantErr: "points outside",
+		},
+		{
+			name:    "relative symlink leaving dst",
+			entries: []tarEntry{{name: "sub/link", linkname: "../../outside"}},
+			wantErr: "points outside",
+		},
+		{
+			name: "write through symlinked parent",
+			entries: []tarEntry{
+				{name: "dir", dir: true},
+				{name: "link", linkname: "dir"},
+				{name: "link/evil.txt", body: "x"},
+			},
+			wantErr: "written through symlink",
+		},
+		{
+			name: "regular file replaces symlink",
+			entries: []tarEntry{
+				{name: "a.txt", body: "a"},
+				{name: "b.txt", linkname: "a.txt"},
+				{name: "b.txt", body: "b"},
+			},
+			files: map[string]string{"a.txt": "a", "b.txt": "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			dst := filepath.Join(root, "dst")
+			if err := os.Mkdir(dst, 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			err := extractTar(buildTar(t, tt.entries), dst)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("extractTar() error = %v, want %q", err, tt.wantErr)
+				}
+				if _, err := os.Stat(filepath.Join(root, "evil.txt")); err == nil {
+					t.Fatal("file was written outside the extraction root")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractTar() error = %v", err)
+			}
+			for name, want := range tt.files {
+				data, err := os.ReadFile(filepath.Join(dst, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != want {
+					t.Errorf("%s = %q, want %q", name, data, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseOCIReference(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    ociReference
+		wantErr bool
+	}{
+		{source: "oci://ghcr.io/me/tpl", want: ociReference{registry: "ghcr.io", repository: "me/tpl", reference: "latest"}},
+		{source: "oci://ghcr.io/me/tpl:v2", want: ociReference{registry: "ghcr.io", repository: "me/tpl", reference: "v2"}},
+		{source: "oci://ghcr.io/me/tpl@sha256:abc", want: ociReference{registry: "ghcr.io", repository: "me/tpl", reference: "sha256:abc"}},
+		{source: "oci://localhost:5000/tpl", want: ociReference{registry: "localhost:5000", repository: "tpl", reference: "latest"}},
+		{source: "oci://ghcr.io", wantErr: true},
+		{source: "oci:///tpl", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got, err := parseOCIReference(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOCIReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseOCIReference() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeRegistry 以 httptest 提供 OCI Distribution API；auth 為 true 時要求匿名 Bearer token
+func fakeRegistry(t *testing.T, layers []ociDescriptor, blobs map[string][]byte, auth bool) string {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, `{"token":"anonymous"}`)
+			return
+		}
+		if auth && r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:me/tpl:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/me/tpl/manifests/"):
+			if r.Header.Get("Accept") != ociManifestMediaType {
+				t.Errorf("manifest Accept = %q", r.Header.Get("Accept"))
+			}
+			json.NewEncoder(w).Encode(ociManifest{Layers: layers})
+		case strings.HasPrefix(r.URL.Path, "/v2/me/tpl/blobs/"):
+			blob, ok := blobs[strings.TrimPrefix(r.URL.Path, "/v2/me/tpl/blobs/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(blob)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestOCIFetcherFetch(t *testing.T) {
+	archive := buildTar(t, []tarEntry{
+		{name: "template.yaml", body: "name: oci\n"},
+		{name: "src", dir: true},
+		{name: "src/main.go", body: "package main\n"},
+	}).Bytes()
+	readme := []byte("# from a file layer\n")
+	tampered := digestOf([]byte("tampered"))
+	blobs := map[string][]byte{digestOf(archive): archive, digestOf(readme): readme, tampered: readme}
+	layers := []ociDescriptor{
+		{MediaType: "application/vnd.oci.image.layer.v1.tar", Digest: digestOf(archive)},
+		{MediaType: "text/markdown", Digest: digestOf(readme), Annotations: map[string]string{ociTitleAnnotation: "README.md"}},
+	}
+
+	tests := []struct {
+		name    string
+		layers  []ociDescriptor
+		auth    bool
+		wantErr string
+	}{
+		{name: "tar and file layers", layers: layers},
+		{name: "anonymous token", layers: layers, auth: true},
+		{name: "no layers", wantErr: "has no layers"},
+		{
+			name:    "digest mismatch",
+			layers:  []ociDescriptor{{MediaType: "text/plain", Digest: tampered, Annotations: map[string]string{ociTitleAnnotation: "x"}}},
+			wantErr: "digest mismatch",
+		},
+		{
+			name:    "missing blob",
+			layers:  []ociDescriptor{{MediaType: "text/plain", Digest: digestOf([]byte("other")), Annotations: map[string]string{ociTitleAnnotation: "x"}}},
+			wantErr: "404",
+		},
+		{
+			name:    "file layer without title",
+			layers:  []ociDescriptor{{MediaType: "text/markdown", Digest: digestOf(readme)}},
+			wantErr: "has no " + ociTitleAnnotation,
+		},
+		{
+			name:    "title escaping the template",
+			layers:  []ociDescriptor{{MediaType: "text/markdown", Digest: digestOf(readme), Annotations: map[string]string{ociTitleAnnotation: "../README.md"}}},
+			wantErr: "escapes the template directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := fakeRegistry(t, tt.layers, blobs, tt.auth)
+			dst := filepath.Join(t.TempDir(), "dst")
+			if err := os.Mkdir(dst, 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			err := (&ociFetcher{client: http.DefaultClient}).Fetch("oci://"+registry+"/me/tpl:v1", dst)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Fetch() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			for name, want := range map[string]string{"template.yaml": "name: oci\n", "src/main.go": "package main\n", "README.md": string(readme)} {
+				data, err := os.ReadFile(filepath.Join(dst, name))
+				if err != nil || string(data) != want {
+					t.Errorf("%s = %q, %v; want %q", name, data, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyDigest(t *testing.T) {
+	data := []byte("layer")
+	tests := []struct {
+		name    string
+		digest  string
+		wantErr string
+	}{
+		{name: "match", digest: digestOf(data)},
+		{name: "mismatch", digest: digestOf([]byte("other")), wantErr: "digest mismatch"},
+		{name: "unsupported algorithm", digest: "sha512:abc", wantErr: "unsupported digest"},
+		{name: "malformed", digest: "abc", wantErr: "unsupported digest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyDigest(data, tt.digest)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("verifyDigest() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("verifyDigest() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
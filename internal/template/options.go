@@ -0,0 +1,99 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// optionsTimeout 為 optionsFrom 命令的執行時限，避免環境問題讓提示卡住
+const optionsTimeout = 10 * time.Second
+
+// runOptionsCommand 以 sh -c 在 dir 執行 optionsFrom 命令並回傳 stdout，測試時可替換
+var runOptionsCommand = func(command, dir string) ([]byte, error) {
+	return commandOutput(command, dir, optionsTimeout)
+}
+
+// commandOutput 以 sh -c 在 dir 執行 command 並回傳 stdout；逾時或失敗時錯誤包含 stderr
+func commandOutput(command, dir string, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	// sh 被終止後，仍在執行的子程序可能保持 stdout 開啟；WaitDelay 讓逾時確實生效
+	cmd.WaitDelay = time.Second
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		if text := strings.TrimSpace(stderr.String()); text != "" {
+			return nil, fmt.Errorf("%w: %s", err, text)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// dynamicOptions 執行 select 變數的 optionsFrom 命令（可使用先前收集的變數），以 stdout 的每個非空白行作為選項
+func (g *Generator) dynamicOptions(variable TemplateVar, vars map[string]interface{}) ([]string, error) {
+	command := g.processCommandTemplate(variable.OptionsFrom, vars)
+	output, err := runOptionsCommand(command, g.WorkDir)
+	if err != nil {
+		return nil, newDetailError(ErrInvalidVariable, "failed to list options for variable '%s' (optionsFrom %q): %v", variable.Name, command, err)
+	}
+
+	var options []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if option := strings.TrimSpace(line); option != "" {
+			options = append(options, option)
+		}
+	}
+	if len(options) == 0 {
+		return nil, newDetailError(ErrInvalidVariable, "optionsFrom for variable '%s' produced no options", variable.Name)
+	}
+	g.trace("options", map[string]interface{}{"name": variable.Name, "command": command, "options": options})
+	return options, nil
+}
+
+// fileOptions 讀取 select 變數的 optionsFile（允許清單），每個非空白、非 # 註解的行為一個選項。
+// 路徑可使用先前的變數與 ~/ 開頭，相對路徑以 WorkDir（未設定時為目前工作目錄）為基準
+func (g *Generator) fileOptions(variable TemplateVar, vars map[string]interface{}) ([]string, error) {
+	name, err := renderText(variable.OptionsFile, vars)
+	if err != nil {
+		return nil, newDetailError(ErrInvalidVariable, "invalid optionsFile for variable '%s': %v", variable.Name, err)
+	}
+	name = strings.TrimSpace(name)
+	if rest, ok := strings.CutPrefix(name, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			name = filepath.Join(home, rest)
+		}
+	}
+
+	data, err := os.ReadFile(resolveIn(g.WorkDir, name))
+	if err != nil {
+		return nil, newDetailError(ErrInvalidVariable, "failed to read options for variable '%s': %v", variable.Name, err)
+	}
+
+	var options []string
+	for _, line := range strings.Split(string(data), "\n") {
+		option := strings.TrimSpace(line)
+		if option != "" && !strings.HasPrefix(option, "#") {
+			options = append(options, option)
+		}
+	}
+	if len(options) == 0 {
+		return nil, newDetailError(ErrInvalidVariable, "optionsFile %s for variable '%s' lists no options", name, variable.Name)
+	}
+	g.trace("options", map[string]interface{}{"name": variable.Name, "file": name, "options": options})
+	return options, nil
+}
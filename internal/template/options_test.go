@@ -0,0 +1,216 @@
+package template
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptionsFrom(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: drivers
+variables:
+  - name: Driver
+    type: select
+    required: true
+    optionsFrom: "list-drivers --project {{ .ProjectName }}"
+`,
+		"driver.txt.tmpl": "{{ .Driver }}\n",
+	}
+
+	tests := []struct {
+		name        string
+		output      string
+		commandErr  error
+		values      map[string]string
+		input       string
+		noInput     bool
+		want        string
+		wantErr     string
+		wantCommand bool
+	}{
+		{
+			name:        "--set value among the options",
+			output:      "postgres\nmysql\n\n",
+			values:      map[string]string{"Driver": "mysql"},
+			want:        "mysql",
+			wantCommand: true,
+		},
+		{
+			name:        "prompted value among the options",
+			output:      "  postgres  \nmysql\n",
+			input:       "postgres\n",
+			want:        "postgres",
+			wantCommand: true,
+		},
+		{
+			name:        "value not among the options",
+			output:      "postgres\nmysql\n",
+			values:      map[string]string{"Driver": "sqlite"},
+			wantErr:     "invalid value 'sqlite' for variable 'Driver'",
+			wantCommand: true,
+		},
+		{
+			name:        "command fails",
+			commandErr:  errors.New("exit status 1: drivers unavailable"),
+			values:      map[string]string{"Driver": "mysql"},
+			wantErr:     "failed to list options for variable 'Driver'",
+			wantCommand: true,
+		},
+		{
+			name:        "command prints nothing",
+			output:      "\n  \n",
+			values:      map[string]string{"Driver": "mysql"},
+			wantErr:     "produced no options",
+			wantCommand: true,
+		},
+		{
+			name:        "--no-input runs the command to check the given value",
+			output:      "postgres\nmysql\n",
+			noInput:     true,
+			values:      map[string]string{"Driver": "mysql"},
+			want:        "mysql",
+			wantCommand: true,
+		},
+		{
+			name:        "--no-input rejects a value not among the options",
+			output:      "postgres\nmysql\n",
+			noInput:     true,
+			values:      map[string]string{"Driver": "sqlite"},
+			wantErr:     "invalid value 'sqlite' for variable 'Driver'",
+			wantCommand: true,
+		},
+		{
+			name:    "--no-input without a value",
+			noInput: true,
+			wantErr: "--no-input can't prompt for it",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.NoInput = tt.noInput
+			generator.Values = tt.values
+			generator.Input = strings.NewReader(tt.input)
+			generator.Output = io.Discard
+
+			var commands []string
+			previous := runOptionsCommand
+			runOptionsCommand = func(command, dir string) ([]byte, error) {
+				if dir != generator.WorkDir {
+					t.Errorf("optionsFrom ran in %s, want %s", dir, generator.WorkDir)
+				}
+				commands = append(commands, command)
+				return []byte(tt.output), tt.commandErr
+			}
+			t.Cleanup(func() { runOptionsCommand = previous })
+
+			_, err := generator.Generate("app", name)
+			if ran := len(commands) > 0; ran != tt.wantCommand {
+				t.Errorf("optionsFrom ran = %v, want %v", ran, tt.wantCommand)
+			}
+			if tt.wantCommand && commands[0] != "list-drivers --project app" {
+				t.Errorf("optionsFrom command = %q, want the rendered command", commands[0])
+			}
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidVariable) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want ErrInvalidVariable containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(generator.WorkDir, "app", "driver.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(data)); got != tt.want {
+				t.Errorf("Driver = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    *string
+		value   string
+		wantErr string
+	}{
+		{name: "listed value", list: ptr("# allowed regions\nus-east-1\n\neu-west-1\n"), value: "eu-west-1"},
+		{name: "comment is not an option", list: ptr("# us-west-2\nus-east-1\n"), value: "# us-west-2", wantErr: "invalid value"},
+		{name: "unlisted value", list: ptr("us-east-1\n"), value: "ap-south-1", wantErr: "invalid value"},
+		{name: "empty list", list: ptr("# nothing yet\n"), value: "us-east-1", wantErr: "lists no options"},
+		{name: "missing file", value: "us-east-1", wantErr: "failed to read options"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml": "name: regions\nvariables:\n  - name: Region\n    type: select\n    optionsFile: \"{{ .ProjectName }}-regions.txt\"\n",
+				"main.go":       "package main\n",
+			})
+			generator := newTestGenerator(t, manager)
+			generator.Values = map[string]string{"Region": tt.value}
+			if tt.list != nil {
+				// 相對路徑以 WorkDir 為基準，且可使用變數
+				writeFiles(t, generator.WorkDir, map[string]string{"app-regions.txt": *tt.list})
+			}
+
+			_, err := generator.Generate("app", name)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidVariable) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want ErrInvalidVariable containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestCommandOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		timeout time.Duration
+		want    string
+		wantErr string
+	}{
+		{name: "stdout only", command: "echo a; echo b; echo ignored >&2", timeout: 5 * time.Second, want: "a\nb\n"},
+		{name: "failure includes stderr", command: "echo no drivers >&2; exit 3", timeout: 5 * time.Second, wantErr: "exit status 3: no drivers"},
+		{name: "timeout", command: "sleep 5", timeout: 100 * time.Millisecond, wantErr: "timed out after 100ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := commandOutput(tt.command, t.TempDir(), tt.timeout)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("commandOutput() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("commandOutput() error = %v", err)
+			}
+			if string(output) != tt.want {
+				t.Errorf("commandOutput() = %q, want %q", output, tt.want)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string { return &s }
@@ -0,0 +1,120 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	appDirName    = "aaa-generator"
+	legacyDirName = ".go-react-generator"
+)
+
+// resolveIn 將相對路徑解析到 base 之下；base 為空或 path 為絕對路徑時原樣回傳
+func resolveIn(base, path string) string {
+	if base == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(base, path)
+}
+
+// UserTemplatesDir 回傳用戶模板的存放目錄。
+// Linux 上依序使用 $XDG_DATA_HOME、~/.local/share；
+// 若新位置尚不存在而舊的 ~/.go-react-generator 存在，則沿用舊位置以保持相容。
+func UserTemplatesDir() (string, error) {
+	dir, err := resolveUserDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// ConfigDir 回傳用戶設定檔的存放目錄。
+// Linux 上依序使用 $XDG_CONFIG_HOME、~/.config，並同樣回退到舊位置。
+func ConfigDir() (string, error) {
+	return resolveUserDir("XDG_CONFIG_HOME", ".config")
+}
+
+func legacyDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, legacyDirName), nil
+}
+
+func xdgDir(envVar, homeFallback string) (string, error) {
+	if base := os.Getenv(envVar); filepath.IsAbs(base) {
+		return filepath.Join(base, appDirName), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, homeFallback, appDirName), nil
+}
+
+func resolveUserDir(envVar, homeFallback string) (string, error) {
+	legacy, err := legacyDir()
+	if err != nil {
+		return "", err
+	}
+
+	if runtime.GOOS != "linux" {
+		return legacy, nil
+	}
+
+	dir, err := xdgDir(envVar, homeFallback)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if info, err := os.Stat(legacy); err == nil && info.IsDir() {
+			return legacy, nil
+		}
+	}
+
+	return dir, nil
+}
+
+// migrateLegacyTemplates 在舊的 ~/.go-react-generator/templates 存在而新的 XDG 位置
+// 尚未建立時，將模板搬移到新位置。已完成搬移（或無需搬移）時不做任何事。
+func migrateLegacyTemplates() (from, to string, err error) {
+	if runtime.GOOS != "linux" {
+		return "", "", nil
+	}
+
+	legacy, err := legacyDir()
+	if err != nil {
+		return "", "", err
+	}
+	from = filepath.Join(legacy, "templates")
+	if info, err := os.Stat(from); err != nil || !info.IsDir() {
+		return "", "", nil
+	}
+
+	dataDir, err := xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(dataDir); !os.IsNotExist(err) {
+		return "", "", nil
+	}
+	to = filepath.Join(dataDir, "templates")
+
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return "", "", err
+	}
+
+	if err := os.Rename(from, to); err != nil {
+		// 跨檔案系統時無法 rename，改為複製（保留舊目錄）
+		if err := copyDir(from, to, false); err != nil {
+			return "", "", err
+		}
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,167 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUserDirs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG base directories are only used on Linux")
+	}
+
+	tests := []struct {
+		name          string
+		dataHome      string
+		configHome    string
+		legacy        bool
+		wantTemplates string
+		wantConfig    string
+	}{
+		{
+			name:          "XDG variables",
+			dataHome:      "/xdg/data",
+			configHome:    "/xdg/config",
+			wantTemplates: "/xdg/data/aaa-generator/templates",
+			wantConfig:    "/xdg/config/aaa-generator",
+		},
+		{
+			name:          "home fallbacks",
+			wantTemplates: "HOME/.local/share/aaa-generator/templates",
+			wantConfig:    "HOME/.config/aaa-generator",
+		},
+		{
+			name:          "relative XDG variables are ignored",
+			dataHome:      "data",
+			configHome:    "config",
+			wantTemplates: "HOME/.local/share/aaa-generator/templates",
+			wantConfig:    "HOME/.config/aaa-generator",
+		},
+		{
+			name:          "legacy directory when XDG is not created",
+			legacy:        true,
+			wantTemplates: "HOME/.go-react-generator/templates",
+			wantConfig:    "HOME/.go-react-generator",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_DATA_HOME", tt.dataHome)
+			t.Setenv("XDG_CONFIG_HOME", tt.configHome)
+			if tt.legacy {
+				if err := os.Mkdir(filepath.Join(home, legacyDirName), 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expand := func(path string) string {
+				if rel, ok := strings.CutPrefix(path, "HOME/"); ok {
+					return filepath.Join(home, rel)
+				}
+				return path
+			}
+
+			templates, err := UserTemplatesDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := expand(tt.wantTemplates); templates != want {
+				t.Errorf("UserTemplatesDir() = %q, want %q", templates, want)
+			}
+			config, err := ConfigDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := expand(tt.wantConfig); config != want {
+				t.Errorf("ConfigDir() = %q, want %q", config, want)
+			}
+		})
+	}
+}
+
+func TestMigrateLegacyTemplates(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("legacy templates are only migrated on Linux")
+	}
+
+	tests := []struct {
+		name         string
+		legacy       bool
+		xdgExists    bool
+		wantMigrated bool
+	}{
+		{name: "nothing to migrate"},
+		{name: "legacy templates moved", legacy: true, wantMigrated: true},
+		{name: "XDG directory already exists", legacy: true, xdgExists: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			dataHome := filepath.Join(home, "data")
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_DATA_HOME", dataHome)
+			legacyTemplates := filepath.Join(home, legacyDirName, "templates")
+			if tt.legacy {
+				writeFiles(t, legacyTemplates, map[string]string{"old/template.yaml": "name: old\n"})
+			}
+			if tt.xdgExists {
+				if err := os.MkdirAll(filepath.Join(dataHome, appDirName), 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			from, to, err := migrateLegacyTemplates()
+			if err != nil {
+				t.Fatalf("migrateLegacyTemplates() error = %v", err)
+			}
+			wantTo := filepath.Join(dataHome, appDirName, "templates")
+			if !tt.wantMigrated {
+				if from != "" || to != "" {
+					t.Errorf("migrateLegacyTemplates() = (%q, %q), want no migration", from, to)
+				}
+				return
+			}
+			if from != legacyTemplates || to != wantTo {
+				t.Errorf("migrateLegacyTemplates() = (%q, %q), want (%q, %q)", from, to, legacyTemplates, wantTo)
+			}
+			if _, err := os.Stat(filepath.Join(wantTo, "old", "template.yaml")); err != nil {
+				t.Errorf("template not migrated: %v", err)
+			}
+			if _, err := os.Stat(legacyTemplates); !os.IsNotExist(err) {
+				t.Errorf("legacy templates still present: %v", err)
+			}
+
+			// 第二次呼叫時 XDG 目錄已存在，不再搬移
+			if from, to, err := migrateLegacyTemplates(); err != nil || from != "" || to != "" {
+				t.Errorf("second migrateLegacyTemplates() = (%q, %q, %v), want no-op", from, to, err)
+			}
+		})
+	}
+}
+
+func TestResolveIn(t *testing.T) {
+	tests := []struct {
+		base string
+		path string
+		want string
+	}{
+		{path: "app", want: "app"},
+		{base: "/work", path: "app", want: filepath.Join("/work", "app")},
+		{base: "/work", path: "../app", want: filepath.Join("/", "app")},
+		{base: "/work", path: "/abs/app", want: "/abs/app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.base+" "+tt.path, func(t *testing.T) {
+			if got := resolveIn(tt.base, tt.path); got != tt.want {
+				t.Errorf("resolveIn(%q, %q) = %q, want %q", tt.base, tt.path, got, tt.want)
+			}
+		})
+	}
+}
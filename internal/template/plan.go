@@ -0,0 +1,62 @@
+package template
+
+import (
+	"fmt"
+)
+
+// Plan 收集變數並在記憶體中渲染模板，回傳實際產生時會寫入的 manifest（檔案、變數與模板版本），
+// 不建立專案目錄也不執行 post-generate 命令。用於在正式產生前先審查將產生的內容；
+// 與 Generate 一樣檢查 requiredEnv，並包含 GenReadme 會補上的 README.md。
+// Plan 不寫入任何檔案也不啟動任何程序：optionsFrom 變數需直接提供值，dataCommand 不執行，--check-module 的模組快取檢查會略過
+func (g *Generator) Plan(projectName, templateName string) (*Manifest, error) {
+	g.planning = true
+	defer func() { g.planning = false }()
+
+	tmpl, err := g.manager.GetTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.checkGeneratorVersion(tmpl, templateName); err != nil {
+		return nil, err
+	}
+	if err := g.checkDeprecated(tmpl, templateName); err != nil {
+		return nil, err
+	}
+	if err := checkRequiredEnv(tmpl, templateName); err != nil {
+		return nil, err
+	}
+
+	config, err := withEnvDefaults(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := g.collectVariables(config, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if g.PrintVars {
+		printVariables(g.status(), g.resolvedVariables(config, vars))
+	}
+
+	if err := g.checkGoNames(config, vars); err != nil {
+		return nil, err
+	}
+
+	if g.fileMode, _, err = g.permissions(tmpl.Config); err != nil {
+		return nil, err
+	}
+
+	sink := NewMemorySink()
+	stats, err := g.generateFiles(tmpl, sink, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render files: %w", err)
+	}
+	if g.shouldGenerateReadme(tmpl.Config) {
+		if err := g.generateReadme(sink, g.projectDir(projectName), tmpl, vars, &stats); err != nil {
+			return nil, err
+		}
+	}
+	return newManifest(tmpl, vars, stats.files, stats.appended), nil
+}
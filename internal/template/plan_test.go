@@ -0,0 +1,197 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: plan
+version: 2.0.0
+variables:
+  - name: Docker
+    type: bool
+    default: "false"
+  - name: DB
+    type: select
+    options: [postgres, none]
+    default: none
+files:
+  - source: main.go.tmpl
+    type: file
+  - source: Dockerfile
+    type: file
+    condition: '{{ eq .Docker "true" }}'
+postGenerate:
+  - command: touch ran.txt
+`,
+		"main.go.tmpl": "package {{ .ProjectName }}\n",
+		"Dockerfile":   "FROM scratch\n",
+	}
+
+	tests := []struct {
+		name      string
+		values    map[string]string
+		template  string
+		wantFiles []string
+		wantErr   error
+	}{
+		{name: "default values", wantFiles: []string{"main.go"}},
+		{name: "conditional file enabled", values: map[string]string{"Docker": "true"}, wantFiles: []string{"Dockerfile", "main.go"}},
+		{name: "invalid value", values: map[string]string{"DB": "mysql"}, wantErr: ErrInvalidVariable},
+		{name: "unknown template", template: "missing", wantErr: ErrTemplateNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			if tt.template != "" {
+				name = tt.template
+			}
+			generator := newTestGenerator(t, manager)
+			generator.Values = tt.values
+
+			manifest, err := generator.Plan("app", name)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Plan() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if manifest.Template != "plan" || manifest.Version != "2.0.0" || manifest.Variables["ProjectName"] != "app" {
+				t.Errorf("manifest = %+v", manifest)
+			}
+			if !reflect.DeepEqual(manifest.Files, tt.wantFiles) {
+				t.Errorf("Files = %q, want %q", manifest.Files, tt.wantFiles)
+			}
+			if _, err := os.Stat(filepath.Join(generator.WorkDir, "app")); !os.IsNotExist(err) {
+				t.Errorf("Plan() created the project directory: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(generator.WorkDir, "ran.txt")); !os.IsNotExist(err) {
+				t.Errorf("Plan() ran post-generate commands: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlanMatchesGenerate(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": "name: plan\nrequiredEnv: [PLAN_TOKEN]\nnextSteps:\n  - make dev\n",
+		"main.go":       "package main\n",
+	}
+
+	tests := []struct {
+		name      string
+		env       string
+		genReadme bool
+		wantErr   error
+	}{
+		{name: "plain", env: "set"},
+		// --gen-readme 補上的 README.md 也列在 manifest 中
+		{name: "gen-readme", env: "set", genReadme: true},
+		{name: "required environment variable missing", wantErr: ErrMissingEnv},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PLAN_TOKEN", tt.env)
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.GenReadme = tt.genReadme
+
+			planned, err := generator.Plan("app", name)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Plan() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+
+			captureStdout(t, func() {
+				if _, err := generator.Generate("app", name); err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+			})
+			saved, err := LoadManifest(filepath.Join(generator.WorkDir, "app"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(planned.Files, saved.Files) {
+				t.Errorf("planned files = %q, generated %q", planned.Files, saved.Files)
+			}
+		})
+	}
+}
+
+func TestPlanStartsNoProcesses(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: planned
+variables:
+  - name: Driver
+    type: select
+    required: true
+    optionsFrom: list-drivers
+`,
+		"driver.txt.tmpl": "{{ .Driver }}\n",
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr string
+	}{
+		{name: "value given with --set", values: map[string]string{"Driver": "sqlite"}},
+		{name: "value missing", wantErr: "which --dry-run doesn't run"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.NoInput = false
+			generator.Values = tt.values
+
+			previous := runOptionsCommand
+			runOptionsCommand = func(command, dir string) ([]byte, error) {
+				t.Errorf("optionsFrom command %q ran during Plan", command)
+				return nil, nil
+			}
+			t.Cleanup(func() { runOptionsCommand = previous })
+
+			manifest, err := generator.Plan("app", name)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidVariable) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Plan() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if manifest.Variables["Driver"] != "sqlite" {
+				t.Errorf("Driver = %v, want sqlite", manifest.Variables["Driver"])
+			}
+			if generator.planning {
+				t.Error("planning still set after Plan()")
+			}
+			entries, err := os.ReadDir(generator.WorkDir)
+			if err != nil || len(entries) != 0 {
+				t.Errorf("Plan() wrote to the work dir: %v, %v", entries, err)
+			}
+		})
+	}
+}
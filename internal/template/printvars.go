@@ -0,0 +1,59 @@
+package template
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// secretMask 為 secret 變數在輸出中的顯示內容
+const secretMask = "********"
+
+// ResolvedVariable 為 PrintVars 輸出的一個變數：最終值（secret 變數為遮罩）與來源
+type ResolvedVariable struct {
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+	secret bool
+}
+
+// resolvedVariables 回傳變數的最終值與來源，標記為 secret 的變數只保留遮罩
+func (g *Generator) resolvedVariables(config *TemplateConfig, vars map[string]interface{}) map[string]ResolvedVariable {
+	secret := make(map[string]bool)
+	if config != nil {
+		for _, variable := range config.Variables {
+			secret[variable.Name] = variable.Secret
+		}
+	}
+
+	resolved := make(map[string]ResolvedVariable, len(vars))
+	for name, value := range vars {
+		if secret[name] {
+			value = secretMask
+		}
+		source := g.varSources[name]
+		if source == "" {
+			source = "unknown"
+		}
+		resolved[name] = ResolvedVariable{Value: value, Source: source, secret: secret[name]}
+	}
+	return resolved
+}
+
+// printVariables 依名稱排序輸出 resolvedVariables 的內容
+func printVariables(out io.Writer, resolved map[string]ResolvedVariable) {
+	names := make([]string, 0, len(resolved))
+	for name := range resolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(out, "📝 Resolved variables:")
+	for _, name := range names {
+		variable := resolved[name]
+		value := fmt.Sprintf("%q", fmt.Sprint(variable.Value))
+		if variable.secret {
+			value = secretMask
+		}
+		fmt.Fprintf(out, "   • %s = %s (%s)\n", name, value, variable.Source)
+	}
+}
@@ -0,0 +1,80 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrintVars(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: vars
+variables:
+  - name: Port
+    default: "8080"
+  - name: Token
+    default: hunter2
+    secret: true
+`,
+		"main.go": "package main\n",
+	}
+
+	tests := []struct {
+		name       string
+		values     map[string]string
+		variable   string
+		want       ResolvedVariable
+		wantOutput string
+	}{
+		{
+			name:       "default",
+			variable:   "Port",
+			want:       ResolvedVariable{Value: "8080", Source: "default"},
+			wantOutput: `Port = "8080" (default)`,
+		},
+		{
+			name:       "--set override wins over the default",
+			values:     map[string]string{"Port": "9090"},
+			variable:   "Port",
+			want:       ResolvedVariable{Value: "9090", Source: "set"},
+			wantOutput: `Port = "9090" (set)`,
+		},
+		{
+			name:       "secret is masked",
+			values:     map[string]string{"Token": "s3cret"},
+			variable:   "Token",
+			want:       ResolvedVariable{Value: secretMask, Source: "set", secret: true},
+			wantOutput: "Token = " + secretMask + " (set)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.Values = tt.values
+			generator.PrintVars = true
+
+			var result *GenerateResult
+			var err error
+			output := captureStdout(t, func() { result, err = generator.Generate("app", name) })
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			if !strings.Contains(output, tt.wantOutput) {
+				t.Errorf("output missing %q:\n%s", tt.wantOutput, output)
+			}
+			if strings.Contains(output, "s3cret") || strings.Contains(output, "hunter2") {
+				t.Errorf("secret value printed:\n%s", output)
+			}
+			summary, err := result.Summary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := summary.Variables[tt.variable]; got != tt.want {
+				t.Errorf("summary variable %s = %+v, want %+v", tt.variable, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// readmeFileName 為自動產生的專案說明檔名稱
+const readmeFileName = "README.md"
+
+// readmeTemplate 為 --gen-readme 產生的 README.md 內容，以模板函式渲染
+const readmeTemplate = `# {{ .ProjectName }}
+
+Generated from the ` + "`{{ .Template.Name }}`" + ` template{{ with .Template.Version }} (version {{ . }}){{ end }}.
+{{- with .Template.Description }}
+
+{{ . }}
+{{- end }}
+{{- if .Variables }}
+
+## Variables
+
+| Name | Value |
+| --- | --- |
+{{- range .Variables }}
+| {{ .Name }} | {{ .Value }} |
+{{- end }}
+{{- end }}
+{{- if .NextSteps }}
+
+## Next Steps
+{{ range .NextSteps }}
+- {{ . }}
+{{- end }}
+{{- end }}
+`
+
+// readmeVariable 為 README 變數表格中的一列；secret 變數的值以遮罩顯示
+type readmeVariable struct {
+	Name  string
+	Value string
+}
+
+// shouldGenerateReadme 回傳這次產生是否要補上 README.md：Generator.GenReadme 或模板的 generateReadme: true
+func (g *Generator) shouldGenerateReadme(config *TemplateConfig) bool {
+	return g.GenReadme || (config != nil && config.GenerateReadme)
+}
+
+// generateReadme 在模板沒有產生 README.md、專案目錄中也沒有時，以模板資訊、變數與 nextSteps 寫入 README.md
+func (g *Generator) generateReadme(out Sink, projectDir string, tmpl *Template, vars map[string]interface{}, stats *GenerateStats) error {
+	for _, file := range stats.files {
+		if strings.EqualFold(file, readmeFileName) {
+			return nil
+		}
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, readmeFileName)); err == nil {
+		return nil
+	}
+
+	content, err := renderReadme(tmpl.Config, vars)
+	if err != nil {
+		return err
+	}
+	if err := out.WriteFile(readmeFileName, content, g.fileMode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", readmeFileName, err)
+	}
+	stats.addFile(readmeFileName, "readme")
+	g.trace("readme", map[string]interface{}{"target": readmeFileName})
+	return nil
+}
+
+// renderReadme 渲染 readmeTemplate；ProjectPath 等產生時才加入的變數不列出
+func renderReadme(config *TemplateConfig, vars map[string]interface{}) ([]byte, error) {
+	if config == nil {
+		config = &TemplateConfig{}
+	}
+	secret := make(map[string]bool)
+	for _, variable := range config.Variables {
+		secret[variable.Name] = variable.Secret
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		if name != "ProjectName" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	variables := make([]readmeVariable, 0, len(names))
+	for _, name := range names {
+		// | 會切斷 Markdown 表格的欄位
+		value := "`" + strings.ReplaceAll(fmt.Sprint(vars[name]), "|", `\|`) + "`"
+		if secret[name] {
+			value = secretMask
+		}
+		variables = append(variables, readmeVariable{Name: name, Value: value})
+	}
+
+	var steps []string
+	for _, step := range config.NextSteps {
+		rendered, err := renderText(step, vars)
+		if err != nil {
+			rendered = step
+		}
+		steps = append(steps, strings.TrimSpace(rendered))
+	}
+
+	tmpl, err := template.New("readme").Funcs(templateFuncs()).Parse(readmeTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	data := map[string]interface{}{
+		"ProjectName": vars["ProjectName"],
+		"Template":    config,
+		"Variables":   variables,
+		"NextSteps":   steps,
+	}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", readmeFileName, err)
+	}
+	return buf.Bytes(), nil
+}
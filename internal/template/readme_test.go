@@ -0,0 +1,102 @@
+package template
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderReadme(t *testing.T) {
+	config := &TemplateConfig{
+		Name:        "api",
+		Version:     "1.4.0",
+		Description: "REST API service",
+		Variables:   []TemplateVar{{Name: "Port"}, {Name: "Password", Secret: true}, {Name: "Filter"}},
+		NextSteps:   []string{"cd {{ .ProjectName }}", "make {{ nosuch }}"},
+	}
+	vars := map[string]interface{}{"ProjectName": "shop", "Port": 8080, "Password": "hunter2", "Filter": "a|b"}
+
+	got, err := renderReadme(config, vars)
+	if err != nil {
+		t.Fatalf("renderReadme() error = %v", err)
+	}
+	want := "# shop\n\nGenerated from the `api` template (version 1.4.0).\n\nREST API service\n\n" +
+		"## Variables\n\n| Name | Value |\n| --- | --- |\n" +
+		"| Filter | `a\\|b` |\n| Password | ******** |\n| Port | `8080` |\n\n" +
+		"## Next Steps\n\n- cd shop\n- make {{ nosuch }}\n"
+	if string(got) != want {
+		t.Errorf("renderReadme() =\n%s\nwant\n%s", got, want)
+	}
+
+	minimal, err := renderReadme(nil, map[string]interface{}{"ProjectName": "shop"})
+	if err != nil {
+		t.Fatalf("renderReadme(nil) error = %v", err)
+	}
+	if got, want := string(minimal), "# shop\n\nGenerated from the `` template.\n"; got != want {
+		t.Errorf("renderReadme(nil) = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateReadme(t *testing.T) {
+	const generatedReadme = "# app\n\nGenerated from the `rd` template.\n\n## Variables\n\n| Name | Value |\n| --- | --- |\n| ModuleName | `app` |\n"
+
+	tests := []struct {
+		name       string
+		config     string
+		flag       bool
+		files      map[string]string
+		existing   bool
+		wantReadme string
+	}{
+		{name: "off by default", config: "name: rd\n"},
+		{name: "--gen-readme", config: "name: rd\n", flag: true, wantReadme: generatedReadme},
+		{name: "generateReadme: true", config: "name: rd\ngenerateReadme: true\n", wantReadme: generatedReadme},
+		{name: "template provides one", config: "name: rd\n", flag: true, files: map[string]string{"readme.md": "own\n"}, wantReadme: "own\n"},
+		{name: "project already has one", config: "name: rd\n", flag: true, existing: true, wantReadme: "mine\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"template.yaml": tt.config, "main.go": "package main\n"}
+			for name, content := range tt.files {
+				files[name] = content
+			}
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.GenReadme = tt.flag
+			projectDir := filepath.Join(generator.WorkDir, "app")
+			if tt.existing {
+				writeFiles(t, projectDir, map[string]string{"README.md": "mine\n"})
+				generator.Force = true
+			}
+
+			if _, err := generator.Generate("app", name); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			readme := "README.md"
+			if tt.files != nil {
+				readme = "readme.md"
+			}
+			data, err := fs.ReadFile(os.DirFS(projectDir), readme)
+			if tt.wantReadme == "" {
+				if err == nil {
+					t.Errorf("README.md generated:\n%s", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.wantReadme {
+				t.Errorf("%s = %q, want %q", readme, data, tt.wantReadme)
+			}
+			if tt.files != nil {
+				if _, err := os.Stat(filepath.Join(projectDir, "README.md")); err == nil {
+					t.Error("README.md generated next to the template's readme.md")
+				}
+			}
+		})
+	}
+}
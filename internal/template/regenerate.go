@@ -0,0 +1,102 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RegenerateFile 以專案 manifest 記錄的模板與變數重新渲染單一檔案 file（相對於 projectDir），
+// 只覆寫該檔案，不執行 post-generate 命令。append 規則不會再附加到磁碟上的既有內容，
+// 檔案內容與全新產生時相同。secret 變數不在 manifest 中，須以 Values 或同名的環境變數再次提供
+func (g *Generator) RegenerateFile(projectDir, file string) error {
+	projectDir = resolveIn(g.WorkDir, projectDir)
+	manifest, err := LoadManifest(projectDir)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		return fmt.Errorf("no %s found in %s", ManifestFileName, projectDir)
+	}
+
+	target := path.Clean(strings.TrimPrefix(filepath.ToSlash(file), "./"))
+	if !contains(manifest.Files, target) {
+		return newDetailError(ErrFileNotGenerated, "%s was not generated from template '%s'", target, manifest.Template)
+	}
+
+	vars := make(map[string]interface{}, len(manifest.Variables))
+	for name, value := range manifest.Variables {
+		vars[name] = value
+	}
+	// secret 變數沒有存在 manifest 中，改從 Values（--set）或同名的環境變數取得
+	for _, name := range manifest.Secrets {
+		value, ok := g.Values[name]
+		if !ok {
+			value, ok = os.LookupEnv(name)
+		}
+		if !ok {
+			return newDetailError(ErrInvalidVariable, "variable '%s' is secret and not saved in %s (set it with --set %s=... or the %s environment variable)", name, ManifestFileName, name, name)
+		}
+		vars[name] = value
+	}
+
+	out := &singleFileSink{DiskSink: NewDiskSink(projectDir), path: target}
+	if _, err := g.GenerateTo(manifest.Template, vars, out); err != nil {
+		return err
+	}
+	if !out.written {
+		return newDetailError(ErrFileNotGenerated, "template '%s' no longer produces %s", manifest.Template, target)
+	}
+	return out.flush()
+}
+
+// singleFileSink 只寫入指定的單一路徑，其餘輸出皆忽略。檔案內容先保留在記憶體中，
+// ReadFile 只讀得到這次產生寫入的內容，讓 append 規則從空檔案開始，最後由 flush 覆寫磁碟上的檔案
+type singleFileSink struct {
+	*DiskSink
+	path    string
+	written bool
+	content []byte
+	mode    fs.FileMode
+}
+
+func (s *singleFileSink) WriteFile(name string, data []byte, mode fs.FileMode) error {
+	if name != s.path {
+		return nil
+	}
+	s.written = true
+	s.content = append([]byte{}, data...)
+	s.mode = mode
+	return nil
+}
+
+func (s *singleFileSink) ReadFile(name string) ([]byte, error) {
+	if name != s.path || s.content == nil {
+		return nil, fs.ErrNotExist
+	}
+	return append([]byte(nil), s.content...), nil
+}
+
+// flush 將保留的內容寫入磁碟；目標為符號連結時已由 Symlink 直接建立
+func (s *singleFileSink) flush() error {
+	if s.content == nil {
+		return nil
+	}
+	return s.DiskSink.WriteFile(s.path, s.content, s.mode)
+}
+
+func (s *singleFileSink) Mkdir(name string) error {
+	return nil
+}
+
+func (s *singleFileSink) Symlink(target, name string) error {
+	if name != s.path {
+		return nil
+	}
+	s.written = true
+	s.content = nil
+	return s.DiskSink.Symlink(target, name)
+}
@@ -0,0 +1,133 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegenerateFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		data    string
+		target  string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "append rule is overwritten, not appended again",
+			files: map[string]string{
+				"template.yaml":  "name: regen\nfiles:\n  - source: gitignore.tmpl\n    target: .gitignore\n    append: true\n",
+				"gitignore.tmpl": "node_modules/\n{{ .ProjectName }}.log\n",
+			},
+			target: ".gitignore",
+			want:   "node_modules/\napp.log\n",
+		},
+		{
+			name: "structured dataCommand values keep their types",
+			files: map[string]string{
+				"template.yaml":  "name: regen\ndataCommand: cloud-info\n",
+				"cloud.txt.tmpl": "{{ .Cloud.Account }} {{ index .Ports 1 }} {{ .Port }}\n",
+			},
+			data:   `{"Cloud": {"Account": "acme"}, "Ports": [8080, 8081], "Port": 9000}`,
+			target: "cloud.txt",
+			want:   "acme 8081 9000\n",
+		},
+		{
+			name: "file not in the manifest",
+			files: map[string]string{
+				"template.yaml": "name: regen\n",
+				"a.txt.tmpl":    "a\n",
+			},
+			target:  "other.txt",
+			wantErr: ErrFileNotGenerated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubDataCommand(t, tt.data, nil)
+
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, tt.files)
+			generator := newTestGenerator(t, manager)
+			if _, err := generator.Generate("app", name); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			err := generator.RegenerateFile("app", tt.target)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RegenerateFile() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RegenerateFile() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(generator.WorkDir, "app", filepath.FromSlash(tt.target)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("%s = %q, want %q", tt.target, data, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegenerateSecretVariable(t *testing.T) {
+	files := map[string]string{
+		"template.yaml":  "name: regen\nvariables:\n  - name: Token\n    secret: true\n",
+		"token.txt.tmpl": "{{ .Token }}\n",
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		env     string
+		want    string
+		wantErr error
+	}{
+		{name: "not given again", wantErr: ErrInvalidVariable},
+		{name: "from --set", values: map[string]string{"Token": "rotated"}, want: "rotated\n"},
+		{name: "from the environment", env: "from-env", want: "from-env\n"},
+		{name: "--set wins over the environment", values: map[string]string{"Token": "rotated"}, env: "from-env", want: "rotated\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+			generator.Values = map[string]string{"Token": "hunter2"}
+			if _, err := generator.Generate("app", name); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			if tt.env != "" {
+				t.Setenv("Token", tt.env)
+			}
+			generator.Values = tt.values
+			err := generator.RegenerateFile("app", "token.txt")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RegenerateFile() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RegenerateFile() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(generator.WorkDir, "app", "token.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("token.txt = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
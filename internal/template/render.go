@@ -0,0 +1,56 @@
+package template
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RenderFile 將模板中的單一檔案 sourcePath（相對於模板根目錄，可省略 .tmpl）渲染後寫入 w，
+// 不建立任何檔案也不執行 post-generate 命令。vars 的處理方式與 GenerateFS 相同；
+// 非 .tmpl 檔案原樣輸出
+func (g *Generator) RenderFile(templateName, sourcePath string, vars map[string]interface{}, w io.Writer) error {
+	tmpl, err := g.manager.GetTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	if err := g.checkGeneratorVersion(tmpl, templateName); err != nil {
+		return err
+	}
+
+	name := path.Clean(strings.TrimPrefix(filepath.ToSlash(sourcePath), "./"))
+	if isTemplateMetaFile(tmpl.Config, name) {
+		return fmt.Errorf("%s is template configuration, not a template file", name)
+	}
+	content, err := fs.ReadFile(tmpl.Files, name)
+	if err != nil && !strings.HasSuffix(name, ".tmpl") {
+		if templated, tmplErr := fs.ReadFile(tmpl.Files, name+".tmpl"); tmplErr == nil {
+			name, content, err = name+".tmpl", templated, nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("file %s not found in template '%s': %w", sourcePath, templateName, err)
+	}
+
+	if strings.HasSuffix(name, ".tmpl") && !looksBinary(content) {
+		config, err := withEnvDefaults(tmpl)
+		if err != nil {
+			return err
+		}
+		resolved, err := defaultVariables(config, vars)
+		if err != nil {
+			return err
+		}
+
+		g.seedRandom()
+		if content, err = g.processTemplate(content, strings.TrimSuffix(name, ".tmpl"), resolved); err != nil {
+			return err
+		}
+	}
+
+	_, err = w.Write(content)
+	return err
+}
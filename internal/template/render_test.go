@@ -0,0 +1,61 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderFile(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: render
+variables:
+  - name: Port
+`,
+		"main.go.tmpl":   "package {{ .ProjectName }} // port {{ .Port }}\n",
+		"static.txt":     "{{ not rendered }}\n",
+		"cmd/app.tmpl":   "app {{ .ProjectName | upper }}\n",
+		"broken.tmpl":    "{{ .ProjectName \n",
+		"defaults.env":   "Port=9000\n",
+		"docs/readme.md": "# docs\n",
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		vars    map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{name: "template file", source: "main.go.tmpl", vars: map[string]interface{}{"ProjectName": "demo"}, want: "package demo // port 9000\n"},
+		{name: ".tmpl suffix optional", source: "./main.go", vars: map[string]interface{}{"ProjectName": "demo", "Port": 3000}, want: "package demo // port 3000\n"},
+		{name: "nested path", source: "cmd/app", vars: map[string]interface{}{"ProjectName": "demo"}, want: "app DEMO\n"},
+		{name: "plain file copied as-is", source: "static.txt", want: "{{ not rendered }}\n"},
+		{name: "missing file", source: "nope.go", wantErr: "file nope.go not found in template 'render'"},
+		{name: "template config", source: "template.yaml", wantErr: "is template configuration"},
+		{name: "defaults file", source: "defaults.env", wantErr: "is template configuration"},
+		{name: "parse error", source: "broken.tmpl", wantErr: "failed to parse template broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, files)
+			generator := newTestGenerator(t, manager)
+
+			var out strings.Builder
+			err := generator.RenderFile(name, tt.source, tt.vars, &out)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RenderFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderFile() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("RenderFile() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package template
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// binarySniffLen 為判斷檔案是否為二進位時檢查的位元組數
+const binarySniffLen = 8000
+
+// looksBinary 以內容判斷檔案是否為二進位：前段含有 NUL 位元組或不是合法的 UTF-8
+func looksBinary(content []byte) bool {
+	sniff := content
+	if len(sniff) > binarySniffLen {
+		sniff = sniff[:binarySniffLen]
+		// 截斷處可能切在多位元組字元中間，去掉最後不完整的字元再檢查
+		for i := 1; i < utf8.UTFMax && i <= len(sniff); i++ {
+			if utf8.RuneStart(sniff[len(sniff)-i]) {
+				if !utf8.FullRune(sniff[len(sniff)-i:]) {
+					sniff = sniff[:len(sniff)-i]
+				}
+				break
+			}
+		}
+	}
+	return bytes.IndexByte(sniff, 0) >= 0 || !utf8.Valid(sniff)
+}
+
+// applyReplacements 將規則的 replace 對照表套用到非 .tmpl 檔案的內容。
+// 對照表的值以模板變數渲染（例如 "{{ .ProjectName }}"），鍵則是字面字串；
+// 較長的鍵優先比對，避免被其前綴先取代
+func applyReplacements(content []byte, replace map[string]string, vars map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(replace))
+	for key := range replace {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	pairs := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		value, err := renderText(replace[key], vars)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, key, value)
+	}
+	return []byte(strings.NewReplacer(pairs...).Replace(string(content))), nil
+}
+
+// appendContent 將 addition 附加到 existing 之後（必要時先補上換行）；
+// dedup 時略過 existing 中已存在的行（忽略行尾空白），因此重複產生不會累積相同內容
+func appendContent(existing, addition []byte, dedup bool) []byte {
+	if dedup {
+		present := make(map[string]bool)
+		for _, line := range strings.Split(string(existing), "\n") {
+			present[strings.TrimRight(line, " \t\r")] = true
+		}
+
+		var kept []string
+		for _, line := range strings.SplitAfter(string(addition), "\n") {
+			key := strings.TrimRight(line, " \t\r\n")
+			if line == "" || present[key] {
+				continue
+			}
+			present[key] = true
+			kept = append(kept, line)
+		}
+		addition = []byte(strings.Join(kept, ""))
+	}
+
+	if len(addition) == 0 {
+		return existing
+	}
+	result := append([]byte(nil), existing...)
+	if len(result) > 0 && result[len(result)-1] != '\n' {
+		result = append(result, '\n')
+	}
+	return append(result, addition...)
+}
@@ -0,0 +1,199 @@
+package template
+
+import (
+	"bytes"
+	"io/fs"
+	"testing"
+)
+
+func TestApplyReplacements(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		replace map[string]string
+		vars    map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "literal keys",
+			content: "module example.com/__NAME__ // __NAME__",
+			replace: map[string]string{"__NAME__": "demo"},
+			want:    "module example.com/demo // demo",
+		},
+		{
+			name:    "values are rendered",
+			content: "name: PLACEHOLDER",
+			replace: map[string]string{"PLACEHOLDER": "{{ .ProjectName | upper }}"},
+			vars:    map[string]interface{}{"ProjectName": "demo"},
+			want:    "name: DEMO",
+		},
+		{
+			name:    "longer key wins over its prefix",
+			content: "__APP__ __APP_NAME__",
+			replace: map[string]string{"__APP__": "a", "__APP_NAME__": "b"},
+			want:    "a b",
+		},
+		{
+			name:    "empty key ignored",
+			content: "unchanged",
+			replace: map[string]string{"": "x"},
+			want:    "unchanged",
+		},
+		{
+			name:    "invalid value template",
+			content: "x",
+			replace: map[string]string{"x": "{{ .Missing"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyReplacements([]byte(tt.content), tt.replace, tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyReplacements() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Errorf("applyReplacements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLooksBinary(t *testing.T) {
+	// 多位元組字元跨越檢查長度的邊界時，不應被誤判為不合法的 UTF-8
+	boundary := append(bytes.Repeat([]byte("a"), binarySniffLen-1), []byte("中文")...)
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    bool
+	}{
+		{name: "text", content: []byte("package main\n"), want: false},
+		{name: "empty", content: nil, want: false},
+		{name: "NUL byte", content: []byte("PNG\x00\x01"), want: true},
+		{name: "invalid UTF-8", content: []byte{0xff, 0xfe, 'a'}, want: true},
+		{name: "rune across the sniff boundary", content: boundary, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksBinary(tt.content); got != tt.want {
+				t.Errorf("looksBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateReplace(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: replace
+files:
+  - source: src
+    target: src
+    replace:
+      __OWNER__: rule-owner
+  - source: other.txt
+    type: file
+`,
+		"src/main.go":      "// __OWNER__ __NAME__\n",
+		"src/readme.tmpl":  "__NAME__ {{ .ProjectName }}\n",
+		"src/logo.bin":     "__NAME__\x00",
+		"other.txt":        "__OWNER__ __NAME__\n",
+		"src/nested/a.txt": "__NAME__\n",
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		// 規則的 replace 優先於命令列的 --replace
+		{path: "src/main.go", want: "// rule-owner demo\n"},
+		{path: "src/nested/a.txt", want: "demo\n"},
+		// .tmpl 檔案由模板引擎處理，不套用 replace
+		{path: "src/readme", want: "__NAME__ demo\n"},
+		{path: "src/logo.bin", want: "__NAME__\x00"},
+		{path: "other.txt", want: "cli-owner demo\n"},
+	}
+
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, files)
+	generator := newTestGenerator(t, manager)
+	generator.Replace = map[string]string{"__NAME__": "{{ .ProjectName }}", "__OWNER__": "cli-owner"}
+	output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "demo"})
+	if err != nil {
+		t.Fatalf("GenerateFS() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			data, err := fs.ReadFile(output, tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(data); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		addition string
+		dedup    bool
+		want     string
+	}{
+		{name: "append to empty", addition: "node_modules/\n", want: "node_modules/\n"},
+		{name: "newline added before the addition", existing: "bin/", addition: "dist/\n", want: "bin/\ndist/\n"},
+		{name: "duplicates kept without dedup", existing: "bin/\n", addition: "bin/\n", want: "bin/\nbin/\n"},
+		{name: "dedup skips present lines", existing: "bin/\ndist/  \n", addition: "dist/\nbin/\n.env\n", dedup: true, want: "bin/\ndist/  \n.env\n"},
+		{name: "dedup within the addition", existing: "", addition: "a\na\nb\n", dedup: true, want: "a\nb\n"},
+		{name: "nothing new", existing: "a\n", addition: "a\n", dedup: true, want: "a\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := appendContent([]byte(tt.existing), []byte(tt.addition), tt.dedup)
+			if string(got) != tt.want {
+				t.Errorf("appendContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateAppend(t *testing.T) {
+	files := map[string]string{
+		"template.yaml": `name: ignore
+files:
+  - source: base.gitignore
+    target: .gitignore
+    type: file
+  - source: node.gitignore
+    target: .gitignore
+    type: file
+    append: true
+    dedupLines: true
+`,
+		"base.gitignore": "bin/\n.env\n",
+		"node.gitignore": ".env\nnode_modules/\n",
+	}
+
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, files)
+	generator := newTestGenerator(t, manager)
+	output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "demo"})
+	if err != nil {
+		t.Fatalf("GenerateFS() error = %v", err)
+	}
+	data, err := fs.ReadFile(output, ".gitignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "bin/\n.env\nnode_modules/\n"; got != want {
+		t.Errorf(".gitignore = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package template
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+//go:embed template.schema.json
+var configSchema []byte
+
+// ConfigSchema 回傳 template.yaml 的 JSON Schema
+func ConfigSchema() []byte {
+	return configSchema
+}
+
+// validateSchema 以 JSON Schema 檢查已解析的 YAML 文件結構。
+// 只實作本專案 schema 使用到的子集：type、properties、required、additionalProperties、items、enum。
+func validateSchema(document interface{}) ([]string, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(configSchema, &schema); err != nil {
+		return nil, fmt.Errorf("invalid embedded schema: %w", err)
+	}
+
+	var problems []string
+	validateNode(schema, document, "", &problems)
+	return problems, nil
+}
+
+func validateNode(schema map[string]interface{}, value interface{}, location string, problems *[]string) {
+	at := location
+	if at == "" {
+		at = "(root)"
+	}
+
+	if types, ok := schema["type"]; ok && !matchesSchemaType(types, value) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %v, got %s", at, types, yamlTypeName(value)))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range enum {
+			if fmt.Sprint(option) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*problems = append(*problems, fmt.Sprintf("%s: value %v is not one of %v", at, value, enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, exists := v[fmt.Sprint(name)]; !exists {
+					*problems = append(*problems, fmt.Sprintf("%s: missing required field %q", at, name))
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			child := joinLocation(location, key)
+			propSchema, known := properties[key].(map[string]interface{})
+			if !known {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					*problems = append(*problems, fmt.Sprintf("%s: unknown field", child))
+				}
+				continue
+			}
+			validateNode(propSchema, v[key], child, problems)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateNode(items, item, fmt.Sprintf("%s[%d]", location, i), problems)
+			}
+		}
+	}
+}
+
+func joinLocation(location, key string) string {
+	if location == "" {
+		return key
+	}
+	return location + "." + key
+}
+
+func matchesSchemaType(types interface{}, value interface{}) bool {
+	switch t := types.(type) {
+	case string:
+		return matchesType(t, value)
+	case []interface{}:
+		for _, option := range t {
+			if matchesType(fmt.Sprint(option), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesType(name string, value interface{}) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		_, ok := value.(int)
+		return ok
+	case "number":
+		switch value.(type) {
+		case int, int64, uint64, float64:
+			return true
+		}
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func yamlTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, uint64, float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
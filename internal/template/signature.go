@@ -0,0 +1,86 @@
+package template
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrSignatureInvalid 表示模板簽章驗證失敗
+var ErrSignatureInvalid = errors.New("template signature verification failed")
+
+// signatureSuffix 接在模板設定檔名稱之後即為簽章檔，例如 template.yaml.sig
+const signatureSuffix = ".sig"
+
+// verifyTemplateSignature 以 ed25519 公鑰驗證模板設定檔的分離式簽章；configData 為 signedPayload 的結果。
+// 公鑰與簽章檔可為原始位元組或 base64 編碼。
+func verifyTemplateSignature(configData []byte, sigPath, keyPath string) error {
+	keyData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read verify key: %w", err)
+	}
+	key, err := decodeKeyMaterial(keyData, ed25519.PublicKeySize)
+	if err != nil {
+		return fmt.Errorf("invalid verify key %s: %w", keyPath, err)
+	}
+
+	sigData, err := os.ReadFile(sigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return newDetailError(ErrSignatureInvalid, "template is not signed: %s not found", filepath.Base(sigPath))
+		}
+		return fmt.Errorf("failed to read signature: %w", err)
+	}
+	sig, err := decodeKeyMaterial(sigData, ed25519.SignatureSize)
+	if err != nil {
+		return fmt.Errorf("invalid signature %s: %w", sigPath, err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), configData, sig) {
+		return newDetailError(ErrSignatureInvalid, "signature %s does not match the template config and its imports", sigPath)
+	}
+	return nil
+}
+
+// signedPayload 回傳簽章涵蓋的內容：模板設定檔之後依序接上 imports 列出的每個設定片段，
+// 避免竄改片段（例如加入 postGenerate 命令）而不影響簽章。沒有 imports 時即為設定檔本身
+func signedPayload(configData []byte, files fs.FS) ([]byte, error) {
+	var config struct {
+		Imports []string `yaml:"imports"`
+	}
+	if err := yaml.Unmarshal(configData, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse template config: %w", err)
+	}
+
+	payload := append([]byte{}, configData...)
+	for _, name := range config.Imports {
+		data, err := readConfigFragment(files, name)
+		if err != nil {
+			return nil, err
+		}
+		payload = append(payload, data...)
+	}
+	return payload, nil
+}
+
+func decodeKeyMaterial(data []byte, size int) ([]byte, error) {
+	if len(data) == size {
+		return data, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, fmt.Errorf("expected %d raw bytes or base64", size)
+	}
+	if len(decoded) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(decoded))
+	}
+	return decoded, nil
+}
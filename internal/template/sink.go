@@ -0,0 +1,123 @@
+package template
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing/fstest"
+)
+
+const (
+	defaultFileMode fs.FileMode = 0o644
+	defaultDirMode  fs.FileMode = 0o755
+)
+
+// Sink 為產生結果的寫入目標，路徑皆為相對於專案根目錄、以 / 分隔
+type Sink interface {
+	WriteFile(path string, data []byte, mode fs.FileMode) error
+	Mkdir(path string) error
+}
+
+// SymlinkSink 為可建立符號連結的 Sink；不支援時模板中的連結會被展開為實際內容
+type SymlinkSink interface {
+	Sink
+	Symlink(target, path string) error
+}
+
+// ReadableSink 為可讀回既有檔案的 Sink，供 append 規則附加到已存在的檔案
+type ReadableSink interface {
+	Sink
+	ReadFile(path string) ([]byte, error)
+}
+
+// DiskSink 將檔案寫入 Root 目錄，建立的目錄使用 DirMode 權限
+type DiskSink struct {
+	Root    string
+	DirMode fs.FileMode
+}
+
+func NewDiskSink(root string) *DiskSink {
+	return &DiskSink{Root: root, DirMode: defaultDirMode}
+}
+
+func (s *DiskSink) dirMode() fs.FileMode {
+	if s.DirMode == 0 {
+		return defaultDirMode
+	}
+	return s.DirMode
+}
+
+func (s *DiskSink) path(name string) string {
+	return filepath.Join(s.Root, filepath.FromSlash(name))
+}
+
+func (s *DiskSink) WriteFile(name string, data []byte, mode fs.FileMode) error {
+	target := s.path(name)
+	if err := os.MkdirAll(filepath.Dir(target), s.dirMode()); err != nil {
+		return err
+	}
+	if err := os.WriteFile(target, data, mode); err != nil {
+		return err
+	}
+	// WriteFile 只在建立新檔時套用權限，重新產生時也要更新既有檔案
+	return os.Chmod(target, mode)
+}
+
+func (s *DiskSink) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(s.path(name))
+}
+
+func (s *DiskSink) Mkdir(name string) error {
+	return os.MkdirAll(s.path(name), s.dirMode())
+}
+
+func (s *DiskSink) Symlink(target, name string) error {
+	return createSymlink(target, s.path(name))
+}
+
+// MemorySink 將檔案保存在記憶體中，可透過 FS 讀回
+type MemorySink struct {
+	files fstest.MapFS
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{files: fstest.MapFS{}}
+}
+
+func (s *MemorySink) WriteFile(name string, data []byte, mode fs.FileMode) error {
+	s.files[name] = &fstest.MapFile{Data: append([]byte(nil), data...), Mode: mode}
+	return nil
+}
+
+func (s *MemorySink) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(s.files, name)
+}
+
+func (s *MemorySink) Mkdir(name string) error {
+	if name == "." || name == "" {
+		return nil
+	}
+	s.files[name] = &fstest.MapFile{Mode: fs.ModeDir | defaultDirMode}
+	return nil
+}
+
+func (s *MemorySink) Symlink(target, name string) error {
+	s.files[name] = &fstest.MapFile{Data: []byte(filepath.ToSlash(target)), Mode: fs.ModeSymlink | 0o777}
+	return nil
+}
+
+// Paths 回傳已寫入的所有路徑（含目錄），依字母排序
+func (s *MemorySink) Paths() []string {
+	paths := make([]string, 0, len(s.files))
+	for name := range s.files {
+		paths = append(paths, name)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// FS 回傳目前寫入內容的唯讀檔案系統
+func (s *MemorySink) FS() fs.FS {
+	return s.files
+}
@@ -0,0 +1,291 @@
+package template
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMemorySink(t *testing.T) {
+	sink := NewMemorySink()
+	data := []byte("hello")
+	if err := sink.WriteFile("src/main.go", data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// 寫入後修改呼叫端的 slice 不應影響已保存的內容
+	data[0] = 'j'
+	if err := sink.Mkdir("src"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Mkdir("."); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(sink.Paths(), ","); got != "src,src/main.go" {
+		t.Errorf("Paths() = %s, want src,src/main.go", got)
+	}
+	got, err := sink.ReadFile("src/main.go")
+	if err != nil || string(got) != "hello" {
+		t.Errorf("ReadFile() = %q, %v; want hello", got, err)
+	}
+	info, err := fs.Stat(sink.FS(), "src")
+	if err != nil || !info.IsDir() {
+		t.Errorf("src is not a directory in FS(): %v", err)
+	}
+}
+
+func TestGenerateFS(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: memory
+variables:
+  - name: Owner
+    required: true
+files:
+  - source: README.md.tmpl
+    type: file
+  - source: src
+    target: src
+`,
+		"README.md.tmpl": "# {{ .ProjectName }} by {{ .Owner }}\n",
+		"src/main.go":    "package main\n",
+	})
+	generator := newTestGenerator(t, manager)
+
+	output, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app", "Owner": "me"})
+	if err != nil {
+		t.Fatalf("GenerateFS() error = %v", err)
+	}
+	for path, want := range map[string]string{"README.md": "# app by me\n", "src/main.go": "package main\n"} {
+		data, err := fs.ReadFile(output, path)
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", path, data, err, want)
+		}
+	}
+
+	entries, err := os.ReadDir(generator.WorkDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("GenerateFS() wrote %d entries to the working directory", len(entries))
+	}
+
+	if _, err := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"}); !errors.Is(err, ErrInvalidVariable) {
+		t.Errorf("GenerateFS() without Owner error = %v, want ErrInvalidVariable", err)
+	}
+}
+
+func TestDiskSink(t *testing.T) {
+	root := t.TempDir()
+	sink := NewDiskSink(root)
+
+	if err := sink.WriteFile("a/b/c.txt", []byte("c"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Mkdir("empty/dir"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Symlink("b/c.txt", "a/link.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := sink.ReadFile("a/b/c.txt")
+	if err != nil || string(data) != "c" {
+		t.Errorf("ReadFile() = %q, %v; want c", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "a", "link.txt")); err != nil || string(data) != "c" {
+		t.Errorf("a/link.txt = %q, %v; want c", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(root, "empty", "dir")); err != nil || !info.IsDir() {
+		t.Errorf("empty/dir was not created: %v", err)
+	}
+}
+
+// writeOnlySink 只實作 Sink，用來確認 GenerateTo 不依賴選用的介面
+type writeOnlySink struct {
+	files map[string]string
+	dirs  []string
+}
+
+func (s *writeOnlySink) WriteFile(name string, data []byte, mode fs.FileMode) error {
+	s.files[name] = string(data)
+	return nil
+}
+
+func (s *writeOnlySink) Mkdir(name string) error {
+	s.dirs = append(s.dirs, name)
+	return nil
+}
+
+func TestGenerateTo(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: sinks
+files:
+  - source: main.go.tmpl
+    type: file
+  - source: main_link.go
+    type: file
+`,
+		"main.go.tmpl": "package {{ .ProjectName }}\n",
+	})
+	tmpl, err := manager.GetTemplate(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("main.go.tmpl", filepath.Join(tmpl.LocalPath, "main_link.go")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	generator := newTestGenerator(t, manager)
+	vars := map[string]interface{}{"ProjectName": "app"}
+
+	t.Run("disk", func(t *testing.T) {
+		root := t.TempDir()
+		stats, err := generator.GenerateTo(name, vars, NewDiskSink(root))
+		if err != nil {
+			t.Fatalf("GenerateTo() error = %v", err)
+		}
+		if len(stats.generatedFiles()) == 0 {
+			t.Error("GenerateTo() reported no generated files")
+		}
+		if data, err := os.ReadFile(filepath.Join(root, "main.go")); err != nil || string(data) != "package app\n" {
+			t.Errorf("main.go = %q, %v", data, err)
+		}
+		if _, err := os.Readlink(filepath.Join(root, "main_link.go")); err != nil {
+			t.Errorf("main_link.go is not a symlink: %v", err)
+		}
+	})
+
+	t.Run("write-only sink", func(t *testing.T) {
+		out := &writeOnlySink{files: map[string]string{}}
+		if _, err := generator.GenerateTo(name, vars, out); err != nil {
+			t.Fatalf("GenerateTo() error = %v", err)
+		}
+		if got := out.files["main.go"]; got != "package app\n" {
+			t.Errorf("main.go = %q", got)
+		}
+		// 不支援連結的 Sink 會收到展開後的內容
+		if _, ok := out.files["main_link.go"]; !ok {
+			t.Errorf("main_link.go was not expanded, got files %v", out.files)
+		}
+	})
+}
+
+func TestParseFileMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    fs.FileMode
+		wantErr bool
+	}{
+		{value: "0644", want: 0o644},
+		{value: "600", want: 0o600},
+		{value: "0o755", want: 0o755},
+		{value: " 0700 ", want: 0o700},
+		{value: "0999", wantErr: true},
+		{value: "01777", wantErr: true},
+		{value: "rw-r--r--", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseFileMode(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFileMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFileMode() = %o, want %o", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *TemplateConfig
+		fileMode  fs.FileMode
+		dirMode   fs.FileMode
+		wantFile  fs.FileMode
+		wantDir   fs.FileMode
+		wantError string
+	}{
+		{name: "defaults", config: &TemplateConfig{}, wantFile: 0o644, wantDir: 0o755},
+		{name: "no config", wantFile: 0o644, wantDir: 0o755},
+		{name: "template modes", config: &TemplateConfig{FileMode: "0600", DirMode: "0700"}, wantFile: 0o600, wantDir: 0o700},
+		{
+			name:     "generator overrides template",
+			config:   &TemplateConfig{FileMode: "0600", DirMode: "0700"},
+			fileMode: 0o640, dirMode: 0o750,
+			wantFile: 0o640, wantDir: 0o750,
+		},
+		{name: "only file mode set", config: &TemplateConfig{DirMode: "0700"}, fileMode: 0o600, wantFile: 0o600, wantDir: 0o700},
+		{name: "invalid fileMode", config: &TemplateConfig{FileMode: "abc"}, wantError: "fileMode"},
+		{name: "invalid dirMode", config: &TemplateConfig{DirMode: "0888"}, wantError: "dirMode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := &Generator{FileMode: tt.fileMode, DirMode: tt.dirMode}
+			fileMode, dirMode, err := generator.permissions(tt.config)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("permissions() error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("permissions() error = %v", err)
+			}
+			if fileMode != tt.wantFile || dirMode != tt.wantDir {
+				t.Errorf("permissions() = %o, %o; want %o, %o", fileMode, dirMode, tt.wantFile, tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestGenerateFileModes(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: private
+fileMode: "0600"
+dirMode: "0700"
+files:
+  - source: config
+    target: config
+`,
+		"config/secret.env": "TOKEN=x\n",
+	})
+	generator := newTestGenerator(t, manager)
+
+	if _, err := generator.Generate("app", name); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	info, err := os.Stat(filepath.Join(generator.WorkDir, "app", "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o700 {
+		t.Errorf("config directory mode = %o, want 700", info.Mode().Perm())
+	}
+
+	// 既有檔案的權限較寬，重新產生時也要收緊
+	existing := filepath.Join(generator.WorkDir, "app", "config", "secret.env")
+	if err := os.Chmod(existing, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	generator.Force = true
+	if _, err := generator.Generate("app", name); err != nil {
+		t.Fatalf("Generate() with Force error = %v", err)
+	}
+	info, err = os.Stat(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("secret.env mode = %o, want 600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,71 @@
+package template
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed skeleton
+var embeddedSkeleton embed.FS
+
+const skeletonNamePlaceholder = "__TEMPLATE_NAME__"
+
+// CreateTemplateSkeleton 在用戶模板目錄下建立一個新的起始模板，回傳其路徑
+func (m *Manager) CreateTemplateSkeleton(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if !isValidTemplateName(name) {
+		return "", fmt.Errorf("invalid template name: %q", name)
+	}
+
+	templatesDir, err := UserTemplatesDir()
+	if err != nil {
+		return "", err
+	}
+
+	targetPath := filepath.Join(templatesDir, name)
+	if _, err := os.Stat(targetPath); err == nil {
+		return "", fmt.Errorf("template directory already exists: %s", targetPath)
+	}
+
+	skeleton, err := fs.Sub(embeddedSkeleton, "skeleton")
+	if err != nil {
+		return "", err
+	}
+
+	replace := func(content []byte) []byte {
+		return []byte(strings.ReplaceAll(string(content), skeletonNamePlaceholder, name))
+	}
+	if err := exportFS(skeleton, targetPath, replace); err != nil {
+		return "", fmt.Errorf("failed to write template skeleton: %w", err)
+	}
+
+	return targetPath, nil
+}
+
+// exportFS 將 fsys 的內容寫入 dst 目錄，transform 可在寫入前改寫檔案內容
+func exportFS(fsys fs.FS, dst string, transform func([]byte) []byte) error {
+	return fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		targetPath := filepath.Join(dst, filepath.FromSlash(path))
+		if d.IsDir() {
+			return os.MkdirAll(targetPath, 0o755)
+		}
+
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		if transform != nil {
+			content = transform(content)
+		}
+
+		return os.WriteFile(targetPath, content, 0o644)
+	})
+}
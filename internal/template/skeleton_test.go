@@ -0,0 +1,83 @@
+package template
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateTemplateSkeleton(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		existing bool
+		wantErr  string
+	}{
+		{name: "new template", template: "my-api"},
+		{name: "trimmed name", template: "  my-api  "},
+		{name: "empty name", template: " ", wantErr: "invalid template name"},
+		{name: "path separator", template: "a/b", wantErr: "invalid template name"},
+		{name: "parent directory", template: "..", wantErr: "invalid template name"},
+		{name: "already exists", template: "my-api", existing: true, wantErr: "already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			if tt.existing {
+				if _, err := manager.CreateTemplateSkeleton(tt.template); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			path, err := manager.CreateTemplateSkeleton(tt.template)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CreateTemplateSkeleton() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateTemplateSkeleton() error = %v", err)
+			}
+			templatesDir, err := UserTemplatesDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != filepath.Join(templatesDir, "my-api") {
+				t.Errorf("path = %s, want it under %s", path, templatesDir)
+			}
+
+			for _, file := range []string{"template.yaml", "README.md", "project/README.md.tmpl"} {
+				data, err := os.ReadFile(filepath.Join(path, filepath.FromSlash(file)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if strings.Contains(string(data), skeletonNamePlaceholder) {
+					t.Errorf("%s still contains %s", file, skeletonNamePlaceholder)
+				}
+			}
+
+			// 起始模板本身必須通過驗證並可直接產生
+			if problems, err := ValidateTemplate(path); err != nil || len(problems) != 0 {
+				t.Fatalf("ValidateTemplate() = %v, %v", problems, err)
+			}
+			if err := manager.loadUserTemplates(); err != nil {
+				t.Fatal(err)
+			}
+			output, err := newTestGenerator(t, manager).GenerateFS("my-api", map[string]interface{}{"ProjectName": "demo"})
+			if err != nil {
+				t.Fatalf("GenerateFS() error = %v", err)
+			}
+			data, err := fs.ReadFile(output, "README.md")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "# demo\n\nGenerated from the my-api template.\n"; string(data) != want {
+				t.Errorf("README.md = %q, want %q", data, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// GeneratedFile 為產生的一個檔案（相對於專案根目錄、以 / 分隔）；Kind 為 template（渲染的 .tmpl）、
+// copy（原樣複製）、symlink 或 readme（--gen-readme 產生）
+type GeneratedFile struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+}
+
+// CommandResult 為一個 post-generate 命令的執行結果；ExitCode 在命令無法啟動時為 -1
+type CommandResult struct {
+	Command  string
+	WorkDir  string
+	ExitCode int
+	Duration time.Duration
+}
+
+// RunSummary 為 --summary-json 寫出（--json 時輸出到 stdout）的執行結果，著重於這次執行（與記錄變數的 manifest 互補）
+type RunSummary struct {
+	Template string `json:"template"`
+	Version  string `json:"version"`
+	// Deprecated 只在以 --allow-deprecated 使用已淘汰的模板時出現
+	Deprecated         bool             `json:"deprecated,omitempty"`
+	DeprecationMessage string           `json:"deprecationMessage,omitempty"`
+	ProjectName        string           `json:"projectName"`
+	ProjectPath        string           `json:"projectPath"`
+	Counts             SummaryCounts    `json:"counts"`
+	Files              []GeneratedFile  `json:"files"`
+	Commands           []CommandSummary `json:"commands"`
+	GeneratedAt        time.Time        `json:"generatedAt"`
+	// Variables 只在 --print-vars 時出現
+	Variables map[string]ResolvedVariable `json:"variables,omitempty"`
+}
+
+// SummaryCounts 為 GenerateStats 的計數（與 --count 顯示的內容相同）
+type SummaryCounts struct {
+	Created            int `json:"created"`
+	SkippedByRule      int `json:"skippedByRule"`
+	SkippedByCondition int `json:"skippedByCondition"`
+	Excluded           int `json:"excluded"`
+	CommandsRun        int `json:"commandsRun"`
+}
+
+// CommandSummary 為 RunSummary 中的一個命令，耗時以毫秒表示
+type CommandSummary struct {
+	Command    string `json:"command"`
+	WorkDir    string `json:"workDir"`
+	ExitCode   int    `json:"exitCode"`
+	DurationMS int64  `json:"durationMs"`
+}
+
+// generatedFiles 依路徑排序回傳產生的檔案與種類
+func (s GenerateStats) generatedFiles() []GeneratedFile {
+	files := make([]GeneratedFile, 0, len(s.kinds))
+	for path, kind := range s.kinds {
+		files = append(files, GeneratedFile{Path: path, Kind: kind})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+// Summary 回傳這次產生的執行結果
+func (r *GenerateResult) Summary() (*RunSummary, error) {
+	projectPath, err := filepath.Abs(r.ProjectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path: %w", err)
+	}
+
+	summary := &RunSummary{
+		Template:           r.Template,
+		Version:            r.Version,
+		ProjectName:        r.ProjectName,
+		ProjectPath:        projectPath,
+		Deprecated:         r.Deprecated,
+		DeprecationMessage: r.DeprecationMessage,
+		Counts: SummaryCounts{
+			Created:            r.Stats.FilesCreated,
+			SkippedByRule:      r.Stats.SkippedByRule,
+			SkippedByCondition: r.Stats.SkippedByCondition,
+			Excluded:           r.Stats.Excluded,
+			CommandsRun:        r.Stats.CommandsRun,
+		},
+		Files:       append([]GeneratedFile{}, r.Files...),
+		Commands:    make([]CommandSummary, 0, len(r.Commands)),
+		Variables:   r.Variables,
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, command := range r.Commands {
+		summary.Commands = append(summary.Commands, CommandSummary{
+			Command:    command.Command,
+			WorkDir:    command.WorkDir,
+			ExitCode:   command.ExitCode,
+			DurationMS: command.Duration.Milliseconds(),
+		})
+	}
+	return summary, nil
+}
+
+// WriteSummary 將 Summary 以縮排的 JSON 寫入 path
+func (r *GenerateResult) WriteSummary(path string) error {
+	summary, err := r.Summary()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,64 @@
+package template
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteSummary(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: summary
+version: 1.2.0
+postGenerate:
+  - command: "true"
+    workDir: cmd
+`,
+		"README.md.tmpl": "# {{ .ProjectName }}\n",
+		"cmd/main.go":    "package main\n",
+	})
+	generator := newTestGenerator(t, manager)
+
+	var result *GenerateResult
+	var err error
+	captureStdout(t, func() { result, err = generator.Generate("app", name) })
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := result.WriteSummary(path); err != nil {
+		t.Fatalf("WriteSummary() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary RunSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("summary is not valid JSON: %v\n%s", err, data)
+	}
+
+	if summary.Template != "summary" || summary.Version != "1.2.0" || summary.ProjectName != "app" {
+		t.Errorf("summary = %+v", summary)
+	}
+	if want := filepath.Join(generator.WorkDir, "app"); summary.ProjectPath != want {
+		t.Errorf("projectPath = %s, want %s", summary.ProjectPath, want)
+	}
+	wantFiles := []GeneratedFile{{Path: "README.md", Kind: "template"}, {Path: "cmd/main.go", Kind: "copy"}}
+	if !reflect.DeepEqual(summary.Files, wantFiles) {
+		t.Errorf("files = %+v, want %+v", summary.Files, wantFiles)
+	}
+	if len(summary.Commands) != 1 || summary.Commands[0].Command != "true" || summary.Commands[0].WorkDir != filepath.Join(summary.ProjectPath, "cmd") || summary.Commands[0].ExitCode != 0 {
+		t.Errorf("commands = %+v", summary.Commands)
+	}
+	if summary.GeneratedAt.IsZero() {
+		t.Error("generatedAt is not set")
+	}
+	if summary.Variables != nil {
+		t.Errorf("variables = %v, want none without PrintVars", summary.Variables)
+	}
+}
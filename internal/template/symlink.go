@@ -0,0 +1,83 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolveSymlink 讀取 linkPath 的連結目標，並確認其最終位置仍位於 root 之內。
+// 回傳的 target 為相對於連結所在目錄的路徑（絕對連結會被轉換為相對路徑），
+// resolved 則為完全解析後的實際路徑。
+func resolveSymlink(root, linkPath string) (target string, resolved string, err error) {
+	target, err = os.Readlink(linkPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	rootReal, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", "", err
+	}
+
+	dest := target
+	if !filepath.IsAbs(dest) {
+		dest = filepath.Join(filepath.Dir(linkPath), dest)
+	}
+
+	resolved, err = filepath.EvalSymlinks(dest)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve symlink %s: %w", linkPath, err)
+	}
+
+	if !isWithinDir(rootReal, resolved) {
+		return "", "", fmt.Errorf("symlink %s points outside the template: %s", linkPath, target)
+	}
+
+	if filepath.IsAbs(target) {
+		linkDir, err := filepath.EvalSymlinks(filepath.Dir(linkPath))
+		if err != nil {
+			return "", "", err
+		}
+		target, err = filepath.Rel(linkDir, resolved)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return target, resolved, nil
+}
+
+// checkSymlinkCycle 避免將指向自身祖先目錄的連結展開成無限遞迴。
+func checkSymlinkCycle(linkPath, resolved string) error {
+	linkDir, err := filepath.EvalSymlinks(filepath.Dir(linkPath))
+	if err != nil {
+		return err
+	}
+	if isWithinDir(resolved, linkDir) {
+		return fmt.Errorf("symlink %s points to one of its parent directories", linkPath)
+	}
+	return nil
+}
+
+func createSymlink(target, dstPath string) error {
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0o755); err != nil {
+		return err
+	}
+	// 重新產生時取代既有的檔案或連結
+	if info, err := os.Lstat(dstPath); err == nil && !info.IsDir() {
+		if err := os.Remove(dstPath); err != nil {
+			return err
+		}
+	}
+	return os.Symlink(target, dstPath)
+}
+
+func isWithinDir(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
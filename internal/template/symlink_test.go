@@ -0,0 +1,171 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCopyDirSymlinks(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		links       map[string]string
+		materialize bool
+		wantErr     string
+		want        map[string]string
+		wantLinks   []string
+	}{
+		{
+			name:      "links are preserved",
+			files:     map[string]string{"b/f.txt": "f"},
+			links:     map[string]string{"a": "b"},
+			want:      map[string]string{"a/f.txt": "f"},
+			wantLinks: []string{"a"},
+		},
+		{
+			name:        "nested link to a sibling of the expanded directory",
+			files:       map[string]string{"b/f.txt": "f", "c/g.txt": "g"},
+			links:       map[string]string{"a": "b", "b/sib": "../c/g.txt"},
+			materialize: true,
+			want:        map[string]string{"a/f.txt": "f", "a/sib": "g"},
+		},
+		{
+			name:        "nested link leaving the template root",
+			files:       map[string]string{"b/f.txt": "f", "../outside/secret": "s"},
+			links:       map[string]string{"a": "b", "b/out": "../../outside/secret"},
+			materialize: true,
+			wantErr:     "points outside the template",
+		},
+		{
+			name:        "directories linking to each other",
+			files:       map[string]string{"x/f.txt": "f", "y/g.txt": "g"},
+			links:       map[string]string{"x/l": "../y", "y/l": "../x"},
+			materialize: true,
+			wantErr:     "already being copied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+			src := filepath.Join(base, "src")
+			writeFiles(t, src, tt.files)
+			for link, target := range tt.links {
+				if err := os.Symlink(target, filepath.Join(src, link)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			dst := filepath.Join(base, "dst")
+			err := copyDir(src, dst, tt.materialize)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("copyDir() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("copyDir() error = %v", err)
+			}
+			for name, want := range tt.want {
+				data, err := os.ReadFile(filepath.Join(dst, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != want {
+					t.Errorf("%s = %q, want %q", name, data, want)
+				}
+			}
+			for _, name := range tt.wantLinks {
+				info, err := os.Lstat(filepath.Join(dst, name))
+				if err != nil || info.Mode()&os.ModeSymlink == 0 {
+					t.Errorf("%s is not a symlink (err = %v)", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSymlinks(t *testing.T) {
+	tests := []struct {
+		name       string
+		links      map[string]string
+		noSymlinks bool
+		wantErr    string
+		// want 為讀取產生結果（跟隨連結）後的內容；wantLinks 為應保留的連結與其目標
+		want      map[string]string
+		wantLinks map[string]string
+	}{
+		{
+			name:      "links are recreated",
+			links:     map[string]string{"latest.conf.tmpl": "app.conf.tmpl", "docs": "shared"},
+			want:      map[string]string{"latest.conf": "name=app\n", "docs/guide.md": "# guide\n"},
+			wantLinks: map[string]string{"latest.conf": "app.conf", "docs": "shared"},
+		},
+		{
+			name:       "NoSymlinks expands links",
+			links:      map[string]string{"latest.conf.tmpl": "app.conf.tmpl", "docs": "shared"},
+			noSymlinks: true,
+			want:       map[string]string{"latest.conf": "name=app\n", "docs/guide.md": "# guide\n"},
+		},
+		{
+			name:    "link leaving the template",
+			links:   map[string]string{"secret": "../../outside.txt"},
+			wantErr: "points outside the template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml":   "name: linked\n",
+				"app.conf.tmpl":   "name={{ .ProjectName }}\n",
+				"shared/guide.md": "# guide\n",
+			})
+			tmpl, err := manager.GetTemplate(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeFiles(t, filepath.Dir(filepath.Dir(tmpl.LocalPath)), map[string]string{"outside.txt": "secret"})
+			for link, target := range tt.links {
+				if err := os.Symlink(target, filepath.Join(tmpl.LocalPath, link)); err != nil {
+					t.Skipf("symlinks unsupported: %v", err)
+				}
+			}
+			generator := newTestGenerator(t, manager)
+			generator.NoSymlinks = tt.noSymlinks
+
+			var result *GenerateResult
+			captureStdout(t, func() { result, err = generator.Generate("app", name) })
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			for path, want := range tt.want {
+				data, err := os.ReadFile(filepath.Join(result.ProjectDir, filepath.FromSlash(path)))
+				if err != nil || string(data) != want {
+					t.Errorf("%s = %q, %v; want %q", path, data, err, want)
+				}
+			}
+			for _, path := range []string{"latest.conf", "docs"} {
+				target, err := os.Readlink(filepath.Join(result.ProjectDir, path))
+				if want, ok := tt.wantLinks[path]; ok {
+					if err != nil || target != want {
+						t.Errorf("%s links to %q (%v), want %q", path, target, err, want)
+					}
+				} else if err == nil {
+					t.Errorf("%s is a symlink to %s, want it expanded", path, target)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package template
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"aaa-generator/internal/console"
+)
+
+// shellBuiltins 為不需要在 PATH 上尋找的 shell 內建命令
+var shellBuiltins = map[string]bool{
+	"cd": true, "echo": true, "export": true, "exit": true, "true": true, "false": true,
+	"set": true, "test": true, "[": true, "source": true, ".": true, "printf": true,
+	"exec": true, "if": true, "then": true, "else": true, "fi": true, "for": true, "do": true, "done": true,
+}
+
+// toolHints 為常見工具缺少時的安裝建議
+var toolHints = map[string]string{
+	"go":    "install Go from https://go.dev/dl/",
+	"gofmt": "install Go from https://go.dev/dl/",
+	"node":  "install Node.js from https://nodejs.org/",
+	"npm":   "install Node.js from https://nodejs.org/",
+	"npx":   "install Node.js from https://nodejs.org/",
+	"yarn":  "install it with npm install -g yarn",
+	"pnpm":  "install it with npm install -g pnpm",
+	"git":   "install Git from https://git-scm.com/",
+	"make":  "install make with your system package manager",
+}
+
+// commandWords 粗略擷取 shell 命令中每個片段（以 &&、||、;、| 分隔）實際執行的程式名稱，
+// 略過前置的 VAR=value、shell 內建命令、路徑與含變數展開的字詞
+func commandWords(command string) []string {
+	replacer := strings.NewReplacer("&&", "\n", "||", "\n", ";", "\n", "|", "\n", "(", "\n", ")", "\n")
+
+	var words []string
+	for _, segment := range strings.Split(replacer.Replace(command), "\n") {
+		for _, word := range strings.Fields(segment) {
+			if strings.Contains(word, "=") && !strings.HasPrefix(word, "=") {
+				continue
+			}
+			word = strings.Trim(word, `"'`)
+			if word != "" && !shellBuiltins[word] && !strings.ContainsAny(word, "/$`{}<>") && !contains(words, word) {
+				words = append(words, word)
+			}
+			break
+		}
+	}
+	return words
+}
+
+// checkPostCommandTools 在產生檔案前檢查 post-generate 命令用到的工具是否在 PATH 上。
+// 預設只顯示警告；Strict 時回傳 ErrMissingTool，在建立任何檔案前中止
+func (g *Generator) checkPostCommandTools(config *TemplateConfig, vars map[string]interface{}) error {
+	if config == nil {
+		return nil
+	}
+
+	var missing []string
+	for _, command := range config.PostGenerate {
+		if !matchesOS(command.OS) {
+			continue
+		}
+		for _, word := range commandWords(g.processCommandTemplate(command.Command, vars)) {
+			if contains(missing, word) {
+				continue
+			}
+			if _, err := exec.LookPath(word); err != nil {
+				missing = append(missing, word)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	problems := make([]string, 0, len(missing))
+	for _, tool := range missing {
+		problem := fmt.Sprintf("%s not found in PATH, post-generate commands using it will fail", tool)
+		if hint := toolHints[tool]; hint != "" {
+			problem = fmt.Sprintf("%s not found in PATH; %s", tool, hint)
+		}
+		problems = append(problems, problem)
+	}
+	g.trace("tools", map[string]interface{}{"missing": missing})
+
+	if g.Strict {
+		return newDetailError(ErrMissingTool, "post-generate commands need missing tools: %s", strings.Join(problems, "; "))
+	}
+	for _, problem := range problems {
+		g.status().Printf(console.Warning("⚠️  %s\n"), problem)
+	}
+	return nil
+}
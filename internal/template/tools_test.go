@@ -0,0 +1,105 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandWords(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{command: "go mod tidy", want: []string{"go"}},
+		{command: "npm install && npm run build", want: []string{"npm"}},
+		{command: "cd web; pnpm i || yarn", want: []string{"pnpm", "yarn"}},
+		{command: "CGO_ENABLED=0 go build ./...", want: []string{"go"}},
+		{command: "cat go.mod | grep module", want: []string{"cat", "grep"}},
+		{command: "(cd api && make)", want: []string{"make"}},
+		{command: "'git' init", want: []string{"git"}},
+		{command: "./scripts/setup.sh && $TOOL run && bin/tool", want: nil},
+		{command: "echo done", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := commandWords(tt.command); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commandWords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPostCommandTools(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []PostCommand
+		strict   bool
+		// want 為各警告應包含的文字；空白表示不應有警告
+		want []string
+	}{
+		{name: "tools present", commands: []PostCommand{{Command: "go mod tidy"}}},
+		{name: "missing with hint", commands: []PostCommand{{Command: "npm install"}}, want: []string{"npm not found in PATH; install Node.js"}},
+		{name: "missing without hint", commands: []PostCommand{{Command: "buf generate"}}, want: []string{"buf not found in PATH, post-generate commands"}},
+		{name: "reported once", commands: []PostCommand{{Command: "npm i"}, {Command: "npm test"}}, want: []string{"npm not found"}},
+		{name: "rendered before lookup", commands: []PostCommand{{Command: "{{ .Tool }} version"}}, want: []string{"terraform not found"}},
+		{name: "other OS skipped", commands: []PostCommand{{Command: "npm install", OS: []string{"windows"}}}},
+		{name: "strict", commands: []PostCommand{{Command: "npm install"}, {Command: "git init"}}, strict: true, want: []string{"npm not found", "git not found"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeTools(t, "go")
+			setTargetOS(t, "linux")
+			generator := NewGenerator(nil)
+			generator.Strict = tt.strict
+			config := &TemplateConfig{PostGenerate: tt.commands}
+
+			var err error
+			output := captureStdout(t, func() {
+				err = generator.checkPostCommandTools(config, map[string]interface{}{"Tool": "terraform"})
+			})
+			if tt.strict {
+				if !errors.Is(err, ErrMissingTool) {
+					t.Fatalf("checkPostCommandTools() error = %v, want ErrMissingTool", err)
+				}
+				output = err.Error()
+			} else if err != nil {
+				t.Fatalf("checkPostCommandTools() error = %v", err)
+			}
+
+			if len(tt.want) == 0 && output != "" {
+				t.Errorf("unexpected warnings:\n%s", output)
+			}
+			for _, want := range tt.want {
+				if strings.Count(output, want) != 1 {
+					t.Errorf("output should mention %q once, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateStrictMissingTool(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": "name: tools\npostGenerate:\n  - command: npm install\n",
+		"main.go":       "package main\n",
+	})
+	fakeTools(t)
+	generator := newTestGenerator(t, manager)
+	generator.Strict = true
+
+	var err error
+	captureStdout(t, func() { _, err = generator.Generate("app", name) })
+	if !errors.Is(err, ErrMissingTool) {
+		t.Fatalf("Generate() error = %v, want ErrMissingTool", err)
+	}
+	if _, err := os.Stat(filepath.Join(generator.WorkDir, "app")); !os.IsNotExist(err) {
+		t.Error("project directory was created before the tool check failed")
+	}
+}
@@ -0,0 +1,38 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// trace 在設定 Generator.Trace 時寫入一行 JSON 記錄，欄位 "event" 標示事件種類
+func (g *Generator) trace(event string, fields map[string]interface{}) {
+	if g.Trace == nil {
+		return
+	}
+
+	entry := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		entry[key] = value
+	}
+	entry["event"] = event
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data = []byte(fmt.Sprintf(`{"event":"trace-error","error":%q}`, err.Error()))
+	}
+	g.Trace.Write(append(data, '\n'))
+}
+
+// traceVariable 記錄變數值的來源（builtin、set、default 或 prompt），供 trace 與 PrintVars 使用；
+// secret 變數在 trace 中只寫出遮罩
+func (g *Generator) traceVariable(name string, value interface{}, source string, secret bool) {
+	if g.varSources == nil {
+		g.varSources = make(map[string]string)
+	}
+	g.varSources[name] = source
+	if secret {
+		value = secretMask
+	}
+	g.trace("variable", map[string]interface{}{"name": name, "value": value, "source": source})
+}
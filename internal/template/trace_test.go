@@ -0,0 +1,166 @@
+package template
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// traceEvents 解析 JSON Lines 的 trace 輸出
+func traceEvents(t *testing.T, data []byte) []map[string]interface{} {
+	t.Helper()
+	var events []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var event map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("trace line is not JSON: %v\n%s", err, scanner.Text())
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestTrace(t *testing.T) {
+	tests := []struct {
+		name   string
+		event  string
+		fields map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{name: "fields", event: "rule", fields: map[string]interface{}{"path": "a.go", "matched": true}, want: map[string]interface{}{"event": "rule", "path": "a.go", "matched": true}},
+		{name: "event field wins", event: "write", fields: map[string]interface{}{"event": "other"}, want: map[string]interface{}{"event": "write"}},
+		{name: "unencodable value", event: "rule", fields: map[string]interface{}{"bad": make(chan int)}, want: map[string]interface{}{"event": "trace-error"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			generator := &Generator{Trace: &buf}
+			generator.trace(tt.event, tt.fields)
+
+			events := traceEvents(t, buf.Bytes())
+			if len(events) != 1 {
+				t.Fatalf("got %d trace lines, want 1:\n%s", len(events), buf.String())
+			}
+			for key, want := range tt.want {
+				if events[0][key] != want {
+					t.Errorf("%s = %v, want %v", key, events[0][key], want)
+				}
+			}
+		})
+	}
+
+	// 未設定 Trace 時不做任何事
+	(&Generator{}).trace("rule", nil)
+}
+
+func TestGenerateTrace(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: traced
+variables:
+  - name: Port
+    default: "8080"
+  - name: Docs
+    type: bool
+    default: "false"
+files:
+  - source: main.go.tmpl
+    type: file
+  - source: docs
+    target: docs
+    condition: '{{ eq .Docs "true" }}'
+postGenerate:
+  - command: "true"
+`,
+		"main.go.tmpl":  "// port {{ .Port }}\n",
+		"docs/index.md": "# docs\n",
+		"unmatched.txt": "x\n",
+	})
+	var buf bytes.Buffer
+	generator := newTestGenerator(t, manager)
+	generator.Trace = &buf
+	generator.Values = map[string]string{"Docs": "false"}
+
+	captureStdout(t, func() {
+		if _, err := generator.Generate("app", name); err != nil {
+			t.Errorf("Generate() error = %v", err)
+		}
+	})
+
+	var got []string
+	for _, event := range traceEvents(t, buf.Bytes()) {
+		switch event["event"] {
+		case "variable":
+			got = append(got, "variable "+event["name"].(string)+"="+event["source"].(string))
+		case "condition":
+			got = append(got, "condition "+event["source"].(string))
+		case "write":
+			got = append(got, "write "+event["target"].(string))
+		case "command":
+			got = append(got, "command "+event["command"].(string))
+		case "rule":
+			if event["matched"] == false && event["path"] == "unmatched.txt" {
+				got = append(got, "unmatched "+event["path"].(string))
+			}
+		}
+	}
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{"variable ProjectName=builtin", "variable Port=default", "variable Docs=set", "condition docs", "write main.go", "unmatched unmatched.txt", "command true"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("trace is missing %q, got:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "write docs/index.md") {
+		t.Errorf("trace records a write for a disabled rule:\n%s", joined)
+	}
+}
+
+func TestTraceSecretVariable(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml": `name: traced
+variables:
+  - name: Token
+    secret: true
+  - name: APIKey
+    secret: true
+    default: s3cret
+  - name: Region
+    default: eu
+`,
+		"config.txt.tmpl": "{{ .Token }} {{ .APIKey }} {{ .Region }}\n",
+	})
+	var buf bytes.Buffer
+	generator := newTestGenerator(t, manager)
+	generator.Trace = &buf
+	generator.Values = map[string]string{"Token": "hunter2"}
+
+	captureStdout(t, func() {
+		if _, err := generator.Generate("app", name); err != nil {
+			t.Errorf("Generate() error = %v", err)
+		}
+	})
+
+	// 以 --set 提供或取自預設值的 secret 變數都只寫出遮罩
+	for _, secret := range []string{"hunter2", "s3cret"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("trace contains the secret value %q:\n%s", secret, buf.String())
+		}
+	}
+	values := make(map[string]interface{})
+	for _, event := range traceEvents(t, buf.Bytes()) {
+		if event["event"] == "variable" {
+			values[event["name"].(string)] = event["value"]
+		}
+	}
+	want := map[string]interface{}{"Token": secretMask, "APIKey": secretMask, "Region": "eu"}
+	for name, value := range want {
+		if values[name] != value {
+			t.Errorf("%s = %v, want %v", name, values[name], value)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// UpdateCheck 為 CheckUpdates 對單一已安裝模板的結果；Err 為查詢來源時的錯誤，不影響其他模板的檢查
+type UpdateCheck struct {
+	Name      string
+	Source    string
+	Installed string
+	Latest    string
+	Available bool
+	Err       error
+}
+
+// CheckUpdates 對每個記錄了安裝來源的用戶模板重新下載來源並比較 template.yaml 的 version，
+// 回報是否有較新的版本；不會安裝任何內容。git 來源檢查預設分支，不受安裝時指定的 @ref 限制
+func (m *Manager) CheckUpdates() []UpdateCheck {
+	names := make([]string, 0, len(m.userTemplates))
+	for name := range m.userTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var checks []UpdateCheck
+	for _, name := range names {
+		tmpl := m.userTemplates[name]
+		info, err := LoadInstallInfo(tmpl.LocalPath)
+		if err != nil {
+			checks = append(checks, UpdateCheck{Name: name, Installed: tmpl.Config.Version, Err: err})
+			continue
+		}
+		if info == nil || info.Source == "" {
+			continue
+		}
+
+		check := UpdateCheck{Name: name, Source: info.Source, Installed: tmpl.Config.Version}
+		check.Latest, check.Err = m.latestVersion(info)
+		check.Available = check.Err == nil && check.Latest != "" && compareVersions(check.Latest, check.Installed) > 0
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// latestVersion 讀取安裝來源目前的模板版本：已註冊 scheme 與 git 來源下載到暫存目錄，本機來源直接讀取
+func (m *Manager) latestVersion(info *InstallInfo) (string, error) {
+	source, _ := splitSubdir(info.Source)
+
+	fetcher, ok := m.fetcherFor(source)
+	if !ok && isGitSource(source) {
+		fetcher = m.git
+		source, _ = parseGitSource(source)
+	}
+	if fetcher == nil {
+		return templateVersion(source)
+	}
+
+	dir, err := os.MkdirTemp("", "aaa-generator-update-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := fetcher.Fetch(source, dir); err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", source, err)
+	}
+
+	root := dir
+	if info.Subdir != "" {
+		root = filepath.Join(dir, filepath.FromSlash(info.Subdir))
+		if !isWithinDir(dir, root) {
+			return "", fmt.Errorf("subdirectory %q escapes the fetched template", info.Subdir)
+		}
+	} else if root, err = findTemplateRoot(dir); err != nil {
+		return "", err
+	}
+	return templateVersion(root)
+}
+
+// templateVersion 回傳 dir 中模板設定檔的 version
+func templateVersion(dir string) (string, error) {
+	fsys := os.DirFS(dir)
+	_, data, err := findConfigFile(fsys)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template config: %w", err)
+	}
+	config, err := parseTemplateConfig(data, fsys)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template config: %w", err)
+	}
+	return config.Version, nil
+}
@@ -0,0 +1,145 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingFetcher 在下載時回傳錯誤
+type failingFetcher struct{}
+
+func (failingFetcher) Fetch(source, dst string) error {
+	return errors.New("registry unavailable")
+}
+
+func TestCheckUpdates(t *testing.T) {
+	const v1 = "name: tpl\nversion: 1.0.0\n"
+
+	tests := []struct {
+		name string
+		// setup 安裝模板並模擬來源的變化
+		setup         func(t *testing.T, manager *Manager)
+		want          UpdateCheck
+		wantErr       string
+		wantNoResults bool
+	}{
+		{
+			name: "local source is newer",
+			setup: func(t *testing.T, manager *Manager) {
+				source := filepath.Join(t.TempDir(), "tpl")
+				writeFiles(t, source, map[string]string{"template.yaml": v1})
+				mustInstall(t, manager, source)
+				writeFiles(t, source, map[string]string{"template.yaml": "name: tpl\nversion: 1.1.0\n"})
+			},
+			want: UpdateCheck{Name: "tpl", Installed: "1.0.0", Latest: "1.1.0", Available: true},
+		},
+		{
+			name: "local source unchanged",
+			setup: func(t *testing.T, manager *Manager) {
+				source := filepath.Join(t.TempDir(), "tpl")
+				writeFiles(t, source, map[string]string{"template.yaml": v1})
+				mustInstall(t, manager, source)
+			},
+			want: UpdateCheck{Name: "tpl", Installed: "1.0.0", Latest: "1.0.0"},
+		},
+		{
+			name: "older source is not an update",
+			setup: func(t *testing.T, manager *Manager) {
+				source := filepath.Join(t.TempDir(), "tpl")
+				writeFiles(t, source, map[string]string{"template.yaml": "name: tpl\nversion: 2.0.0\n"})
+				mustInstall(t, manager, source)
+				writeFiles(t, source, map[string]string{"template.yaml": v1})
+			},
+			want: UpdateCheck{Name: "tpl", Installed: "2.0.0", Latest: "1.0.0"},
+		},
+		{
+			name: "fetched subdirectory source",
+			setup: func(t *testing.T, manager *Manager) {
+				fetcher := &treeFetcher{t: t, files: map[string]string{"tpl/template.yaml": v1}}
+				manager.RegisterFetcher("fake", fetcher)
+				mustInstall(t, manager, "fake://registry/templates:latest//tpl")
+				fetcher.files = map[string]string{"tpl/template.yaml": "name: tpl\nversion: 1.2.0\n"}
+			},
+			want: UpdateCheck{Name: "tpl", Installed: "1.0.0", Latest: "1.2.0", Available: true},
+		},
+		{
+			name: "fetch failure",
+			setup: func(t *testing.T, manager *Manager) {
+				manager.RegisterFetcher("fake", &treeFetcher{t: t, files: map[string]string{"template.yaml": v1}})
+				mustInstall(t, manager, "fake://registry/tpl:latest")
+				manager.RegisterFetcher("fake", failingFetcher{})
+			},
+			want:    UpdateCheck{Name: "tpl", Installed: "1.0.0"},
+			wantErr: "registry unavailable",
+		},
+		{
+			name: "local source removed",
+			setup: func(t *testing.T, manager *Manager) {
+				source := filepath.Join(t.TempDir(), "tpl")
+				writeFiles(t, source, map[string]string{"template.yaml": v1})
+				mustInstall(t, manager, source)
+				if err := os.RemoveAll(source); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:    UpdateCheck{Name: "tpl", Installed: "1.0.0"},
+			wantErr: "template config",
+		},
+		{
+			name: "no recorded source",
+			setup: func(t *testing.T, manager *Manager) {
+				if _, err := manager.CreateTemplateSkeleton("tpl"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantNoResults: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			tt.setup(t, manager)
+			if err := manager.loadUserTemplates(); err != nil {
+				t.Fatal(err)
+			}
+
+			checks := manager.CheckUpdates()
+			if tt.wantNoResults {
+				if len(checks) != 0 {
+					t.Errorf("CheckUpdates() = %+v, want no results", checks)
+				}
+				return
+			}
+			if len(checks) != 1 {
+				t.Fatalf("CheckUpdates() = %+v, want one result", checks)
+			}
+			got := checks[0]
+			if tt.wantErr != "" {
+				if got.Err == nil || !strings.Contains(got.Err.Error(), tt.wantErr) {
+					t.Errorf("Err = %v, want %q", got.Err, tt.wantErr)
+				}
+			} else if got.Err != nil {
+				t.Errorf("Err = %v", got.Err)
+			}
+			if got.Source == "" {
+				t.Error("Source is empty")
+			}
+			got.Source, got.Err = "", nil
+			if got != tt.want {
+				t.Errorf("CheckUpdates() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// mustInstall 安裝 source，失敗時中止測試
+func mustInstall(t *testing.T, manager *Manager, source string) {
+	t.Helper()
+	if _, err := manager.InstallTemplate(source); err != nil {
+		t.Fatalf("InstallTemplate(%s) error = %v", source, err)
+	}
+}
@@ -0,0 +1,204 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// plainVersion 比對 minGeneratorVersion 允許的版本格式，例如 "1.4.0" 或 "v1.4"
+var plainVersion = regexp.MustCompile(`^v?[0-9]+(\.[0-9]+)*$`)
+
+// envName 比對 requiredEnv 中的環境變數名稱
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateTemplate 檢查模板目錄（或模板設定檔）的設定，回傳所有發現的問題。
+// 先以 JSON Schema 檢查結構，結構正確後再進行語意檢查。
+func ValidateTemplate(location string) ([]string, error) {
+	configPath := location
+	var configData []byte
+	if info, err := os.Stat(location); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		name, data, err := findConfigFile(os.DirFS(location))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template config: %w", err)
+		}
+		configPath, configData = filepath.Join(location, name), data
+	} else if configData, err = os.ReadFile(configPath); err != nil {
+		return nil, fmt.Errorf("failed to read template config: %w", err)
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal(configData, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse template config: %w", err)
+	}
+
+	problems, err := validateSchema(document)
+	if err != nil || len(problems) > 0 {
+		return problems, err
+	}
+
+	// 缺少或無法解析的 imports 片段視為設定問題，而非讀取錯誤
+	config, err := parseTemplateConfig(configData, os.DirFS(filepath.Dir(configPath)))
+	if err != nil {
+		return []string{fmt.Sprintf("imports: %v", err)}, nil
+	}
+
+	return validateConfig(config), nil
+}
+
+// validateConfig 進行 schema 無法表達的語意檢查
+func validateConfig(config *TemplateConfig) []string {
+	var problems []string
+
+	if strings.TrimSpace(config.Name) == "" {
+		problems = append(problems, "name: must not be empty")
+	}
+
+	declaredAt := make(map[string]int, len(config.Variables))
+	for i, variable := range config.Variables {
+		at := fmt.Sprintf("variables[%d]", i)
+		if strings.TrimSpace(variable.Name) == "" {
+			problems = append(problems, at+".name: must not be empty")
+		} else if first, exists := declaredAt[variable.Name]; exists {
+			problems = append(problems, fmt.Sprintf("%s.name: %q is already declared by variables[%d]", at, variable.Name, first))
+		} else {
+			declaredAt[variable.Name] = i
+		}
+		if variable.OptionsFrom != "" && variable.Type != "select" {
+			problems = append(problems, at+".optionsFrom: only select variables can list options with a command")
+		}
+		if variable.OptionsFile != "" && variable.Type != "select" {
+			problems = append(problems, at+".optionsFile: only select variables can read options from a file")
+		}
+		if variable.OptionsFrom != "" && variable.OptionsFile != "" {
+			problems = append(problems, at+": optionsFrom and optionsFile cannot be used together")
+		}
+		if variable.Type == "select" && variable.OptionsFrom == "" && variable.OptionsFile == "" {
+			if len(variable.Options) == 0 {
+				problems = append(problems, at+".options: select variables need at least one option")
+			} else if variable.Default != "" && !contains(variable.Options, variable.Default) {
+				problems = append(problems, fmt.Sprintf("%s.default: %q is not one of %v", at, variable.Default, variable.Options))
+			}
+		}
+		if variable.RequiredIf != "" {
+			if _, err := template.New("requiredIf").Funcs(templateFuncs()).Parse(variable.RequiredIf); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.requiredIf: %v", at, err))
+			}
+		}
+		if variable.Transform != "" {
+			if _, err := transformValue(variable, variable.Default); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.transform: %v", at, err))
+			}
+		}
+	}
+
+	seenArgs := make(map[string]bool)
+	for i, name := range config.ArgsOrder {
+		at := fmt.Sprintf("argsOrder[%d]", i)
+		if _, declared := findVariable(config, name); !declared && name != projectNameArg {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a declared variable", at, name))
+		}
+		if seenArgs[name] {
+			problems = append(problems, fmt.Sprintf("%s: %q is listed more than once", at, name))
+		}
+		seenArgs[name] = true
+	}
+
+	if config.FileMode != "" {
+		if _, err := ParseFileMode(config.FileMode); err != nil {
+			problems = append(problems, fmt.Sprintf("fileMode: %v", err))
+		}
+	}
+	if config.DirMode != "" {
+		if _, err := ParseFileMode(config.DirMode); err != nil {
+			problems = append(problems, fmt.Sprintf("dirMode: %v", err))
+		}
+	}
+
+	if config.Header != "" {
+		if _, err := template.New("header").Funcs(templateFuncs()).Parse(config.Header); err != nil {
+			problems = append(problems, fmt.Sprintf("header: %v", err))
+		} else if len(config.HeaderExtensions) == 0 {
+			problems = append(problems, "headerExtensions: header is set but no extensions are listed, so it is never applied")
+		}
+	}
+
+	if config.DataCommand != "" {
+		if _, err := template.New("dataCommand").Funcs(templateFuncs()).Parse(config.DataCommand); err != nil {
+			problems = append(problems, fmt.Sprintf("dataCommand: %v", err))
+		}
+	}
+
+	for i, name := range config.RequiredEnv {
+		if !envName.MatchString(name) {
+			problems = append(problems, fmt.Sprintf("requiredEnv[%d]: %q is not a valid environment variable name", i, name))
+		}
+	}
+
+	if config.MinGeneratorVersion != "" && !plainVersion.MatchString(config.MinGeneratorVersion) {
+		problems = append(problems, fmt.Sprintf("minGeneratorVersion: %q is not a version like 1.4.0", config.MinGeneratorVersion))
+	}
+
+	for i, rule := range config.Files {
+		at := fmt.Sprintf("files[%d]", i)
+		if strings.TrimSpace(rule.Source) == "" {
+			problems = append(problems, at+".source: must not be empty")
+		}
+		if rule.Type == "glob" {
+			for _, segment := range strings.Split(rule.Source, "/") {
+				if _, err := path.Match(segment, ""); err != nil {
+					problems = append(problems, fmt.Sprintf("%s.source: invalid glob pattern %q", at, rule.Source))
+					break
+				}
+			}
+		}
+		if rule.Condition != "" {
+			if _, err := template.New("condition").Funcs(templateFuncs()).Parse(rule.Condition); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.condition: %v", at, err))
+			}
+		}
+		for old, value := range rule.Replace {
+			if old == "" {
+				problems = append(problems, at+".replace: keys must not be empty")
+			} else if _, err := template.New("replace").Funcs(templateFuncs()).Parse(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.replace[%q]: %v", at, old, err))
+			}
+		}
+	}
+
+	for i, include := range config.Includes {
+		at := fmt.Sprintf("include[%d]", i)
+		if strings.TrimSpace(include.Template) == "" {
+			problems = append(problems, at+".template: must not be empty")
+		}
+		if strings.TrimSpace(include.Source) == "" {
+			problems = append(problems, at+".source: must not be empty")
+		}
+	}
+
+	for i, command := range config.PostGenerate {
+		if strings.TrimSpace(command.Command) == "" {
+			problems = append(problems, fmt.Sprintf("postGenerate[%d].command: must not be empty", i))
+		}
+	}
+
+	for i, command := range config.Validate {
+		at := fmt.Sprintf("validate[%d]", i)
+		if strings.TrimSpace(command.Command) == "" {
+			problems = append(problems, at+".command: must not be empty")
+		}
+		if command.Stage != "" && command.Stage != validateBefore && command.Stage != validateAfter {
+			problems = append(problems, fmt.Sprintf("%s.stage: %q must be %s or %s", at, command.Stage, validateBefore, validateAfter))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,166 @@
+package template
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		files  map[string]string
+		// want 為各問題應包含的文字；空白表示設定有效
+		want []string
+	}{
+		{
+			name: "valid",
+			config: `name: ok
+variables:
+  - name: DB
+    type: select
+    options: [postgres, none]
+    default: none
+files:
+  - source: "src/**/*.go"
+    type: glob
+postGenerate:
+  - command: go mod tidy
+`,
+		},
+		{name: "missing name", config: "version: 1.0.0\n", want: []string{`(root): missing required field "name"`}},
+		{name: "unknown field", config: "name: t\nvariablez: []\n", want: []string{"variablez: unknown field"}},
+		{name: "wrong type", config: "name: t\ntags: go\n", want: []string{"tags: expected array, got string"}},
+		{name: "enum", config: "name: t\nvariables:\n  - name: X\n    type: text\n", want: []string{"variables[0].type: value text is not one of"}},
+		{name: "empty name", config: "name: ' '\n", want: []string{"name: must not be empty"}},
+		{
+			name:   "duplicate variable",
+			config: "name: t\nvariables:\n  - name: Port\n  - name: Port\n",
+			want:   []string{`variables[1].name: "Port" is already declared by variables[0]`},
+		},
+		{
+			name:   "select without options",
+			config: "name: t\nvariables:\n  - name: DB\n    type: select\n",
+			want:   []string{"variables[0].options: select variables need at least one option"},
+		},
+		{
+			name:   "select default not an option",
+			config: "name: t\nvariables:\n  - name: DB\n    type: select\n    options: [pg]\n    default: mysql\n",
+			want:   []string{`variables[0].default: "mysql" is not one of [pg]`},
+		},
+		{
+			name:   "option sources on non-select",
+			config: "name: t\nvariables:\n  - name: A\n    optionsFrom: ls\n  - name: B\n    optionsFile: list.txt\n  - name: C\n    type: select\n    optionsFrom: ls\n    optionsFile: list.txt\n",
+			want: []string{
+				"variables[0].optionsFrom: only select variables",
+				"variables[1].optionsFile: only select variables",
+				"variables[2]: optionsFrom and optionsFile cannot be used together",
+			},
+		},
+		{
+			name:   "unparsable expressions",
+			config: "name: t\nvariables:\n  - name: A\n    requiredIf: '{{ if }}'\n  - name: B\n    transform: '{{ . | nosuch }}'\nheader: '{{ .X'\ndataCommand: '{{ end }}'\nfiles:\n  - source: a\n    condition: '{{ .X'\n    replace:\n      old: '{{ .Y'\n",
+			want:   []string{"variables[0].requiredIf:", "variables[1].transform:", "header:", "dataCommand:", "files[0].condition:", `files[0].replace["old"]:`},
+		},
+		{
+			name:   "argsOrder",
+			config: "name: t\nvariables:\n  - name: Variant\nargsOrder: [ProjectName, Variant, Missing, Variant]\n",
+			want:   []string{`argsOrder[2]: "Missing" is not a declared variable`, `argsOrder[3]: "Variant" is listed more than once`},
+		},
+		{
+			name:   "modes, env and version",
+			config: "name: t\nfileMode: '0999'\ndirMode: rwx\nrequiredEnv: [API_TOKEN, 9BAD]\nminGeneratorVersion: latest\n",
+			want:   []string{"fileMode: invalid permission mode", "dirMode: invalid permission mode", `requiredEnv[1]: "9BAD"`, `minGeneratorVersion: "latest" is not a version`},
+		},
+		{name: "header without extensions", config: "name: t\nheader: Copyright\n", want: []string{"headerExtensions: header is set but no extensions are listed"}},
+		{name: "invalid glob", config: "name: t\nfiles:\n  - source: 'src/[/*.go'\n    type: glob\n", want: []string{`files[0].source: invalid glob pattern`}},
+		{
+			name:   "empty commands and sources",
+			config: "name: t\nfiles:\n  - source: ' '\ninclude:\n  - template: ' '\n    source: ' '\npostGenerate:\n  - command: ' '\nvalidate:\n  - command: ' '\n",
+			want:   []string{"files[0].source: must not be empty", "include[0].template: must not be empty", "include[0].source: must not be empty", "postGenerate[0].command: must not be empty", "validate[0].command: must not be empty"},
+		},
+		{name: "missing import", config: "name: t\nimports: [shared.yaml]\n", want: []string{`imports: import "shared.yaml": fragment not found`}},
+		{
+			name:   "imported duplicate is merged, not reported",
+			config: "name: t\nimports: [shared.yaml]\nvariables:\n  - name: Port\n",
+			files:  map[string]string{"shared.yaml": "variables:\n  - name: Port\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"template.yaml": tt.config})
+			writeFiles(t, dir, tt.files)
+
+			problems, err := ValidateTemplate(dir)
+			if err != nil {
+				t.Fatalf("ValidateTemplate() error = %v", err)
+			}
+			if len(tt.want) == 0 {
+				if len(problems) != 0 {
+					t.Fatalf("ValidateTemplate() = %q, want no problems", problems)
+				}
+				return
+			}
+			got := strings.Join(problems, "\n")
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("problems missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTemplateLocation(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"yml/template.yml":     "name: yml\n",
+		"file/custom.yaml":     "name: file\n",
+		"broken/template.yaml": "name: [\n",
+		"empty/README.md":      "no config\n",
+	})
+
+	tests := []struct {
+		name     string
+		location string
+		wantErr  string
+	}{
+		{name: "alternate config name", location: "yml"},
+		{name: "config file path", location: "file/custom.yaml"},
+		{name: "unparsable YAML", location: "broken", wantErr: "failed to parse template config"},
+		{name: "no config file", location: "empty", wantErr: "failed to read template config"},
+		{name: "missing location", location: "missing", wantErr: "no such file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := ValidateTemplate(filepath.Join(dir, tt.location))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ValidateTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || len(problems) != 0 {
+				t.Fatalf("ValidateTemplate() = %q, %v; want valid", problems, err)
+			}
+		})
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(ConfigSchema(), &schema); err != nil {
+		t.Fatalf("embedded schema is not JSON: %v", err)
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, field := range []string{"name", "variables", "files", "postGenerate", "include", "validate"} {
+		if _, ok := properties[field]; !ok {
+			t.Errorf("schema has no %q property", field)
+		}
+	}
+}
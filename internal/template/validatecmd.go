@@ -0,0 +1,84 @@
+package template
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aaa-generator/internal/console"
+)
+
+// 驗證命令相對於 post-generate 命令的執行階段
+const (
+	validateBefore = "before"
+	validateAfter  = "after"
+)
+
+// ValidateCommand 為模板自訂的驗證命令（例如 go build ./...），對產生的專案執行；非零結束碼會讓產生失敗。
+// Stage 為 before 或 after（預設），表示在 post-generate 命令之前或之後執行
+type ValidateCommand struct {
+	Command string   `yaml:"command"`
+	WorkDir string   `yaml:"workDir"`
+	Stage   string   `yaml:"stage"`
+	OS      []string `yaml:"os"`
+}
+
+func (c ValidateCommand) stage() string {
+	if c.Stage == "" {
+		return validateAfter
+	}
+	return c.Stage
+}
+
+// shouldValidate 回傳這次產生是否要執行驗證命令：Generator.Validate 或模板的 alwaysValidate: true
+func (g *Generator) shouldValidate(config *TemplateConfig) bool {
+	return g.Validate || (config != nil && config.AlwaysValidate)
+}
+
+// runValidation 在 projectDir 執行指定階段的驗證命令；第一個失敗的命令連同其輸出回傳 ErrValidationFailed
+func (g *Generator) runValidation(config *TemplateConfig, projectDir string, vars map[string]interface{}, stage string) error {
+	if !g.shouldValidate(config) {
+		return nil
+	}
+
+	var commands []ValidateCommand
+	for _, command := range config.Validate {
+		if command.stage() == stage && matchesOS(command.OS) {
+			commands = append(commands, command)
+		}
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+
+	g.status().Println("🔄 Running validation commands...")
+	for i, command := range commands {
+		cmdStr := g.processCommandTemplate(command.Command, vars)
+		workDir := filepath.Join(projectDir, command.WorkDir)
+
+		g.status().Printf("   • [%d/%d] Validating: %s", i+1, len(commands), cmdStr)
+		var output bytes.Buffer
+		cmd := exec.Command("sh", "-c", cmdStr)
+		cmd.Dir = workDir
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+
+		started := time.Now()
+		err := cmd.Run()
+		elapsed := time.Since(started).Round(100 * time.Millisecond)
+		g.trace("validate", map[string]interface{}{"command": cmdStr, "workDir": workDir, "stage": stage, "exitCode": cmd.ProcessState.ExitCode(), "success": err == nil})
+		if err != nil {
+			g.status().Println()
+			detail := ""
+			if text := strings.TrimSpace(output.String()); text != "" {
+				detail = "\n      " + strings.ReplaceAll(text, "\n", "\n      ")
+			}
+			return newDetailError(ErrValidationFailed, "validation command failed: %s: %v%s", cmdStr, err, detail)
+		}
+		g.status().Printf(console.Success(" ✅ (%s)\n"), elapsed)
+	}
+	g.status().Println(console.Success("✅ Validation passed"))
+	return nil
+}
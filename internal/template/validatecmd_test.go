@@ -0,0 +1,95 @@
+package template
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunValidation(t *testing.T) {
+	const post = "postGenerate:\n  - command: echo post >> ../steps.log\n"
+
+	tests := []struct {
+		name     string
+		config   string
+		validate bool
+		// wantSteps 為各命令依序寫入 steps.log 的內容
+		wantSteps string
+		wantErr   []string
+	}{
+		{
+			name:      "not requested",
+			config:    "validate:\n  - command: echo after >> ../steps.log\n" + post,
+			wantSteps: "post\n",
+		},
+		{
+			name:      "stages around post-generate",
+			config:    "validate:\n  - command: echo after >> ../steps.log\n  - command: echo before >> ../steps.log\n    stage: before\n" + post,
+			validate:  true,
+			wantSteps: "before\npost\nafter\n",
+		},
+		{
+			name:      "alwaysValidate",
+			config:    "alwaysValidate: true\nvalidate:\n  - command: echo {{ .ProjectName }} >> ../../steps.log\n    workDir: cmd\n" + post,
+			wantSteps: "post\napp\n",
+		},
+		{
+			name:      "other OS skipped",
+			config:    "validate:\n  - command: echo windows >> ../steps.log\n    os: [windows]\n" + post,
+			validate:  true,
+			wantSteps: "post\n",
+		},
+		{
+			name:      "failure before post-generate",
+			config:    "validate:\n  - command: echo broken build; exit 3\n    stage: before\n" + post,
+			validate:  true,
+			wantSteps: "",
+			wantErr:   []string{"validation command failed: echo broken build; exit 3", "exit status 3", "broken build"},
+		},
+		{
+			name:      "stops at the first failure",
+			config:    "validate:\n  - command: \"false\"\n  - command: echo after >> ../steps.log\n" + post,
+			validate:  true,
+			wantSteps: "post\n",
+			wantErr:   []string{"validation command failed: false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			name := installTestTemplate(t, manager, map[string]string{
+				"template.yaml": "name: checked\n" + tt.config,
+				"cmd/main.go":   "package main\n",
+			})
+			setTargetOS(t, "linux")
+			generator := newTestGenerator(t, manager)
+			generator.Validate = tt.validate
+
+			var err error
+			captureStdout(t, func() { _, err = generator.Generate("app", name) })
+			if len(tt.wantErr) > 0 {
+				if !errors.Is(err, ErrValidationFailed) {
+					t.Fatalf("Generate() error = %v, want ErrValidationFailed", err)
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error should contain %q, got: %v", want, err)
+					}
+				}
+			} else if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(generator.WorkDir, "steps.log"))
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if string(data) != tt.wantSteps {
+				t.Errorf("steps = %q, want %q", data, tt.wantSteps)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package template
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VariableHelp 輸出模板中單一變數的完整說明：類型、是否必填、預設值（含 defaults.env）、選項、說明與 transform。
+// 變數名稱不分大小寫；模板未宣告此變數時回傳 ErrUnknownVariable
+func (m *Manager) VariableHelp(out io.Writer, templateName, name string) error {
+	tmpl, err := m.GetTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	config, err := withEnvDefaults(tmpl)
+	if err != nil {
+		return err
+	}
+
+	var declared []string
+	if config != nil {
+		for _, variable := range config.Variables {
+			if strings.EqualFold(variable.Name, name) {
+				printVariableHelp(out, variable)
+				return nil
+			}
+			declared = append(declared, variable.Name)
+		}
+	}
+
+	if len(declared) == 0 {
+		return newDetailError(ErrUnknownVariable, "template '%s' does not declare any variables", templateName)
+	}
+	return newDetailError(ErrUnknownVariable, "template '%s' has no variable '%s' (declared: %s)", templateName, name, strings.Join(declared, ", "))
+}
+
+func printVariableHelp(out io.Writer, variable TemplateVar) {
+	varType := variable.Type
+	if varType == "" {
+		varType = "string"
+	}
+	required := "no"
+	if variable.Required {
+		required = "yes"
+	}
+
+	fmt.Fprintf(out, "📝 %s\n", variable.Name)
+	if variable.Description != "" {
+		fmt.Fprintf(out, "   %s\n", variable.Description)
+	}
+	fmt.Fprintf(out, "   • Type:     %s\n", varType)
+	fmt.Fprintf(out, "   • Required: %s\n", required)
+	if variable.RequiredIf != "" {
+		fmt.Fprintf(out, "   • Required if: %s\n", variable.RequiredIf)
+	}
+	switch {
+	case variable.Default == "":
+		fmt.Fprintln(out, "   • Default:  (none)")
+	case variable.Secret:
+		fmt.Fprintf(out, "   • Default:  %s\n", secretMask)
+	default:
+		fmt.Fprintf(out, "   • Default:  %q\n", variable.Default)
+	}
+	if variable.OptionsFrom != "" {
+		fmt.Fprintf(out, "   • Options:  output of `%s`\n", variable.OptionsFrom)
+	} else if variable.OptionsFile != "" {
+		fmt.Fprintf(out, "   • Options:  listed in %s\n", variable.OptionsFile)
+	} else if len(variable.Options) > 0 {
+		fmt.Fprintf(out, "   • Options:  %s\n", strings.Join(variable.Options, ", "))
+	}
+	if variable.Transform != "" {
+		fmt.Fprintf(out, "   • Transform: %s\n", variable.Transform)
+	}
+	if variable.Secret {
+		fmt.Fprintln(out, "   • Secret:   value is masked in --print-vars output")
+	}
+}
@@ -0,0 +1,100 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVariableHelp(t *testing.T) {
+	const config = `name: documented
+variables:
+  - name: Port
+    description: HTTP port
+    required: true
+  - name: DBType
+    type: select
+    default: postgres
+    options: [postgres, mysql]
+  - name: DBName
+    requiredIf: '{{ ne .DBType "none" }}'
+  - name: Region
+    optionsFrom: aws regions
+  - name: Team
+    optionsFile: teams.txt
+  - name: Package
+    transform: snake
+  - name: Token
+    secret: true
+    default: hunter2
+`
+
+	tests := []struct {
+		name     string
+		variable string
+		want     []string
+		notWant  []string
+		wantErr  string
+	}{
+		{
+			name:     "defaults.env fills the default",
+			variable: "Port",
+			want:     []string{"📝 Port", "HTTP port", "Type:     string", "Required: yes", `Default:  "9090"`},
+		},
+		{name: "case-insensitive name", variable: "dbtype", want: []string{"📝 DBType", "Type:     select", "Required: no", `Default:  "postgres"`, "Options:  postgres, mysql"}},
+		{name: "requiredIf", variable: "DBName", want: []string{`Required if: {{ ne .DBType "none" }}`, "Default:  (none)"}},
+		{name: "options from a command", variable: "Region", want: []string{"Options:  output of `aws regions`"}},
+		{name: "options from a file", variable: "Team", want: []string{"Options:  listed in teams.txt"}},
+		{name: "transform", variable: "Package", want: []string{"Transform: snake"}},
+		{name: "secret default is masked", variable: "Token", want: []string{"Default:  " + secretMask, "Secret:   value is masked"}, notWant: []string{"hunter2"}},
+		{name: "unknown variable", variable: "Nope", wantErr: "has no variable 'Nope' (declared: Port, DBType, DBName, Region, Team, Package, Token)"},
+	}
+
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{
+		"template.yaml":  config,
+		"defaults.env":   "Port=9090\n",
+		"teams.txt":      "core\n",
+		"README.md.tmpl": "# {{ .ProjectName }}\n",
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := manager.VariableHelp(&out, name, tt.variable)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrUnknownVariable) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("VariableHelp() error = %v, want ErrUnknownVariable containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VariableHelp() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output should contain %q, got:\n%s", want, out.String())
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out.String(), notWant) {
+					t.Errorf("output should not contain %q, got:\n%s", notWant, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestVariableHelpNoVariables(t *testing.T) {
+	manager := newTestManager(t)
+	name := installTestTemplate(t, manager, map[string]string{"template.yaml": "name: bare\n"})
+
+	err := manager.VariableHelp(&bytes.Buffer{}, name, "Port")
+	if !errors.Is(err, ErrUnknownVariable) || !strings.Contains(err.Error(), "does not declare any variables") {
+		t.Fatalf("VariableHelp() error = %v, want ErrUnknownVariable for a template without variables", err)
+	}
+	if err := manager.VariableHelp(&bytes.Buffer{}, "missing", "Port"); err == nil {
+		t.Error("VariableHelp() for an unknown template succeeded")
+	}
+}
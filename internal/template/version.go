@@ -0,0 +1,73 @@
+package template
+
+import (
+	"strconv"
+	"strings"
+)
+
+// compareVersions 比較以點分隔的版本號（可帶 v 前綴，例如 "v1.10.0"），回傳 -1、0 或 1。
+// 數字部分依數值比較，非數字部分（例如 "-beta" 後綴）依字串比較；缺少的部分視為 0
+func compareVersions(a, b string) int {
+	left := strings.Split(strings.TrimPrefix(strings.TrimSpace(a), "v"), ".")
+	right := strings.Split(strings.TrimPrefix(strings.TrimSpace(b), "v"), ".")
+
+	for i := 0; i < len(left) || i < len(right); i++ {
+		l, r := "0", "0"
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+
+		ln, lerr := strconv.Atoi(l)
+		rn, rerr := strconv.Atoi(r)
+		switch {
+		case lerr == nil && rerr == nil && ln != rn:
+			if ln < rn {
+				return -1
+			}
+			return 1
+		case (lerr != nil || rerr != nil) && l != r:
+			return strings.Compare(l, r)
+		}
+	}
+	return 0
+}
+
+// versionCore 去除版本號的 v 前綴與 -/+ 之後的附加資訊，例如 "v1.2.0-abc123f" → "1.2.0"
+func versionCore(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	return version
+}
+
+// isReleaseVersion 回傳 version 是否為正式版本號；"dev" 或未標記 tag 的 v0.0.0 建置視為開發版本
+func isReleaseVersion(version string) bool {
+	core := versionCore(version)
+	if core == "" || compareVersions(core, "0.0.0") == 0 {
+		return false
+	}
+	for _, part := range strings.Split(core, ".") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// checkGeneratorVersion 在模板要求的 minGeneratorVersion 高於目前版本時回傳 ErrGeneratorTooOld；
+// 開發版本不檢查，以免本機建置無法使用新模板
+func (g *Generator) checkGeneratorVersion(tmpl *Template, templateName string) error {
+	if tmpl.Config == nil || tmpl.Config.MinGeneratorVersion == "" || !isReleaseVersion(g.Version) {
+		return nil
+	}
+
+	if compareVersions(versionCore(g.Version), versionCore(tmpl.Config.MinGeneratorVersion)) < 0 {
+		return newDetailError(ErrGeneratorTooOld, "template '%s' requires aaa-generator %s or newer (this is %s); please upgrade the generator",
+			templateName, tmpl.Config.MinGeneratorVersion, g.Version)
+	}
+	return nil
+}
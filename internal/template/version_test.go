@@ -0,0 +1,93 @@
+package template
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.0.0", b: "1.0.0", want: 0},
+		{a: "v1.2.0", b: "1.2.0", want: 0},
+		{a: "1.10.0", b: "1.9.0", want: 1},
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "1.2", b: "1.2.1", want: -1},
+		{a: "2.0.0", b: "10.0.0", want: -1},
+		{a: "1.0.0-beta", b: "1.0.0-alpha", want: 1},
+		{a: "", b: "0.1.0", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			if got := compareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsReleaseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "1.4.0", want: true},
+		{version: "v1.4.0-3-gabc123f", want: true},
+		{version: "v2.0.0+dirty", want: true},
+		{version: "dev", want: false},
+		{version: "", want: false},
+		{version: "v0.0.0-20260101-abc123f", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := isReleaseVersion(tt.version); got != tt.want {
+				t.Errorf("isReleaseVersion(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckGeneratorVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		minVersion string
+		version    string
+		wantErr    bool
+	}{
+		{name: "no requirement", version: "1.0.0"},
+		{name: "newer generator", minVersion: "1.4", version: "v1.10.0"},
+		{name: "same version", minVersion: "v1.4.0", version: "1.4.0-rc1"},
+		{name: "older generator", minVersion: "1.4.0", version: "1.3.9", wantErr: true},
+		{name: "development build", minVersion: "9.0.0", version: "dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			config := "name: versioned\n"
+			if tt.minVersion != "" {
+				config += "minGeneratorVersion: " + quoteYAML(tt.minVersion) + "\n"
+			}
+			name := installTestTemplate(t, manager, map[string]string{"template.yaml": config, "main.go": "package main\n"})
+			generator := newTestGenerator(t, manager)
+			generator.Version = tt.version
+
+			// Generate、Plan 與 GenerateFS 都要在開始前檢查
+			_, generateErr := generator.Generate("app", name)
+			_, planErr := generator.Plan("app", name)
+			_, fsErr := generator.GenerateFS(name, map[string]interface{}{"ProjectName": "app"})
+			for call, err := range map[string]error{"Generate": generateErr, "Plan": planErr, "GenerateFS": fsErr} {
+				if tt.wantErr && !errors.Is(err, ErrGeneratorTooOld) {
+					t.Errorf("%s() error = %v, want ErrGeneratorTooOld", call, err)
+				}
+				if !tt.wantErr && err != nil {
+					t.Errorf("%s() error = %v", call, err)
+				}
+			}
+		})
+	}
+}